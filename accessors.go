@@ -0,0 +1,80 @@
+package airly
+
+// PM1 returns PM1 concentration in µg/m³, false if it's not reported
+func (m Measurement) PM1() (float64, bool) { return m.Value(PM1) }
+
+// PM25 returns PM2.5 concentration in µg/m³, false if it's not reported
+func (m Measurement) PM25() (float64, bool) { return m.Value(PM25) }
+
+// PM10 returns PM10 concentration in µg/m³, false if it's not reported
+func (m Measurement) PM10() (float64, bool) { return m.Value(PM10) }
+
+// NO2 returns nitrogen dioxide concentration in µg/m³, false if it's not reported. Gases are reported by MAINS
+// and sensor-v3 installations only
+func (m Measurement) NO2() (float64, bool) { return m.Value(NO2) }
+
+// O3 returns ozone concentration in µg/m³, false if it's not reported
+func (m Measurement) O3() (float64, bool) { return m.Value(O3) }
+
+// SO2 returns sulfur dioxide concentration in µg/m³, false if it's not reported
+func (m Measurement) SO2() (float64, bool) { return m.Value(SO2) }
+
+// CO returns carbon monoxide concentration in µg/m³, false if it's not reported
+func (m Measurement) CO() (float64, bool) { return m.Value(CO) }
+
+// Temperature returns temperature in °C, false if it's not reported
+func (m Measurement) Temperature() (float64, bool) { return m.Value(Temperature) }
+
+// Humidity returns relative humidity in %, false if it's not reported
+func (m Measurement) Humidity() (float64, bool) { return m.Value(Humidity) }
+
+// Pressure returns pressure in hPa, false if it's not reported
+func (m Measurement) Pressure() (float64, bool) { return m.Value(Pressure) }
+
+// Quantity is value with its unit
+type Quantity struct {
+	Value float64 `json:"value"`
+	Unit  string  `json:"unit"`
+}
+
+// Units maps value names to units
+type Units map[string]string
+
+// DefaultUnits are units of values reported by Airly, used when metadata is not available
+var DefaultUnits = Units{
+	PM1:         "µg/m³",
+	PM25:        "µg/m³",
+	PM10:        "µg/m³",
+	NO2:         "µg/m³",
+	O3:          "µg/m³",
+	SO2:         "µg/m³",
+	CO:          "µg/m³",
+	C6H6:        "µg/m³",
+	Temperature: "°C",
+	Humidity:    "%",
+	Pressure:    "hPa",
+}
+
+// UnitsOf returns units of measurement types, e.g. returned by Client.MeasurementTypes, so units of new value
+// types are known without changes of this package
+func UnitsOf(types []MeasurementType) Units {
+	u := Units{}
+	for _, t := range types {
+		u[t.Name] = t.Unit
+	}
+	return u
+}
+
+// Quantity returns value with given name and its unit, units missing in u are taken from DefaultUnits. False is
+// returned if value is not reported
+func (u Units) Quantity(m Measurement, name string) (Quantity, bool) {
+	v, ok := m.Value(name)
+	if !ok {
+		return Quantity{}, false
+	}
+	unit, ok := u[name]
+	if !ok {
+		unit = DefaultUnits[name]
+	}
+	return Quantity{Value: v, Unit: unit}, true
+}
@@ -0,0 +1,36 @@
+package airly
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestAccessors(t *testing.T) {
+	m := Measurement{Values: []Value{{Name: PM25, Value: 18.7}, {Name: NO2, Value: 21}, {Name: O3, Value: 40},
+		{Name: SO2, Value: 3}, {Name: CO, Value: 300}, {Name: PM10, Null: true}, {Name: Temperature, Value: -2},
+		{Name: Humidity, Value: 80}, {Name: Pressure, Value: 1013}, {Name: PM1, Value: 10}}}
+	for _, tc := range []struct {
+		accessor func() (float64, bool)
+		value    float64
+		ok       bool
+	}{
+		{m.PM1, 10, true}, {m.PM25, 18.7, true}, {m.PM10, 0, false}, {m.NO2, 21, true}, {m.O3, 40, true},
+		{m.SO2, 3, true}, {m.CO, 300, true}, {m.Temperature, -2, true}, {m.Humidity, 80, true}, {m.Pressure, 1013, true},
+	} {
+		v, ok := tc.accessor()
+		assert.Equal(t, tc.value, v)
+		assert.Equal(t, tc.ok, ok)
+	}
+}
+
+func TestUnits(t *testing.T) {
+	m := Measurement{Values: []Value{{Name: NO2, Value: 21}, {Name: "NH3", Value: 5}, {Name: PM10, Null: true}}}
+	u := UnitsOf([]MeasurementType{{Name: "NH3", Label: "NH₃", Unit: "ppb"}})
+	q, ok := u.Quantity(m, "NH3")
+	assert.True(t, ok)
+	assert.Equal(t, Quantity{Value: 5, Unit: "ppb"}, q)
+	q, _ = u.Quantity(m, NO2)
+	assert.Equal(t, Quantity{Value: 21, Unit: "µg/m³"}, q)
+	_, ok = Units(nil).Quantity(m, PM10)
+	assert.False(t, ok)
+}
@@ -2,10 +2,20 @@
 package airly
 
 import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 )
 
@@ -17,6 +27,8 @@ type Installation struct {
 	Elevation float64  `json:"elevation"`
 	Airly     bool     `json:"airly"`
 	Sponsor   Sponsor  `json:"sponsor"`
+	// Source of data, set by FallbackProvider
+	Source string `json:"source,omitempty"`
 }
 
 // Location represents geographical location given by coordinates, used for Nearest* APIs
@@ -46,17 +58,88 @@ type Sponsor struct {
 	DisplayName string `json:"displayName"`
 }
 
+// SponsorPolicy defines what sponsor data is kept in installations returned by Client
+type SponsorPolicy int
+
+const (
+	// SponsorKeep keeps all sponsor data, it's the default
+	SponsorKeep SponsorPolicy = iota
+	// SponsorNameOnly keeps only sponsor name and display name
+	SponsorNameOnly
+	// SponsorStrip removes all sponsor data
+	SponsorStrip
+)
+
+// Apply returns installation with sponsor data limited according to policy
+func (p SponsorPolicy) Apply(i Installation) Installation {
+	switch p {
+	case SponsorNameOnly:
+		i.Sponsor = Sponsor{Name: i.Sponsor.Name, DisplayName: i.Sponsor.DisplayName}
+	case SponsorStrip:
+		i.Sponsor = Sponsor{}
+	}
+	return i
+}
+
 // Measurements data, see https://developer.airly.org/docs#endpoints.measurements
 type Measurements struct {
 	Current  Measurement   `json:"current"`
 	History  []Measurement `json:"history"`
 	Forecast []Measurement `json:"forecast"`
+	// Source of data, set by FallbackProvider
+	Source string `json:"source,omitempty"`
 }
 
 // Value of measurement
 type Value struct {
 	Name  string  `json:"name"`
 	Value float64 `json:"value"`
+	// Null is set if sensor reported no reading (JSON null), Value is 0 then and must not be used. Use
+	// Measurement.Value to get only actual readings
+	Null bool `json:"-"`
+}
+
+// UnmarshalJSON sets Null for null or missing value
+func (v *Value) UnmarshalJSON(data []byte) error {
+	var j struct {
+		Name  string          `json:"name"`
+		Value json.RawMessage `json:"value"`
+	}
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	*v = Value{Name: j.Name}
+	if len(j.Value) == 0 || string(j.Value) == "null" {
+		v.Null = true
+		return nil
+	}
+	if err := json.Unmarshal(j.Value, &v.Value); err != nil {
+		// offsets of errors returned by Unmarshaler are relative to it, so the value is included in message instead
+		return fmt.Errorf("invalid value %s of %s: %w", j.Value, j.Name, err)
+	}
+	return nil
+}
+
+// MarshalJSON encodes Null value as null
+func (v Value) MarshalJSON() ([]byte, error) {
+	j := struct {
+		Name  string   `json:"name"`
+		Value *float64 `json:"value"`
+	}{Name: v.Name}
+	if !v.Null {
+		j.Value = &v.Value
+	}
+	return json.Marshal(j)
+}
+
+// Value returns reading with given name, false is returned if there is no such value or it's Null
+func (m Measurement) Value(name string) (float64, bool) {
+	for _, v := range m.Values {
+		if v.Name == name {
+			return v.Value, !v.Null
+		}
+	}
+	return 0, false
 }
 
 // Index showing aggregated air quality
@@ -114,123 +197,529 @@ type HttpClient interface {
 
 // Client for Airly API
 type Client struct {
-	Key        string     `json:"key"`
-	Language   string     `json:"language"`
-	HttpClient HttpClient `json:"-"`
+	Key string `json:"key"`
+	// Language of descriptions in responses, e.g. English or Polish, API default (English) is used if not set.
+	// Requests with unsupported language fail with error wrapping ErrUnsupportedLanguage
+	Language      string        `json:"language"`
+	SponsorPolicy SponsorPolicy `json:"sponsorPolicy"`
+	// MaxResponseSize in bytes, DefaultMaxResponseSize is used if not set, negative value means no limit
+	MaxResponseSize int64 `json:"maxResponseSize"`
+	// Timeout of requests made when HttpClient is not set, DefaultTimeout is used if not set,
+	// negative value means no timeout. Custom HttpClient is responsible for its own timeouts
+	Timeout time.Duration `json:"timeout"`
+	// BaseURLs of API or its mirrors (e.g. self-hosted caching proxy) tried in order, the next one is used only if
+	// request fails with connection error. DefaultBaseURL is used if not set
+	BaseURLs []string `json:"baseURLs"`
+	// APIVersion used as path prefix, APIv2 is used if not set. Responses are decoded with Decoder registered for
+	// the version, see RegisterDecoder
+	APIVersion APIVersion `json:"apiVersion"`
+	// Strict makes decoding fail with DecodeError when response has fields unknown to this package, so tests can
+	// detect API changes early. Unknown fields are ignored by default. It doesn't apply to custom decoders
+	Strict bool `json:"strict"`
+	// RequestID returns ID sent in RequestIDHeader, a new one is generated for each API call, random one is used if
+	// not set. Errors of API calls are *RequestError with this ID
+	RequestID func() string `json:"-"`
+	// StatsRecorder collects latencies and errors of API calls returned by Stats, statistics are not collected if
+	// not set
+	StatsRecorder *StatsRecorder `json:"-"`
+	HttpClient    HttpClient     `json:"-"`
 }
 
-const base = "https://airapi.airly.eu/v2/"
+// DefaultTimeout of requests used if neither Client.Timeout nor Client.HttpClient is set
+const DefaultTimeout = 30 * time.Second
+
+// DefaultMaxResponseSize is the limit of response body size used if Client.MaxResponseSize is not set
+const DefaultMaxResponseSize = 16 << 20
+
+// DefaultBaseURL of Airly API, without API version
+const DefaultBaseURL = "https://airapi.airly.eu/"
+
+// RequestIDHeader is header with ID of API call, the same ID is sent on retries and to mirrors, so a failing call
+// can be correlated across HttpClient middleware, proxies and error messages
+const RequestIDHeader = "X-Request-Id"
+
+// RequestError is returned by API calls that failed after request was created, it carries ID of the call
+// (see Client.RequestID) and wraps the actual error, e.g. *APIError
+type RequestError struct {
+	RequestID string
+	// Endpoint is request path without version and query, e.g. measurements/installation
+	Endpoint string
+	Err      error
+}
+
+func (e *RequestError) Error() string {
+	return fmt.Sprintf("%v (request ID %s)", e.Err, e.RequestID)
+}
+
+func (e *RequestError) Unwrap() error {
+	return e.Err
+}
+
+// withRequestID wraps err of request for path in RequestError, nil is returned for nil err
+func withRequestID(err error, id, path string) error {
+	if err == nil {
+		return nil
+	}
+	return &RequestError{RequestID: id, Endpoint: endpoint(path), Err: err}
+}
+
+// endpoint returns path without query
+func endpoint(path string) string {
+	if i := strings.Index(path, "?"); i >= 0 {
+		return path[:i]
+	}
+	return path
+}
+
+// newRequestID returns ID of API call from Client.RequestID or random 16 hex digits
+func (c Client) newRequestID() string {
+	if c.RequestID != nil {
+		return c.RequestID()
+	}
+	var b [8]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+// APIError is returned when API responds with status other than 200 OK
+type APIError struct {
+	StatusCode int
+	Body       string
+	// RetryAfter is delay requested by API with Retry-After header, e.g. when rate limit is exceeded, 0 if not set
+	RetryAfter time.Duration
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("%d: %s", e.StatusCode, e.Body)
+}
+
+// ResponseTooLargeError is returned when response body exceeds Client.MaxResponseSize or, when recorded,
+// AuditingClient.MaxBodySize
+type ResponseTooLargeError struct {
+	Limit int64
+}
+
+func (e *ResponseTooLargeError) Error() string {
+	return fmt.Sprintf("response body exceeds limit of %d bytes", e.Limit)
+}
 
 func (c Client) get(path string, v interface{}) error {
-	req, err := http.NewRequest("GET", base+path, nil)
+	_, err := c.getWithHeader(context.Background(), path, v)
+	return err
+}
+
+// maxPooledBuffer is capacity of the largest buffer kept for reuse, larger ones are left for garbage collector
+const maxPooledBuffer = 1 << 20
+
+// buffers are response body buffers reused between requests
+var buffers = sync.Pool{New: func() interface{} { return new(bytes.Buffer) }}
+
+// httpClient returns HttpClient or, if not set, http.Client with configured timeout
+func (c Client) httpClient() HttpClient {
+	switch {
+	case c.HttpClient != nil:
+		return c.HttpClient
+	case c.Timeout < 0:
+		return http.DefaultClient
+	case c.Timeout == 0:
+		return defaultHttpClient
+	}
+	return &http.Client{Timeout: c.Timeout}
+}
+
+var defaultHttpClient = &http.Client{Timeout: DefaultTimeout}
+
+// do sends GET request with given ID for path of API available under baseURL
+func (c Client) do(ctx context.Context, baseURL, path, id string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", strings.TrimSuffix(baseURL, "/")+"/"+c.APIVersion.path()+"/"+path, nil)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	req.Header.Set("Accept", "application/json")
-	req.Header.Set("apikey", c.Key)
+	req.Header = http.Header{
+		"Accept":        {"application/json"},
+		"Apikey":        {c.Key},
+		RequestIDHeader: {id},
+	}
 	if c.Language != "" {
-		req.Header.Set("Accept-Language", c.Language)
+		req.Header["Accept-Language"] = []string{c.Language}
 	}
-	client := c.HttpClient
-	if client == nil {
-		client = http.DefaultClient
+	return c.httpClient().Do(req)
+}
+
+// send sends GET request for path to the first of BaseURLs that doesn't fail with connection error, ID of
+// the request is returned as well
+func (c Client) send(ctx context.Context, path string) (*http.Response, string, error) {
+	baseURLs := c.BaseURLs
+	if len(baseURLs) == 0 {
+		baseURLs = []string{DefaultBaseURL}
+	}
+	id := c.newRequestID()
+	if err := Language(c.Language).Validate(); err != nil {
+		return nil, id, err
+	}
+	var res *http.Response
+	var err error
+	for _, baseURL := range baseURLs {
+		if res, err = c.do(ctx, baseURL, path, id); err == nil {
+			break
+		}
 	}
-	res, err := client.Do(req)
+	return res, id, err
+}
+
+// getWithHeader works like get but returns response headers as well, they are returned also for non-200 responses
+func (c Client) getWithHeader(ctx context.Context, path string, v interface{}) (header http.Header, err error) {
+	start := time.Now()
+	defer func() { c.recordStats(path, start, err) }()
+	res, id, err := c.send(ctx, path)
 	if err != nil {
-		return err
+		return nil, withRequestID(err, id, path)
+	}
+	header, err = c.read(res, path, v)
+	return header, withRequestID(err, id, path)
+}
+
+// read decodes body of response for path into v
+func (c Client) read(res *http.Response, path string, v interface{}) (http.Header, error) {
+	var err error
+
+	limit := c.MaxResponseSize
+	if limit == 0 {
+		limit = DefaultMaxResponseSize
+	}
+	if limit > 0 && res.ContentLength > limit {
+		_ = res.Body.Close()
+		return res.Header, &ResponseTooLargeError{Limit: limit}
 	}
 
-	body, err := ioutil.ReadAll(res.Body)
+	buf := buffers.Get().(*bytes.Buffer)
+	buf.Reset()
+	defer func() {
+		if buf.Cap() <= maxPooledBuffer {
+			buffers.Put(buf)
+		}
+	}()
+	if res.ContentLength > 0 && res.ContentLength <= maxPooledBuffer {
+		buf.Grow(int(res.ContentLength) + bytes.MinRead)
+	}
+	body := io.Reader(res.Body)
+	if limit > 0 {
+		body = io.LimitReader(res.Body, limit+1)
+	}
+	_, err = buf.ReadFrom(body)
 	_ = res.Body.Close()
 	if err != nil {
-		return err
+		return res.Header, err
+	}
+	if limit > 0 && int64(buf.Len()) > limit {
+		return res.Header, &ResponseTooLargeError{Limit: limit}
 	}
 
 	if res.StatusCode != 200 {
-		return fmt.Errorf("%d: %s", res.StatusCode, body)
+		return res.Header, &APIError{StatusCode: res.StatusCode, Body: buf.String(), RetryAfter: retryAfter(res)}
 	}
 
-	return json.Unmarshal(body, v)
+	return res.Header, decode(c.APIVersion, path, buf.Bytes(), v, c.Strict)
 }
 
 // Installation returns installation by id. See https://developer.airly.org/docs#endpoints.installations.getbyid
 func (c Client) Installation(id int) (Installation, error) {
 	var i Installation
-	err := c.get(fmt.Sprintf("installations/%d", id), &i)
-	return i, err
+	err := c.get("installations/"+strconv.Itoa(id), &i)
+	return c.SponsorPolicy.Apply(i), err
 }
 
 // NearestInstallations returns installations near specified point, range can be defined with MaxDistance,
-// number of results can be defined with MaxResults. See https://developer.airly.org/docs#endpoints.installations.nearest
+// number of results can be defined with MaxResults or AllResults. See https://developer.airly.org/docs#endpoints.installations.nearest
 func (c Client) NearestInstallations(loc Location, options ...NearestInstallationsOption) ([]Installation, error) {
 	var i []Installation
-	config := nearestInstallationsConfig{3.0, 1}
-	for _, option := range options {
-		option(&config)
+	path, err := nearestInstallationsPath(loc, options)
+	if err != nil {
+		return nil, err
+	}
+	err = c.get(path, &i)
+	for j := range i {
+		i[j] = c.SponsorPolicy.Apply(i[j])
 	}
-	err := c.get(fmt.Sprintf("installations/nearest?lat=%f&lng=%f&maxDistanceKM=%f&maxResults=%d",
-		loc.Latitude, loc.Longitude, config.maxDistance, config.maxResults), &i)
 	return i, err
 }
 
-// NearestMeasurements returns measurements for an installation closest to a given location, range can be defined with MaxDistance.
+// EachNearestInstallation works like NearestInstallations but calls fn for each installation as it's decoded from
+// response instead of keeping all of them in memory, which is useful with AllResults over large area.
+// Iteration stops at the first error returned by fn. MaxResponseSize doesn't apply to streamed responses
+func (c Client) EachNearestInstallation(loc Location, fn func(Installation) error, options ...NearestInstallationsOption) error {
+	path, err := nearestInstallationsPath(loc, options)
+	if err != nil {
+		return err
+	}
+	decodersMu.RLock()
+	_, custom := decoders[c.APIVersion.path()]
+	decodersMu.RUnlock()
+	if custom {
+		installations, err := c.NearestInstallations(loc, options...)
+		for _, i := range installations {
+			if err := fn(i); err != nil {
+				return err
+			}
+		}
+		return err
+	}
+
+	start := time.Now()
+	res, id, err := c.send(context.Background(), path)
+	if err != nil {
+		err = withRequestID(err, id, path)
+		c.recordStats(path, start, err)
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != 200 {
+		body, err := ioutil.ReadAll(io.LimitReader(res.Body, 64<<10))
+		if err == nil {
+			err = &APIError{StatusCode: res.StatusCode, Body: string(body), RetryAfter: retryAfter(res)}
+		}
+		err = withRequestID(err, id, path)
+		c.recordStats(path, start, err)
+		return err
+	}
+	// installations are streamed to fn, so only time to response is recorded
+	c.recordStats(path, start, nil)
+	name := endpoint(path)
+	dec := json.NewDecoder(res.Body)
+	if c.Strict {
+		dec.DisallowUnknownFields()
+	}
+	if t, err := dec.Token(); err != nil {
+		return withRequestID(newDecodeError(name, nil, err), id, path)
+	} else if t != json.Delim('[') {
+		return withRequestID(newDecodeError(name, nil, fmt.Errorf("expected array of installations, got %v", t)), id, path)
+	}
+	for dec.More() {
+		var i Installation
+		if err := dec.Decode(&i); err != nil {
+			return withRequestID(newDecodeError(name, nil, err), id, path)
+		}
+		if err := fn(c.SponsorPolicy.Apply(i)); err != nil {
+			return err
+		}
+	}
+	_, err = dec.Token()
+	return withRequestID(newDecodeError(name, nil, err), id, path)
+}
+
+func nearestInstallationsPath(loc Location, options []NearestInstallationsOption) (string, error) {
+	if err := loc.Validate(); err != nil {
+		return "", err
+	}
+	config := newNearestInstallationsConfig(options)
+	if config.maxResults == 0 || config.maxResults < -1 {
+		return "", fmt.Errorf("invalid maximum number of results %d, it must be positive or -1 for all results",
+			config.maxResults)
+	}
+	params := locationParams(loc)
+	params.Set("maxDistanceKM", formatFloat(config.maxDistance))
+	params.Set("maxResults", strconv.Itoa(config.maxResults))
+	return withQuery("installations/nearest", params), nil
+}
+
+// NearestMeasurements returns measurements for an installation closest to a given location, range can be defined with MaxDistance,
+// index type with WithIndexType and pollutant of indexes with WithIndexPollutant.
 // See https://developer.airly.org/en/docs#endpoints.measurements.nearest
-func (c Client) NearestMeasurements(loc Location, options ...NearestInstallationsOption) (Measurements, error) {
-	var m Measurements
-	config := nearestInstallationsConfig{3.0, 1}
-	for _, option := range options {
-		option(&config)
+func (c Client) NearestMeasurements(loc Location, options ...NearestMeasurementsOption) (Measurements, error) {
+	if err := loc.Validate(); err != nil {
+		return Measurements{}, err
 	}
-	err := c.get(fmt.Sprintf("measurements/nearest?lat=%f&lng=%f&maxDistanceKM=%f",
-		loc.Latitude, loc.Longitude, config.maxDistance), &m)
+	var m Measurements
+	config := newNearestMeasurementsConfig(options)
+	params := locationParams(loc)
+	params.Set("maxDistanceKM", formatFloat(config.maxDistance))
+	config.setIndex(params)
+	err := c.get(withQuery("measurements/nearest", params), &m)
 	return m, err
 }
 
 // PointMeasurements returns any geographical location.
 // Measurement values are interpolated by averaging measurements from nearby sensors (up to 1,5km away from the given point).
 // The returned value is a weighted average, with the weight inversely proportional to the distance from the sensor to the given point.
-// See https://developer.airly.org/docs#endpoints.measurements.point
-func (c Client) PointMeasurements(loc Location) (Measurements, error) {
+// Index type can be defined with WithIndexType, pollutant of indexes with WithIndexPollutant. See https://developer.airly.org/docs#endpoints.measurements.point
+func (c Client) PointMeasurements(loc Location, options ...MeasurementsOption) (Measurements, error) {
+	if err := loc.Validate(); err != nil {
+		return Measurements{}, err
+	}
 	var m Measurements
-	err := c.get(fmt.Sprintf("measurements/point?lat=%f&lng=%f", loc.Latitude, loc.Longitude), &m)
+	config := newMeasurementsConfig(options)
+	params := locationParams(loc)
+	config.setIndex(params)
+	err := c.get(withQuery("measurements/point", params), &m)
 	return m, err
 }
 
-// InstallationMeasurements returns measurements for concrete installation, see https://developer.airly.org/docs#endpoints.measurements.installation
-func (c Client) InstallationMeasurements(installationId int) (Measurements, error) {
+// InstallationMeasurements returns measurements for concrete installation, index type can be defined with WithIndexType,
+// pollutant of indexes with WithIndexPollutant.
+// See https://developer.airly.org/docs#endpoints.measurements.installation
+func (c Client) InstallationMeasurements(installationId int, options ...MeasurementsOption) (Measurements, error) {
 	var m Measurements
-	err := c.get(fmt.Sprintf("measurements/installation?installationId=%d", installationId), &m)
+	config := newMeasurementsConfig(options)
+	params := url.Values{"installationId": {strconv.Itoa(installationId)}}
+	config.setIndex(params)
+	err := c.get(withQuery("measurements/installation", params), &m)
 	return m, err
 }
 
-// NearestInstallationsOption represents option to narrow search results
-type NearestInstallationsOption func(config *nearestInstallationsConfig)
+// withQuery returns path with parameters in canonical encoding: keys are sorted and values escaped by url.Values.Encode,
+// numbers are formatted by formatFloat and strconv.Itoa, e.g. measurements/nearest?lat=50.062006&lng=19.940984&maxDistanceKM=3
+func withQuery(path string, params url.Values) string {
+	return path + "?" + params.Encode()
+}
+
+// locationParams returns query parameters with coordinates of loc
+func locationParams(loc Location) url.Values {
+	return url.Values{"lat": {formatFloat(loc.Latitude)}, "lng": {formatFloat(loc.Longitude)}}
+}
+
+// formatFloat formats query parameter with the minimal precision needed to represent v exactly, without exponent
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+// NearestInstallationsOption is an option of NearestInstallations: MaxDistance, MaxResults or AllResults
+type NearestInstallationsOption interface {
+	applyNearestInstallations(config *requestConfig)
+}
+
+// NearestMeasurementsOption is an option of NearestMeasurements: MaxDistance, WithIndexType or WithIndexPollutant
+type NearestMeasurementsOption interface {
+	applyNearestMeasurements(config *requestConfig)
+}
+
+// MeasurementsOption is an option of PointMeasurements and InstallationMeasurements: WithIndexType or
+// WithIndexPollutant
+type MeasurementsOption interface {
+	applyMeasurements(config *requestConfig)
+}
+
+// MaxDistanceOption is returned by MaxDistance, it can be used with NearestInstallations and NearestMeasurements
+type MaxDistanceOption float64
+
+func (o MaxDistanceOption) applyNearestInstallations(c *requestConfig) {
+	c.maxDistance = float64(o)
+}
+
+func (o MaxDistanceOption) applyNearestMeasurements(c *requestConfig) {
+	c.maxDistance = float64(o)
+}
+
+// MaxResultsOption is returned by MaxResults and AllResults, it can be used with NearestInstallations only
+type MaxResultsOption int
+
+func (o MaxResultsOption) applyNearestInstallations(c *requestConfig) {
+	c.maxResults = int(o)
+}
+
+// IndexTypeOption is returned by WithIndexType, it can be used with all measurements endpoints
+type IndexTypeOption string
+
+func (o IndexTypeOption) applyNearestMeasurements(c *requestConfig) {
+	c.indexType = string(o)
+}
+
+func (o IndexTypeOption) applyMeasurements(c *requestConfig) {
+	c.indexType = string(o)
+}
+
+// IndexPollutantOption is returned by WithIndexPollutant, it can be used with all measurements endpoints
+type IndexPollutantOption string
+
+func (o IndexPollutantOption) applyNearestMeasurements(c *requestConfig) {
+	c.indexPollutant = string(o)
+}
+
+func (o IndexPollutantOption) applyMeasurements(c *requestConfig) {
+	c.indexPollutant = string(o)
+}
 
 // MaxDistance to given points in km
-func MaxDistance(maxDistance float64) NearestInstallationsOption {
-	return func(c *nearestInstallationsConfig) {
-		c.maxDistance = maxDistance
+func MaxDistance(maxDistance float64) MaxDistanceOption {
+	return MaxDistanceOption(maxDistance)
+}
+
+// MaxResults that can be returned by API call, it must be positive or -1 meaning no limit, see AllResults.
+// 1 is used by default
+func MaxResults(maxResults int) MaxResultsOption {
+	return MaxResultsOption(maxResults)
+}
+
+// AllResults removes limit of number of results, all installations within MaxDistance are returned.
+// Responses can be large, see EachNearestInstallation
+func AllResults() MaxResultsOption {
+	return MaxResults(-1)
+}
+
+// WithIndexType sets index type used to calculate indexes in measurements, e.g. AIRLY_CAQI (default), CAQI or PIJP,
+// see IndexTypes. Empty index type means default one
+func WithIndexType(indexType string) IndexTypeOption {
+	return IndexTypeOption(indexType)
+}
+
+// WithIndexPollutant requests indexes calculated for single pollutant, e.g. PM25, instead of all of them, so index
+// level and color match the pollutant. Empty pollutant means all pollutants
+func WithIndexPollutant(pollutant string) IndexPollutantOption {
+	return IndexPollutantOption(pollutant)
+}
+
+// requestConfig holds values of options of all endpoints with defaults applied
+type requestConfig struct {
+	maxDistance    float64
+	maxResults     int
+	indexType      string
+	indexPollutant string
+}
+
+func defaultRequestConfig() requestConfig {
+	return requestConfig{maxDistance: 3.0, maxResults: 1}
+}
+
+func newNearestInstallationsConfig(options []NearestInstallationsOption) requestConfig {
+	config := defaultRequestConfig()
+	for _, option := range options {
+		option.applyNearestInstallations(&config)
+	}
+	return config
+}
+
+func newNearestMeasurementsConfig(options []NearestMeasurementsOption) requestConfig {
+	config := defaultRequestConfig()
+	for _, option := range options {
+		option.applyNearestMeasurements(&config)
 	}
+	return config
 }
 
-// MaxResults that can be returned by API call
-func MaxResults(maxResults int) NearestInstallationsOption {
-	return func(c *nearestInstallationsConfig) {
-		c.maxResults = maxResults
+func newMeasurementsConfig(options []MeasurementsOption) requestConfig {
+	config := defaultRequestConfig()
+	for _, option := range options {
+		option.applyMeasurements(&config)
 	}
+	return config
 }
 
-type nearestInstallationsConfig struct {
-	maxDistance float64
-	maxResults  int
+// setIndex sets indexType and indexPollutant parameters if they were defined with WithIndexType and
+// WithIndexPollutant
+func (c requestConfig) setIndex(params url.Values) {
+	if c.indexType != "" {
+		params.Set("indexType", c.indexType)
+	}
+	if c.indexPollutant != "" {
+		params.Set("indexPollutant", c.indexPollutant)
+	}
 }
 
 // IndexTypes returns a list of all the index types supported in the API along with lists of levels defined
 // per each index type, see https://developer.airly.org/docs#endpoints.meta.indexes
 func (c Client) IndexTypes() ([]IndexType, error) {
 	var i []IndexType
-	err := c.get("meta/measurements", &i)
+	err := c.get("meta/indexes", &i)
 	return i, err
 }
 
@@ -241,3 +730,83 @@ func (c Client) MeasurementTypes() ([]MeasurementType, error) {
 	err := c.get("meta/measurements", &m)
 	return m, err
 }
+
+// RateLimit represents API usage limits for the key, see https://developer.airly.org/docs#limits
+type RateLimit struct {
+	DayLimit        int `json:"dayLimit"`
+	DayRemaining    int `json:"dayRemaining"`
+	MinuteLimit     int `json:"minuteLimit"`
+	MinuteRemaining int `json:"minuteRemaining"`
+}
+
+// RateLimit returns current API usage limits read from response headers. It calls meta/indexes endpoint,
+// so the call itself is counted against the limits
+func (c Client) RateLimit() (RateLimit, error) {
+	var i []IndexType
+	header, err := c.getWithHeader(context.Background(), "meta/indexes", &i)
+	if header == nil {
+		return RateLimit{}, err
+	}
+	return parseRateLimit(header), err
+}
+
+// KeyStatus is result of ValidateKey
+type KeyStatus int
+
+const (
+	// KeyUnknown means key couldn't be checked, e.g. because of network error
+	KeyUnknown KeyStatus = iota
+	// KeyValid means key was accepted by API
+	KeyValid
+	// KeyInvalid means key is empty or was rejected by API
+	KeyInvalid
+	// KeyRateLimited means key is valid, but its rate limit is exceeded
+	KeyRateLimited
+)
+
+func (s KeyStatus) String() string {
+	switch s {
+	case KeyValid:
+		return "valid"
+	case KeyInvalid:
+		return "invalid"
+	case KeyRateLimited:
+		return "rate limited"
+	default:
+		return "unknown"
+	}
+}
+
+// ValidateKey checks Key with a single call of meta/measurements endpoint, which has the smallest response, so
+// applications can verify keys supplied by users. Rejected key is reported as KeyInvalid, not as an error, error
+// is returned (with KeyUnknown) only if it couldn't be checked. The call is counted against the limits
+func (c Client) ValidateKey(ctx context.Context) (KeyStatus, error) {
+	if c.Key == "" {
+		return KeyInvalid, nil
+	}
+	var m []MeasurementType
+	_, err := c.getWithHeader(ctx, "meta/measurements", &m)
+	var apiErr *APIError
+	switch {
+	case err == nil:
+		return KeyValid, nil
+	case errors.As(err, &apiErr) && (apiErr.StatusCode == 401 || apiErr.StatusCode == 403):
+		return KeyInvalid, nil
+	case errors.As(err, &apiErr) && apiErr.StatusCode == 429:
+		return KeyRateLimited, nil
+	}
+	return KeyUnknown, err
+}
+
+func parseRateLimit(header http.Header) RateLimit {
+	atoi := func(key string) int {
+		v, _ := strconv.Atoi(header.Get(key))
+		return v
+	}
+	return RateLimit{
+		DayLimit:        atoi("X-RateLimit-Limit-day"),
+		DayRemaining:    atoi("X-RateLimit-Remaining-day"),
+		MinuteLimit:     atoi("X-RateLimit-Limit-minute"),
+		MinuteRemaining: atoi("X-RateLimit-Remaining-minute"),
+	}
+}
@@ -1,10 +1,14 @@
 package airly
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
 	"errors"
 	"github.com/stretchr/testify/assert"
 	"io"
 	"net/http"
+	"net/url"
 	"strings"
 	"testing"
 	"time"
@@ -31,13 +35,14 @@ func TestError(t *testing.T) {
 			return nil, err
 		}}}
 	_, err2 := api.Installation(204)
-	assert.Equal(t, err, err2)
+	assert.ErrorIs(t, err2, err)
 }
 
 func TestNon200Status(t *testing.T) {
 	api := Client{
-		Key:      "x1234x",
-		Language: "pl",
+		Key:       "x1234x",
+		Language:  "pl",
+		RequestID: func() string { return "42" },
 		HttpClient: mockClient{func(req *http.Request) (*http.Response, error) {
 			return &http.Response{
 				StatusCode: 404,
@@ -45,7 +50,103 @@ func TestNon200Status(t *testing.T) {
 			}, nil
 		}}}
 	_, err2 := api.Installation(204)
-	assert.Equal(t, "404: not found", err2.Error())
+	assert.Equal(t, "404: not found (request ID 42)", err2.Error())
+	assert.Equal(t, &RequestError{RequestID: "42", Endpoint: "installations/204",
+		Err: &APIError{StatusCode: 404, Body: "not found"}}, err2)
+}
+
+func TestAPIErrorRetryAfter(t *testing.T) {
+	api := Client{HttpClient: mockClient{func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: 429,
+			Header:     http.Header{"Retry-After": {"30"}},
+			Body:       readCloser("Too Many Requests"),
+		}, nil
+	}}}
+	_, err := api.NearestMeasurements(Location{50.062006, 19.940984})
+	var apiErr *APIError
+	if assert.True(t, errors.As(err, &apiErr)) {
+		assert.Equal(t, 30*time.Second, apiErr.RetryAfter)
+	}
+	var requestErr *RequestError
+	if assert.True(t, errors.As(err, &requestErr)) {
+		assert.Equal(t, "measurements/nearest", requestErr.Endpoint)
+	}
+}
+
+func TestMaxResponseSize(t *testing.T) {
+	contentLength := int64(-1)
+	api := Client{
+		MaxResponseSize: 10,
+		HttpClient: mockClient{func(req *http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode:    200,
+				ContentLength: contentLength,
+				Body:          readCloser(`{"id": 204}`),
+			}, nil
+		}}}
+	_, err := api.Installation(204)
+	assert.Equal(t, &ResponseTooLargeError{Limit: 10}, errors.Unwrap(err))
+	assert.EqualError(t, errors.Unwrap(err), "response body exceeds limit of 10 bytes")
+
+	contentLength = 11
+	_, err = api.Installation(204)
+	assert.Equal(t, &ResponseTooLargeError{Limit: 10}, errors.Unwrap(err))
+
+	api.MaxResponseSize = 11
+	i, err := api.Installation(204)
+	assert.Nil(t, err)
+	assert.Equal(t, 204, i.Id)
+
+	contentLength = -1
+	api.MaxResponseSize = -1
+	i, err = api.Installation(204)
+	assert.Nil(t, err)
+	assert.Equal(t, 204, i.Id)
+}
+
+func TestTimeout(t *testing.T) {
+	assert.Equal(t, &http.Client{Timeout: DefaultTimeout}, Client{}.httpClient())
+	assert.Equal(t, &http.Client{Timeout: time.Second}, Client{Timeout: time.Second}.httpClient())
+	assert.Equal(t, http.DefaultClient, Client{Timeout: -1}.httpClient())
+	custom := mockClient{}
+	assert.Equal(t, custom, Client{Timeout: time.Second, HttpClient: custom}.httpClient())
+}
+
+func TestBaseURLs(t *testing.T) {
+	var urls []string
+	api := Client{
+		BaseURLs: []string{"http://proxy.local/airly", "https://airapi.airly.eu/"},
+		HttpClient: mockClient{func(req *http.Request) (*http.Response, error) {
+			urls = append(urls, req.URL.String())
+			if req.URL.Host == "proxy.local" {
+				return nil, errors.New("connection refused")
+			}
+			return &http.Response{StatusCode: 200, Body: readCloser(`{"id": 204}`)}, nil
+		}}}
+	i, err := api.Installation(204)
+	assert.Nil(t, err)
+	assert.Equal(t, 204, i.Id)
+	assert.Equal(t, []string{"http://proxy.local/airly/v2/installations/204",
+		"https://airapi.airly.eu/v2/installations/204"}, urls)
+
+	// only connection errors cause failover
+	urls = nil
+	api.BaseURLs = []string{"https://airapi.airly.eu", "http://proxy.local/airly/"}
+	api.HttpClient = mockClient{func(req *http.Request) (*http.Response, error) {
+		urls = append(urls, req.URL.String())
+		return &http.Response{StatusCode: 503, Body: readCloser("unavailable")}, nil
+	}}
+	_, err = api.Installation(204)
+	assert.Equal(t, &APIError{StatusCode: 503, Body: "unavailable"}, errors.Unwrap(err))
+	assert.Equal(t, []string{"https://airapi.airly.eu/v2/installations/204"}, urls)
+
+	api.BaseURLs = []string{"http://proxy.local/airly/"}
+	api.HttpClient = mockClient{func(req *http.Request) (*http.Response, error) {
+		return nil, errors.New("connection refused")
+	}}
+	_, err = api.Installation(204)
+	assert.EqualError(t, errors.Unwrap(err), "connection refused")
 }
 
 func TestInstallation(t *testing.T) {
@@ -118,7 +219,7 @@ func TestNearestInstallations(t *testing.T) {
 		Language: "pl",
 		HttpClient: mockClient{func(req *http.Request) (*http.Response, error) {
 			assert.Equal(t, "https://airapi.airly.eu/v2/installations/nearest?lat=50.062006"+
-				"&lng=19.940984&maxDistanceKM=3.000000&maxResults=1", req.URL.String())
+				"&lng=19.940984&maxDistanceKM=3&maxResults=1", req.URL.String())
 			assert.Equal(t, "application/json", req.Header.Get("Accept"))
 			assert.Equal(t, "pl", req.Header.Get("Accept-Language"))
 			assert.Equal(t, "x1234x", req.Header.Get("apikey"))
@@ -183,7 +284,7 @@ func TestNearestInstallationsOptions(t *testing.T) {
 		Language: "pl",
 		HttpClient: mockClient{func(req *http.Request) (*http.Response, error) {
 			assert.Equal(t, "https://airapi.airly.eu/v2/installations/nearest?lat=50.062006"+
-				"&lng=19.940984&maxDistanceKM=5.000000&maxResults=3", req.URL.String())
+				"&lng=19.940984&maxDistanceKM=5&maxResults=3", req.URL.String())
 			assert.Equal(t, "application/json", req.Header.Get("Accept"))
 			assert.Equal(t, "pl", req.Header.Get("Accept-Language"))
 			assert.Equal(t, "x1234x", req.Header.Get("apikey"))
@@ -243,6 +344,68 @@ func TestNearestInstallationsOptions(t *testing.T) {
 	}}, installations)
 }
 
+func TestNearestInstallationsMaxResults(t *testing.T) {
+	var url string
+	api := Client{
+		HttpClient: mockClient{func(req *http.Request) (*http.Response, error) {
+			url = req.URL.String()
+			return &http.Response{StatusCode: 200, Body: readCloser(`[]`)}, nil
+		}}}
+	_, err := api.NearestInstallations(Location{Latitude: 50.062006, Longitude: 19.940984}, AllResults())
+	assert.Nil(t, err)
+	assert.Equal(t, "https://airapi.airly.eu/v2/installations/nearest?lat=50.062006&lng=19.940984"+
+		"&maxDistanceKM=3&maxResults=-1", url)
+
+	for _, maxResults := range []int{0, -2} {
+		url = ""
+		_, err = api.NearestInstallations(Location{}, MaxResults(maxResults))
+		assert.NotNil(t, err)
+		assert.Equal(t, "", url)
+		assert.NotNil(t, api.EachNearestInstallation(Location{}, func(Installation) error { return nil },
+			MaxResults(maxResults)))
+	}
+}
+
+func TestEachNearestInstallation(t *testing.T) {
+	body := `[{"id": 204, "sponsor": {"name": "Airly", "logo": "logo.png"}}, {"id": 8077}, {"id": 9000}]`
+	status := 200
+	api := Client{
+		SponsorPolicy: SponsorNameOnly,
+		HttpClient: mockClient{func(req *http.Request) (*http.Response, error) {
+			return &http.Response{StatusCode: status, Body: readCloser(body)}, nil
+		}}}
+	var ids []int
+	err := api.EachNearestInstallation(Location{}, func(i Installation) error {
+		ids = append(ids, i.Id)
+		if i.Id == 204 {
+			assert.Equal(t, Sponsor{Name: "Airly"}, i.Sponsor)
+		}
+		return nil
+	}, AllResults())
+	assert.Nil(t, err)
+	assert.Equal(t, []int{204, 8077, 9000}, ids)
+
+	stop := errors.New("stop")
+	ids = nil
+	err = api.EachNearestInstallation(Location{}, func(i Installation) error {
+		ids = append(ids, i.Id)
+		return stop
+	})
+	assert.Equal(t, stop, err)
+	assert.Equal(t, []int{204}, ids)
+
+	body = `{"id": 204}`
+	assert.EqualError(t, errors.Unwrap(api.EachNearestInstallation(Location{}, func(Installation) error { return nil })),
+		"cannot decode response of installations/nearest: expected array of installations, got {")
+
+	body = `[{"id": 204}, {"id": `
+	assert.NotNil(t, api.EachNearestInstallation(Location{}, func(Installation) error { return nil }))
+
+	status, body = 401, "unauthorized"
+	assert.Equal(t, &APIError{StatusCode: 401, Body: "unauthorized"},
+		errors.Unwrap(api.EachNearestInstallation(Location{}, func(Installation) error { return nil })))
+}
+
 func TestInstallationMeasurements(t *testing.T) {
 	api := Client{
 		Key:      "x1234x",
@@ -325,7 +488,7 @@ func TestNearestMeasurements(t *testing.T) {
 		Key:      "x1234x",
 		Language: "pl",
 		HttpClient: mockClient{func(req *http.Request) (*http.Response, error) {
-			assert.Equal(t, "https://airapi.airly.eu/v2/measurements/nearest?lat=50.062006&lng=19.940984&maxDistanceKM=5.000000", req.URL.String())
+			assert.Equal(t, "https://airapi.airly.eu/v2/measurements/nearest?lat=50.062006&lng=19.940984&maxDistanceKM=5", req.URL.String())
 			assert.Equal(t, "application/json", req.Header.Get("Accept"))
 			assert.Equal(t, "pl", req.Header.Get("Accept-Language"))
 			assert.Equal(t, "x1234x", req.Header.Get("apikey"))
@@ -473,3 +636,349 @@ func TestPointMeasurements(t *testing.T) {
 		Forecast: []Measurement{},
 	}, measurements)
 }
+
+func TestMeasurementsIndexType(t *testing.T) {
+	var urls []string
+	api := Client{
+		Key: "x1234x",
+		HttpClient: mockClient{func(req *http.Request) (*http.Response, error) {
+			urls = append(urls, req.URL.String())
+			return &http.Response{
+				StatusCode: 200,
+				Body:       readCloser(`{}`),
+			}, nil
+		}},
+	}
+	_, err := api.InstallationMeasurements(204, WithIndexType("PIJP"))
+	assert.Nil(t, err)
+	_, err = api.NearestMeasurements(Location{50.062006, 19.940984}, WithIndexType("CAQI"))
+	assert.Nil(t, err)
+	_, err = api.PointMeasurements(Location{50.062006, 19.940984}, WithIndexType("AIRLY_CAQI"))
+	assert.Nil(t, err)
+	assert.Equal(t, []string{
+		"https://airapi.airly.eu/v2/measurements/installation?indexType=PIJP&installationId=204",
+		"https://airapi.airly.eu/v2/measurements/nearest?indexType=CAQI&lat=50.062006&lng=19.940984&maxDistanceKM=3",
+		"https://airapi.airly.eu/v2/measurements/point?indexType=AIRLY_CAQI&lat=50.062006&lng=19.940984",
+	}, urls)
+}
+
+func TestMeasurementsIndexPollutant(t *testing.T) {
+	var urls []string
+	api := Client{
+		Key: "x1234x",
+		HttpClient: mockClient{func(req *http.Request) (*http.Response, error) {
+			urls = append(urls, req.URL.String())
+			return &http.Response{
+				StatusCode: 200,
+				Body:       readCloser(`{}`),
+			}, nil
+		}},
+	}
+	_, err := api.InstallationMeasurements(204, WithIndexPollutant("PM25"))
+	assert.Nil(t, err)
+	_, err = api.NearestMeasurements(Location{50.062006, 19.940984}, WithIndexType("CAQI"), WithIndexPollutant("PM10"))
+	assert.Nil(t, err)
+	_, err = api.PointMeasurements(Location{50.062006, 19.940984}, WithIndexPollutant(""))
+	assert.Nil(t, err)
+	assert.Equal(t, []string{
+		"https://airapi.airly.eu/v2/measurements/installation?indexPollutant=PM25&installationId=204",
+		"https://airapi.airly.eu/v2/measurements/nearest?indexPollutant=PM10&indexType=CAQI&lat=50.062006&lng=19.940984&maxDistanceKM=3",
+		"https://airapi.airly.eu/v2/measurements/point?lat=50.062006&lng=19.940984",
+	}, urls)
+	assert.Equal(t, "PM25", ResolveMeasurementsOptions(WithIndexPollutant("PM25")).IndexPollutant)
+}
+
+func TestCanonicalQuery(t *testing.T) {
+	assert.Equal(t, "measurements/point?indexType=A%26B+C&lat=-0.00001&lng=180",
+		withQuery("measurements/point", url.Values{"lng": {formatFloat(180)}, "lat": {formatFloat(-0.00001)},
+			"indexType": {"A&B C"}}))
+	assert.Equal(t, "1.5", formatFloat(1.5))
+	assert.Equal(t, "50.0620061234", formatFloat(50.0620061234))
+}
+
+func TestIndexTypes(t *testing.T) {
+	api := Client{
+		Key: "x1234x",
+		HttpClient: mockClient{func(req *http.Request) (*http.Response, error) {
+			assert.Equal(t, "https://airapi.airly.eu/v2/meta/indexes", req.URL.String())
+			return &http.Response{
+				StatusCode: 200,
+				Body: readCloser(`[{
+									  "name": "AIRLY_CAQI",
+									  "levels": [
+										{
+										  "values": "0-25",
+										  "level": "VERY_LOW",
+										  "description": "Very Low",
+										  "color": "#6BC926"
+										}
+									  ]
+									}]`),
+			}, nil
+		}},
+	}
+	indexTypes, err := api.IndexTypes()
+	assert.Nil(t, err)
+	assert.Equal(t, []IndexType{{
+		Name: "AIRLY_CAQI",
+		Levels: []Level{{
+			Values:      "0-25",
+			Level:       "VERY_LOW",
+			Description: "Very Low",
+			Color:       "#6BC926",
+		}},
+	}}, indexTypes)
+}
+
+func TestMeasurementTypes(t *testing.T) {
+	api := Client{
+		Key: "x1234x",
+		HttpClient: mockClient{func(req *http.Request) (*http.Response, error) {
+			assert.Equal(t, "https://airapi.airly.eu/v2/meta/measurements", req.URL.String())
+			return &http.Response{
+				StatusCode: 200,
+				Body:       readCloser(`[{"name": "PM25", "label": "PM2.5", "unit": "µg/m³"}]`),
+			}, nil
+		}},
+	}
+	measurementTypes, err := api.MeasurementTypes()
+	assert.Nil(t, err)
+	assert.Equal(t, []MeasurementType{{Name: "PM25", Label: "PM2.5", Unit: "µg/m³"}}, measurementTypes)
+}
+
+func TestRateLimit(t *testing.T) {
+	api := Client{
+		Key: "x1234x",
+		HttpClient: mockClient{func(req *http.Request) (*http.Response, error) {
+			header := http.Header{}
+			header.Set("X-RateLimit-Limit-day", "100")
+			header.Set("X-RateLimit-Remaining-day", "42")
+			header.Set("X-RateLimit-Limit-minute", "50")
+			header.Set("X-RateLimit-Remaining-minute", "49")
+			return &http.Response{
+				StatusCode: 429,
+				Header:     header,
+				Body:       readCloser("too many requests"),
+			}, nil
+		}},
+	}
+	rateLimit, err := api.RateLimit()
+	assert.Equal(t, &APIError{StatusCode: 429, Body: "too many requests"}, errors.Unwrap(err))
+	assert.Equal(t, RateLimit{DayLimit: 100, DayRemaining: 42, MinuteLimit: 50, MinuteRemaining: 49}, rateLimit)
+}
+
+func TestSponsorPolicy(t *testing.T) {
+	api := Client{
+		Key:           "x1234x",
+		SponsorPolicy: SponsorNameOnly,
+		HttpClient: mockClient{func(req *http.Request) (*http.Response, error) {
+			body := `{"id": 204, "sponsor": {"id": 1, "name": "KrakówOddycha", "displayName": "Kraków Oddycha", "logo": "https://cdn.airly.org/logo.jpg", "link": "https://example.com"}}`
+			if req.URL.Path == "/v2/installations/nearest" {
+				body = "[" + body + "]"
+			}
+			return &http.Response{
+				StatusCode: 200,
+				Body:       readCloser(body),
+			}, nil
+		}},
+	}
+	installation, err := api.Installation(204)
+	assert.Nil(t, err)
+	assert.Equal(t, Sponsor{Name: "KrakówOddycha", DisplayName: "Kraków Oddycha"}, installation.Sponsor)
+
+	api.SponsorPolicy = SponsorStrip
+	installations, err := api.NearestInstallations(Location{50.062006, 19.940984})
+	assert.Nil(t, err)
+	assert.Equal(t, []Installation{{Id: 204}}, installations)
+
+	i := Installation{Id: 204, Sponsor: Sponsor{Id: 1, Name: "KrakówOddycha"}}
+	assert.Equal(t, i, SponsorKeep.Apply(i))
+}
+
+// benchmarkMeasurements returns typical measurements payload with 24 hours of history and forecast
+func benchmarkMeasurements(b *testing.B) []byte {
+	window := func(from time.Time) Measurement {
+		return Measurement{
+			FromDateTime: from,
+			TillDateTime: from.Add(time.Hour),
+			Values: []Value{{Name: "PM1", Value: 12.73}, {Name: "PM25", Value: 18.7}, {Name: "PM10", Value: 35.53},
+				{Name: "PRESSURE", Value: 1012.62}, {Name: "HUMIDITY", Value: 66.81}, {Name: "TEMPERATURE", Value: 24.95}},
+			Indexes: []Index{{Name: "AIRLY_CAQI", Value: 35.53, Level: "LOW", Description: "Air is quite good.",
+				Advice: "Take a breath!", Color: "#D1CF1E"}},
+			Standards: []Standard{{"WHO", "PM25", 25, 74.8}, {"WHO", "PM10", 50, 71.06}},
+		}
+	}
+	now := time.Date(2020, 11, 18, 20, 0, 0, 0, time.UTC)
+	m := Measurements{Current: window(now)}
+	for i := 1; i <= 24; i++ {
+		m.History = append(m.History, window(now.Add(time.Duration(i-25)*time.Hour)))
+		m.Forecast = append(m.Forecast, window(now.Add(time.Duration(i)*time.Hour)))
+	}
+	payload, err := json.Marshal(m)
+	if err != nil {
+		b.Fatal(err)
+	}
+	return payload
+}
+
+func benchmarkClient(payload []byte) Client {
+	return Client{
+		Key: "x1234x",
+		HttpClient: mockClient{func(req *http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode:    200,
+				ContentLength: int64(len(payload)),
+				Body:          io.NopCloser(bytes.NewReader(payload)),
+			}, nil
+		}}}
+}
+
+func BenchmarkInstallationMeasurements(b *testing.B) {
+	api := benchmarkClient(benchmarkMeasurements(b))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := api.InstallationMeasurements(204); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkNearestMeasurements(b *testing.B) {
+	api := benchmarkClient(benchmarkMeasurements(b))
+	loc := Location{Latitude: 50.062006, Longitude: 19.940984}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := api.NearestMeasurements(loc); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkNearestInstallations(b *testing.B) {
+	installations := make([]Installation, 100)
+	for i := range installations {
+		installations[i] = Installation{Id: i, Location: Location{Latitude: 50.062006, Longitude: 19.940984},
+			Address: Address{Country: "Poland", City: "Kraków", Street: "Mikołajska", Number: "4B"},
+			Airly:   true, Sponsor: Sponsor{Id: 489, Name: "Chatham Financial", Logo: "https://example.com/logo.png"}}
+	}
+	payload, err := json.Marshal(installations)
+	if err != nil {
+		b.Fatal(err)
+	}
+	api := benchmarkClient(payload)
+	loc := Location{Latitude: 50.062006, Longitude: 19.940984}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := api.NearestInstallations(loc, AllResults()); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestInvalidLocation(t *testing.T) {
+	api := Client{HttpClient: mockClient{func(req *http.Request) (*http.Response, error) {
+		t.Errorf("unexpected request %s", req.URL)
+		return nil, errors.New("unexpected request")
+	}}}
+	loc := Location{Latitude: 19.940984, Longitude: 250.062006}
+	_, err := api.NearestInstallations(loc)
+	assert.ErrorIs(t, err, ErrInvalidLocation)
+	err = api.EachNearestInstallation(loc, func(Installation) error { return nil })
+	assert.ErrorIs(t, err, ErrInvalidLocation)
+	_, err = api.NearestMeasurements(loc)
+	assert.ErrorIs(t, err, ErrInvalidLocation)
+	_, err = api.PointMeasurements(loc)
+	assert.ErrorIs(t, err, ErrInvalidLocation)
+}
+
+func TestRequestID(t *testing.T) {
+	var ids []string
+	api := Client{
+		BaseURLs: []string{"http://proxy.local/airly", "https://airapi.airly.eu/"},
+		HttpClient: mockClient{func(req *http.Request) (*http.Response, error) {
+			ids = append(ids, req.Header.Get(RequestIDHeader))
+			if req.URL.Host == "proxy.local" {
+				return nil, errors.New("connection refused")
+			}
+			return &http.Response{StatusCode: 500, Body: readCloser("error")}, nil
+		}}}
+	_, err := api.Installation(204)
+	assert.Len(t, ids, 2)
+	assert.Len(t, ids[0], 16)
+	assert.Equal(t, ids[0], ids[1], "the same ID should be sent to all mirrors")
+	var requestErr *RequestError
+	if assert.True(t, errors.As(err, &requestErr)) {
+		assert.Equal(t, ids[0], requestErr.RequestID)
+	}
+	assert.EqualError(t, err, "500: error (request ID "+ids[0]+")")
+
+	_, _ = api.Installation(204)
+	assert.NotEqual(t, ids[0], ids[2], "each call should have new ID")
+
+	api.RequestID = func() string { return "trace-1" }
+	_, err = api.Installation(204)
+	assert.Equal(t, "trace-1", ids[4])
+	assert.EqualError(t, err, "500: error (request ID trace-1)")
+}
+
+func TestNullValue(t *testing.T) {
+	var m Measurement
+	assert.NoError(t, json.Unmarshal([]byte(`{"values": [{"name": "PM25", "value": 0}, {"name": "PM10", "value": null},
+		{"name": "NO2"}]}`), &m))
+	assert.Equal(t, []Value{{Name: "PM25"}, {Name: "PM10", Null: true}, {Name: "NO2", Null: true}}, m.Values)
+
+	v, ok := m.Value("PM25")
+	assert.True(t, ok, "zero reading is a valid reading")
+	assert.Equal(t, 0.0, v)
+	_, ok = m.Value("PM10")
+	assert.False(t, ok, "null reading should be reported as missing")
+	_, ok = m.Value("O3")
+	assert.False(t, ok)
+
+	data, err := json.Marshal(m.Values)
+	assert.NoError(t, err)
+	assert.Equal(t, `[{"name":"PM25","value":0},{"name":"PM10","value":null},{"name":"NO2","value":null}]`, string(data))
+}
+
+func TestValidateKey(t *testing.T) {
+	var paths []string
+	api := Client{HttpClient: mockClient{func(req *http.Request) (*http.Response, error) {
+		paths = append(paths, req.URL.Path)
+		switch req.Header.Get("apikey") {
+		case "valid":
+			return &http.Response{StatusCode: 200, Body: readCloser(`[]`)}, nil
+		case "limited":
+			return &http.Response{StatusCode: 429, Body: readCloser(`{}`)}, nil
+		case "forbidden":
+			return &http.Response{StatusCode: 403, Body: readCloser(`{}`)}, nil
+		case "error":
+			return &http.Response{StatusCode: 500, Body: readCloser(`{}`)}, nil
+		}
+		return &http.Response{StatusCode: 401, Body: readCloser(`{}`)}, nil
+	}}}
+	for key, expected := range map[string]KeyStatus{
+		"valid": KeyValid, "limited": KeyRateLimited, "forbidden": KeyInvalid, "wrong": KeyInvalid, "": KeyInvalid,
+	} {
+		api.Key = key
+		status, err := api.ValidateKey(context.Background())
+		assert.NoError(t, err, key)
+		assert.Equal(t, expected, status, key)
+	}
+	assert.Equal(t, "/v2/meta/measurements", paths[0])
+	assert.Len(t, paths, 4)
+
+	api.Key = "error"
+	status, err := api.ValidateKey(context.Background())
+	assert.Error(t, err)
+	assert.Equal(t, KeyUnknown, status)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	api = Client{Key: "valid", Timeout: -1, BaseURLs: []string{"http://127.0.0.1:1/"}}
+	status, err = api.ValidateKey(ctx)
+	assert.True(t, errors.Is(err, context.Canceled))
+	assert.Equal(t, "unknown", status.String())
+}
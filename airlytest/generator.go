@@ -0,0 +1,166 @@
+// Package airlytest provides synthetic Airly data for tests, e.g. load testing of code storing or alerting on
+// measurements without using API quota
+package airlytest
+
+import (
+	"github.com/probakowski/go-airly"
+	"math"
+	"math/rand"
+	"time"
+)
+
+// Generator produces realistic, randomized series of hourly measurements: PM values follow a daily cycle with peaks
+// in the morning and evening, with random noise, occasional spikes and gaps (missing windows). The same Seed gives
+// the same series. Generator is not safe for concurrent use
+type Generator struct {
+	// Seed of random numbers
+	Seed int64
+	// Start of the first window, the current hour is used if zero
+	Start time.Time
+	// Window is duration of measurement window, 1 hour is used if zero
+	Window time.Duration
+	// PM25 is average PM2.5 concentration in µg/m³, 20 is used if zero
+	PM25 float64
+	// SpikeProbability is probability of window with PM values a few times higher than usual, 0.02 is used if zero,
+	// negative value disables spikes
+	SpikeProbability float64
+	// GapProbability is probability of a missing window, 0.01 is used if zero, negative value disables gaps
+	GapProbability float64
+
+	rnd     *rand.Rand
+	next    time.Time
+	history []airly.Measurement
+}
+
+// caqiLevels are upper bounds of PM2.5 concentration and CAQI values of Airly index levels
+var caqiLevels = []struct {
+	pm25, caqi float64
+	level      string
+}{
+	{15, 25, "VERY_LOW"},
+	{30, 50, "LOW"},
+	{55, 75, "MEDIUM"},
+	{82.5, 87.5, "HIGH"},
+	{110, 100, "VERY_HIGH"},
+	{165, 125, "EXTREME"},
+}
+
+func (g *Generator) init() {
+	if g.rnd != nil {
+		return
+	}
+	g.rnd = rand.New(rand.NewSource(g.Seed))
+	g.next = g.Start
+	if g.next.IsZero() {
+		g.next = time.Now().UTC().Truncate(time.Hour)
+	}
+}
+
+func (g *Generator) window() time.Duration {
+	if g.Window == 0 {
+		return time.Hour
+	}
+	return g.Window
+}
+
+func probability(p, def float64) float64 {
+	if p == 0 {
+		return def
+	}
+	return math.Max(p, 0)
+}
+
+// Next returns measurement of the next window, windows skipped as gaps are not returned
+func (g *Generator) Next() airly.Measurement {
+	g.init()
+	for g.rnd.Float64() < probability(g.GapProbability, 0.01) {
+		g.next = g.next.Add(g.window())
+	}
+	from := g.next
+	g.next = from.Add(g.window())
+
+	base := g.PM25
+	if base == 0 {
+		base = 20
+	}
+	hour := float64(from.Hour()) + float64(from.Minute())/60
+	// peaks around 8:00 and 20:00, lowest values in the afternoon
+	daily := 1 + 0.3*math.Cos((hour-8)*math.Pi/6) + 0.2*math.Cos((hour-20)*math.Pi/12)
+	pm25 := base * daily * math.Exp(g.rnd.NormFloat64()*0.2)
+	if g.rnd.Float64() < probability(g.SpikeProbability, 0.02) {
+		pm25 *= 3 + 3*g.rnd.Float64()
+	}
+	temperature := 8 - 5*math.Cos((hour-3)*math.Pi/12) + g.rnd.NormFloat64()
+	round := func(v float64) float64 {
+		return math.Round(v*100) / 100
+	}
+
+	caqi, level := index(pm25)
+	m := airly.Measurement{
+		FromDateTime: from,
+		TillDateTime: g.next,
+		Values: []airly.Value{
+			{Name: airly.PM1, Value: round(pm25 * 0.65)},
+			{Name: airly.PM25, Value: round(pm25)},
+			{Name: airly.PM10, Value: round(pm25 * (1.3 + 0.2*g.rnd.Float64()))},
+			{Name: airly.Pressure, Value: round(1013 + 5*g.rnd.NormFloat64())},
+			{Name: airly.Humidity, Value: round(math.Min(100, math.Max(20, 90-3*temperature+5*g.rnd.NormFloat64())))},
+			{Name: airly.Temperature, Value: round(temperature)},
+		},
+		Indexes: []airly.Index{{Name: "AIRLY_CAQI", Value: round(caqi), Level: level}},
+	}
+	g.history = append(g.history, m)
+	if len(g.history) > 24 {
+		g.history = g.history[1:]
+	}
+	return m
+}
+
+// index returns CAQI value and level for PM2.5 concentration, interpolated between level bounds
+func index(pm25 float64) (float64, string) {
+	lowPM, lowCAQI := 0.0, 0.0
+	for _, l := range caqiLevels {
+		if pm25 <= l.pm25 {
+			return lowCAQI + (pm25-lowPM)/(l.pm25-lowPM)*(l.caqi-lowCAQI), l.level
+		}
+		lowPM, lowCAQI = l.pm25, l.caqi
+	}
+	return lowCAQI + (pm25-lowPM)/2, "AIRMAGEDDON"
+}
+
+// Measurements returns Measurements as returned by API: the next window is current measurement, up to 24 windows
+// generated before are history. Forecast is empty
+func (g *Generator) Measurements() airly.Measurements {
+	history := append([]airly.Measurement{}, g.history...)
+	return airly.Measurements{Current: g.Next(), History: history, Forecast: []airly.Measurement{}}
+}
+
+// Stream sends rate measurements per second until stop is closed, then the channel is closed. Windows follow each
+// other regardless of rate, so a day of data can be generated in a second. Rate must be positive and at most 1e9
+// (one measurement per nanosecond), otherwise the returned channel is closed immediately
+func (g *Generator) Stream(rate float64, stop <-chan struct{}) <-chan airly.Measurements {
+	ch := make(chan airly.Measurements)
+	interval := time.Duration(float64(time.Second) / rate)
+	if !(rate > 0) || interval <= 0 {
+		close(ch)
+		return ch
+	}
+	ticker := time.NewTicker(interval)
+	go func() {
+		defer close(ch)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+			}
+			select {
+			case <-stop:
+				return
+			case ch <- g.Measurements():
+			}
+		}
+	}()
+	return ch
+}
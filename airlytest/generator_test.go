@@ -0,0 +1,76 @@
+package airlytest
+
+import (
+	"github.com/probakowski/go-airly"
+	"github.com/stretchr/testify/assert"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestGenerator(t *testing.T) {
+	start := time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)
+	g := Generator{Seed: 1, Start: start, GapProbability: 0.1, SpikeProbability: -1}
+	var series []airly.Measurement
+	for i := 0; i < 24*30; i++ {
+		series = append(series, g.Next())
+	}
+	assert.Equal(t, start, series[0].FromDateTime)
+	assert.Nil(t, airly.Measurements{History: series}.Validate())
+	assert.True(t, series[len(series)-1].TillDateTime.Sub(start) > time.Duration(len(series))*time.Hour,
+		"there should be gaps")
+
+	var morning, afternoon []airly.Measurement
+	for _, m := range series {
+		switch m.FromDateTime.Hour() {
+		case 8:
+			morning = append(morning, m)
+		case 14:
+			afternoon = append(afternoon, m)
+		}
+	}
+	p := func(series []airly.Measurement) float64 {
+		return airly.Percentiles(series, airly.PM25, airly.Window{}, 50)[0]
+	}
+	assert.True(t, p(morning) > p(afternoon), "%v <= %v", p(morning), p(afternoon))
+	assert.True(t, p(series) > 15 && p(series) < 25, "%v", p(series))
+
+	same := Generator{Seed: 1, Start: start, GapProbability: 0.1, SpikeProbability: -1}
+	assert.Equal(t, series[0], same.Next())
+}
+
+func TestIndex(t *testing.T) {
+	for _, tc := range []struct {
+		pm25, caqi float64
+		level      string
+	}{
+		{0, 0, "VERY_LOW"}, {15, 25, "VERY_LOW"}, {22.5, 37.5, "LOW"}, {110, 100, "VERY_HIGH"}, {175, 130, "AIRMAGEDDON"},
+	} {
+		caqi, level := index(tc.pm25)
+		assert.Equal(t, tc.caqi, caqi, tc.pm25)
+		assert.Equal(t, tc.level, level, tc.pm25)
+	}
+}
+
+func TestStream(t *testing.T) {
+	g := Generator{Seed: 1, GapProbability: -1}
+	stop := make(chan struct{})
+	ch := g.Stream(1000, stop)
+	first := <-ch
+	second := <-ch
+	close(stop)
+	for range ch {
+	}
+	assert.Equal(t, first.Current.TillDateTime, second.Current.FromDateTime)
+	assert.Equal(t, []airly.Measurement{first.Current}, second.History)
+}
+
+func TestStreamInvalidRate(t *testing.T) {
+	g := Generator{Seed: 1}
+	stop := make(chan struct{})
+	defer close(stop)
+	for _, rate := range []float64{0, -1, 2e9, math.NaN()} {
+		_, ok := <-g.Stream(rate, stop)
+		assert.False(t, ok, rate)
+	}
+}
@@ -0,0 +1,93 @@
+package airly
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+// InstallationVersion is installation metadata valid in given period. ValidFrom is time when version was observed
+// for the first time and ValidTo when the next version was observed (the actual change happened between the two
+// observations), ValidTo is zero for the latest version
+type InstallationVersion struct {
+	Installation Installation `json:"installation"`
+	ValidFrom    time.Time    `json:"validFrom"`
+	ValidTo      time.Time    `json:"validTo"`
+}
+
+// InstallationArchive keeps every observed version of installations metadata, so analyses over long series can
+// account for sensor relocations and sponsor changes. It can be saved and restored with encoding/json.
+// InstallationArchive is safe for concurrent use
+type InstallationArchive struct {
+	mu       sync.Mutex
+	versions map[int][]InstallationVersion
+}
+
+// sameVersion returns true if installations differ only in data source
+func sameVersion(a, b Installation) bool {
+	a.Source, b.Source = "", ""
+	return a == b
+}
+
+// Observe records installation metadata fetched at given time, it returns true if it's a new version. Observations
+// older than the latest version are ignored
+func (a *InstallationArchive) Observe(i Installation, at time.Time) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.versions == nil {
+		a.versions = map[int][]InstallationVersion{}
+	}
+	versions := a.versions[i.Id]
+	if n := len(versions); n > 0 {
+		latest := &versions[n-1]
+		if sameVersion(latest.Installation, i) || at.Before(latest.ValidFrom) {
+			return false
+		}
+		latest.ValidTo = at
+	}
+	a.versions[i.Id] = append(versions, InstallationVersion{Installation: i, ValidFrom: at})
+	return true
+}
+
+// Versions returns all versions of installation, the oldest first
+func (a *InstallationArchive) Versions(installationId int) []InstallationVersion {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return append([]InstallationVersion(nil), a.versions[installationId]...)
+}
+
+// At returns version of installation valid at given time, false is returned if installation wasn't observed
+// before that time
+func (a *InstallationArchive) At(installationId int, t time.Time) (Installation, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	versions := a.versions[installationId]
+	for i := len(versions) - 1; i >= 0; i-- {
+		if !t.Before(versions[i].ValidFrom) {
+			return versions[i].Installation, true
+		}
+	}
+	return Installation{}, false
+}
+
+// MarshalJSON encodes versions of all installations as object with installation IDs as keys
+func (a *InstallationArchive) MarshalJSON() ([]byte, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.versions == nil {
+		return []byte("{}"), nil
+	}
+	return json.Marshal(a.versions)
+}
+
+// UnmarshalJSON restores archive encoded with MarshalJSON, replacing its content
+func (a *InstallationArchive) UnmarshalJSON(data []byte) error {
+	var versions map[int][]InstallationVersion
+	if err := json.Unmarshal(data, &versions); err != nil {
+		return err
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.versions = versions
+	return nil
+}
@@ -0,0 +1,82 @@
+package airly
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestInstallationArchive(t *testing.T) {
+	day := func(d int) time.Time {
+		return time.Date(2021, 3, d, 0, 0, 0, 0, time.UTC)
+	}
+	original := Installation{Id: 204, Location: Location{50.062006, 19.940984}, Sponsor: Sponsor{Name: "A"}}
+	moved := Installation{Id: 204, Location: Location{50.072006, 19.940984}, Sponsor: Sponsor{Name: "A"}}
+	sponsored := Installation{Id: 204, Location: Location{50.072006, 19.940984}, Sponsor: Sponsor{Name: "B"}}
+
+	a := &InstallationArchive{}
+	assert.True(t, a.Observe(original, day(1)))
+	assert.False(t, a.Observe(original, day(2)))
+	withSource := original
+	withSource.Source = "fallback"
+	assert.False(t, a.Observe(withSource, day(3)))
+	assert.True(t, a.Observe(moved, day(5)))
+	assert.False(t, a.Observe(sponsored, day(4)))
+	assert.True(t, a.Observe(sponsored, day(10)))
+	assert.True(t, a.Observe(Installation{Id: 8077}, day(1)))
+
+	expected := []InstallationVersion{
+		{Installation: original, ValidFrom: day(1), ValidTo: day(5)},
+		{Installation: moved, ValidFrom: day(5), ValidTo: day(10)},
+		{Installation: sponsored, ValidFrom: day(10)},
+	}
+	assert.Equal(t, expected, a.Versions(204))
+
+	_, ok := a.At(204, day(1).Add(-time.Second))
+	assert.False(t, ok)
+	for d, i := range map[int]Installation{1: original, 4: original, 5: moved, 9: moved, 10: sponsored, 20: sponsored} {
+		v, ok := a.At(204, day(d))
+		assert.True(t, ok, d)
+		assert.Equal(t, i, v, d)
+	}
+
+	data, err := json.Marshal(a)
+	assert.NoError(t, err)
+	restored := &InstallationArchive{}
+	assert.NoError(t, json.Unmarshal(data, restored))
+	assert.Equal(t, expected, restored.Versions(204))
+	assert.Len(t, restored.Versions(8077), 1)
+
+	data, err = json.Marshal(&InstallationArchive{})
+	assert.NoError(t, err)
+	assert.Equal(t, "{}", string(data))
+}
+
+func TestInstallationWatcherArchive(t *testing.T) {
+	now := time.Date(2021, 3, 1, 12, 0, 0, 0, time.UTC)
+	street := "Mikołajska"
+	w := InstallationWatcher{
+		Client: Client{
+			HttpClient: mockClient{func(req *http.Request) (*http.Response, error) {
+				return &http.Response{StatusCode: 200, Body: readCloser(`{"id": 204, "address": {"street": "` + street + `"}}`)}, nil
+			}},
+		},
+		Installations: []int{204},
+		Archive:       &InstallationArchive{},
+		now:           func() time.Time { return now },
+	}
+	_, err := w.Check()
+	assert.NoError(t, err)
+	now = now.Add(24 * time.Hour)
+	street = "Floriańska"
+	_, err = w.Check()
+	assert.NoError(t, err)
+
+	versions := w.Archive.Versions(204)
+	assert.Len(t, versions, 2)
+	assert.Equal(t, "Mikołajska", versions[0].Installation.Address.Street)
+	assert.Equal(t, now, versions[0].ValidTo)
+	assert.Equal(t, "Floriańska", versions[1].Installation.Address.Street)
+}
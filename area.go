@@ -0,0 +1,59 @@
+package airly
+
+import (
+	"sort"
+	"time"
+)
+
+// AreaWatcher periodically downloads installations in area and reports ones that appeared since previous check,
+// e.g. to learn about new sensors nearby. It's not safe for concurrent use
+type AreaWatcher struct {
+	Client Client
+	// Center of watched area
+	Center Location
+	// Radius of watched area in km
+	Radius float64
+	// Interval between checks in Watch, 24 hours is used if not set
+	Interval time.Duration
+	// OnError is called with errors of checks, if set
+	OnError func(err error)
+
+	known map[int]bool
+}
+
+// Check downloads installations in area once and returns InstallationAdded changes for ones not seen before,
+// sorted by distance from Center. The first check only records installations already present
+func (w *AreaWatcher) Check() ([]InstallationChange, error) {
+	installations, err := w.Client.NearestInstallations(w.Center, MaxDistance(w.Radius), AllResults())
+	if err != nil {
+		if w.OnError != nil {
+			w.OnError(err)
+		}
+		return nil, err
+	}
+	first := w.known == nil
+	if first {
+		w.known = map[int]bool{}
+	}
+	var changes []InstallationChange
+	for _, i := range installations {
+		if w.known[i.Id] || w.Center.Distance(i.Location) > w.Radius {
+			continue
+		}
+		w.known[i.Id] = true
+		if !first {
+			changes = append(changes, InstallationChange{Kind: InstallationAdded, InstallationId: i.Id, Current: i})
+		}
+	}
+	sort.SliceStable(changes, func(i, j int) bool {
+		return w.Center.Distance(changes[i].Current.Location) < w.Center.Distance(changes[j].Current.Location)
+	})
+	return changes, nil
+}
+
+// Watch runs Check every Interval (starting immediately) and sends new installations to returned channel until
+// stop is closed, then the channel is closed. Errors are passed to OnError and area is checked again in the next
+// round
+func (w *AreaWatcher) Watch(stop <-chan struct{}) <-chan InstallationChange {
+	return watch(w.Interval, w.Check, stop)
+}
@@ -0,0 +1,88 @@
+package airly
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestAreaWatcher(t *testing.T) {
+	body := `[{"id": 204, "location": {"latitude": 50.062006, "longitude": 19.940984}}]`
+	var fail error
+	w := AreaWatcher{
+		Client: Client{
+			HttpClient: mockClient{func(req *http.Request) (*http.Response, error) {
+				assert.Equal(t, "/v2/installations/nearest", req.URL.Path)
+				assert.Equal(t, "5", req.URL.Query().Get("maxDistanceKM"))
+				assert.Equal(t, "-1", req.URL.Query().Get("maxResults"))
+				if fail != nil {
+					return nil, fail
+				}
+				return &http.Response{StatusCode: 200, Body: readCloser(body)}, nil
+			}},
+		},
+		Center: Location{50.062006, 19.940984},
+		Radius: 5,
+	}
+
+	changes, err := w.Check()
+	assert.NoError(t, err)
+	assert.Empty(t, changes)
+
+	// 8077 is ~1km away, 9000 ~2km away and 1 outside of area
+	body = `[{"id": 9000, "location": {"latitude": 50.08, "longitude": 19.940984}},
+		{"id": 204, "location": {"latitude": 50.062006, "longitude": 19.940984}},
+		{"id": 1, "location": {"latitude": 50.2, "longitude": 19.940984}},
+		{"id": 8077, "location": {"latitude": 50.071, "longitude": 19.940984}}]`
+	changes, err = w.Check()
+	assert.NoError(t, err)
+	var ids []int
+	for _, c := range changes {
+		assert.Equal(t, InstallationAdded, c.Kind)
+		assert.Equal(t, c.InstallationId, c.Current.Id)
+		ids = append(ids, c.InstallationId)
+	}
+	assert.Equal(t, []int{8077, 9000}, ids)
+
+	changes, err = w.Check()
+	assert.NoError(t, err)
+	assert.Empty(t, changes)
+
+	fail = errors.New("connection refused")
+	var reported error
+	w.OnError = func(err error) { reported = err }
+	_, err = w.Check()
+	assert.ErrorIs(t, err, fail)
+	assert.Equal(t, err, reported)
+}
+
+func TestAreaWatcherWatch(t *testing.T) {
+	ids := []int{204}
+	w := AreaWatcher{
+		Client: Client{
+			HttpClient: mockClient{func(req *http.Request) (*http.Response, error) {
+				body := "["
+				for i, id := range ids {
+					if i > 0 {
+						body += ","
+					}
+					body += `{"id": ` + formatFloat(float64(id)) + `, "location": {"latitude": 50.062006, "longitude": 19.940984}}`
+				}
+				ids = append(ids, ids[len(ids)-1]+1)
+				return &http.Response{StatusCode: 200, Body: readCloser(body + "]")}, nil
+			}},
+		},
+		Center:   Location{50.062006, 19.940984},
+		Radius:   1,
+		Interval: time.Millisecond,
+	}
+	stop := make(chan struct{})
+	ch := w.Watch(stop)
+	assert.Equal(t, 205, (<-ch).InstallationId)
+	assert.Equal(t, 206, (<-ch).InstallationId)
+	close(stop)
+	for range ch {
+	}
+}
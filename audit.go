@@ -0,0 +1,210 @@
+package airly
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// AuditEntry is a single request recorded by AuditingClient
+type AuditEntry struct {
+	Time      time.Time `json:"time"`
+	RequestID string    `json:"requestId,omitempty"`
+	// Endpoint is request path without API version, e.g. measurements/installation
+	Endpoint string            `json:"endpoint"`
+	Params   map[string]string `json:"params,omitempty"`
+	// Status of response, 0 if request failed without response
+	Status int    `json:"status,omitempty"`
+	Error  string `json:"error,omitempty"`
+	// Duration in milliseconds
+	Duration float64 `json:"durationMs"`
+	// RateLimit read from response headers, nil if they were not present
+	RateLimit *RateLimit `json:"rateLimit,omitempty"`
+	// Body of response, recorded only if AuditingClient.RecordBodies is set
+	Body string `json:"body,omitempty"`
+}
+
+// AuditingClient is HttpClient appending AuditEntry for every request to Log as a line of JSON, so API usage can be
+// analyzed later, e.g. with SummarizeAudit. Entries are written after response headers are received.
+// AuditingClient is safe for concurrent use
+type AuditingClient struct {
+	// HttpClient used to send requests, http.Client with DefaultTimeout is used if not set
+	HttpClient HttpClient
+	// Log the entries are appended to, e.g. file opened with os.O_APPEND
+	Log io.Writer
+	// OnError is called with errors of writing to Log, if set. Requests don't fail because of them
+	OnError func(err error)
+	// RecordBodies makes entries include response bodies, so the log can be replayed by ReplayClient.
+	// Entries are then written after the whole body is read
+	RecordBodies bool
+	// MaxBodySize in bytes of recorded bodies, DefaultMaxResponseSize is used if not set, negative value means no
+	// limit. Requests with larger responses fail with *ResponseTooLargeError
+	MaxBodySize int64
+
+	mu  sync.Mutex
+	now func() time.Time
+}
+
+// Do sends request and records it in Log
+func (c *AuditingClient) Do(req *http.Request) (*http.Response, error) {
+	client := c.HttpClient
+	if client == nil {
+		client = defaultHttpClient
+	}
+	now := c.now
+	if now == nil {
+		now = time.Now
+	}
+
+	start := now()
+	res, err := client.Do(req)
+	entry := AuditEntry{
+		Time:      start,
+		RequestID: req.Header.Get(RequestIDHeader),
+		Endpoint:  auditEndpoint(req.URL.Path),
+		Duration:  float64(now().Sub(start)) / float64(time.Millisecond),
+	}
+	if query := req.URL.Query(); len(query) > 0 {
+		entry.Params = map[string]string{}
+		for k := range query {
+			entry.Params[k] = query.Get(k)
+		}
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	} else {
+		entry.Status = res.StatusCode
+		if res.Header.Get("X-RateLimit-Limit-day") != "" {
+			rateLimit := parseRateLimit(res.Header)
+			entry.RateLimit = &rateLimit
+		}
+		if c.RecordBodies {
+			limit := c.MaxBodySize
+			if limit == 0 {
+				limit = DefaultMaxResponseSize
+			}
+			body := io.Reader(res.Body)
+			if limit > 0 {
+				body = io.LimitReader(res.Body, limit+1)
+			}
+			data, e := ioutil.ReadAll(body)
+			_ = res.Body.Close()
+			if e == nil && limit > 0 && int64(len(data)) > limit {
+				e = &ResponseTooLargeError{Limit: limit}
+			}
+			if e != nil {
+				entry.Error = e.Error()
+				c.write(entry)
+				return nil, e
+			}
+			res.Body = ioutil.NopCloser(bytes.NewReader(data))
+			entry.Body = string(data)
+		}
+	}
+
+	c.write(entry)
+	return res, err
+}
+
+// write appends entry to Log, errors are passed to OnError
+func (c *AuditingClient) write(entry AuditEntry) {
+	line, err := json.Marshal(entry)
+	if err == nil {
+		c.mu.Lock()
+		_, err = c.Log.Write(append(line, '\n'))
+		c.mu.Unlock()
+	}
+	if err != nil && c.OnError != nil {
+		c.OnError(err)
+	}
+}
+
+// auditEndpoint returns path without base path and API version, e.g. /airly/v2/installations/204 -> installations/204
+func auditEndpoint(path string) string {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for i, s := range segments {
+		if len(s) > 1 && s[0] == 'v' && strings.Trim(s[1:], "0123456789") == "" {
+			return strings.Join(segments[i+1:], "/")
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// ReadAuditLog reads entries written by AuditingClient
+func ReadAuditLog(r io.Reader) ([]AuditEntry, error) {
+	var entries []AuditEntry
+	dec := json.NewDecoder(r)
+	for {
+		var entry AuditEntry
+		if err := dec.Decode(&entry); err == io.EOF {
+			return entries, nil
+		} else if err != nil {
+			return entries, err
+		}
+		entries = append(entries, entry)
+	}
+}
+
+// AuditSummary is usage of a single endpoint on a single day, every request is counted against API quota
+type AuditSummary struct {
+	// Day in local time of entries, YYYY-MM-DD
+	Day string `json:"day"`
+	// Endpoint with installation IDs replaced by {id}, e.g. installations/{id}
+	Endpoint string `json:"endpoint"`
+	Requests int    `json:"requests"`
+	// Errors is number of requests that failed or got status other than 200 OK
+	Errors int `json:"errors"`
+	// DayRemaining is the last known number of remaining requests for the day, -1 if unknown
+	DayRemaining int `json:"dayRemaining"`
+}
+
+// SummarizeAudit groups entries by day and endpoint, summaries are sorted by day and endpoint
+func SummarizeAudit(entries []AuditEntry) []AuditSummary {
+	type key struct{ day, endpoint string }
+	summaries := map[key]*AuditSummary{}
+	var keys []key
+	for _, e := range entries {
+		k := key{e.Time.Format("2006-01-02"), summaryEndpoint(e.Endpoint)}
+		s, ok := summaries[k]
+		if !ok {
+			s = &AuditSummary{Day: k.day, Endpoint: k.endpoint, DayRemaining: -1}
+			summaries[k] = s
+			keys = append(keys, k)
+		}
+		s.Requests++
+		if e.Status != http.StatusOK {
+			s.Errors++
+		}
+		if e.RateLimit != nil {
+			s.DayRemaining = e.RateLimit.DayRemaining
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].day != keys[j].day {
+			return keys[i].day < keys[j].day
+		}
+		return keys[i].endpoint < keys[j].endpoint
+	})
+	result := make([]AuditSummary, len(keys))
+	for i, k := range keys {
+		result[i] = *summaries[k]
+	}
+	return result
+}
+
+// summaryEndpoint replaces numeric path segments with {id}
+func summaryEndpoint(endpoint string) string {
+	segments := strings.Split(endpoint, "/")
+	for i, s := range segments {
+		if s != "" && strings.Trim(s, "0123456789") == "" {
+			segments[i] = "{id}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
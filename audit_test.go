@@ -0,0 +1,118 @@
+package airly
+
+import (
+	"bytes"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+	"time"
+)
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+func TestAuditingClient(t *testing.T) {
+	start := time.Date(2021, 3, 1, 12, 0, 0, 0, time.UTC)
+	now := start
+	var log bytes.Buffer
+	auditing := &AuditingClient{
+		Log: &log,
+		HttpClient: mockClient{func(req *http.Request) (*http.Response, error) {
+			now = now.Add(150 * time.Millisecond)
+			if req.URL.Path == "/v2/meta/indexes" {
+				return nil, errors.New("connection refused")
+			}
+			header := http.Header{}
+			header.Set("X-RateLimit-Limit-day", "100")
+			header.Set("X-RateLimit-Remaining-day", "42")
+			return &http.Response{StatusCode: 200, Header: header, Body: readCloser(`{"id": 204}`)}, nil
+		}},
+		now: func() time.Time { return now },
+	}
+	api := Client{HttpClient: auditing, RequestID: func() string { return "42" }}
+	_, err := api.InstallationMeasurements(204, WithIndexType("CAQI"))
+	assert.NoError(t, err)
+	_, err = api.IndexTypes()
+	assert.Error(t, err)
+
+	entries, err := ReadAuditLog(&log)
+	assert.NoError(t, err)
+	assert.Equal(t, []AuditEntry{{
+		Time:      start,
+		RequestID: "42",
+		Endpoint:  "measurements/installation",
+		Params:    map[string]string{"installationId": "204", "indexType": "CAQI"},
+		Status:    200,
+		Duration:  150,
+		RateLimit: &RateLimit{DayLimit: 100, DayRemaining: 42},
+	}, {
+		Time:      start.Add(150 * time.Millisecond),
+		RequestID: "42",
+		Endpoint:  "meta/indexes",
+		Error:     "connection refused",
+		Duration:  150,
+	}}, entries)
+
+	var writeErr error
+	auditing.Log = failingWriter{}
+	auditing.OnError = func(err error) { writeErr = err }
+	_, err = api.Installation(204)
+	assert.NoError(t, err, "audit log errors should not fail requests")
+	assert.EqualError(t, writeErr, "disk full")
+}
+
+func TestAuditingClientMaxBodySize(t *testing.T) {
+	var log bytes.Buffer
+	auditing := &AuditingClient{
+		Log:          &log,
+		RecordBodies: true,
+		MaxBodySize:  11,
+		HttpClient: mockClient{func(req *http.Request) (*http.Response, error) {
+			if req.URL.Path == "/v2/installations/204" {
+				return &http.Response{StatusCode: 200, Body: readCloser(`{"id": 204}`)}, nil
+			}
+			return &http.Response{StatusCode: 200, Body: readCloser(`{"id": 8077}`)}, nil
+		}},
+	}
+	api := Client{HttpClient: auditing}
+	i, err := api.Installation(204)
+	assert.NoError(t, err)
+	assert.Equal(t, 204, i.Id)
+	_, err = api.Installation(8077)
+	var tooLarge *ResponseTooLargeError
+	assert.ErrorAs(t, err, &tooLarge)
+	assert.Equal(t, int64(11), tooLarge.Limit)
+
+	entries, err := ReadAuditLog(&log)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 2)
+	assert.Equal(t, `{"id": 204}`, entries[0].Body)
+	assert.Equal(t, "", entries[1].Body)
+	assert.Equal(t, "response body exceeds limit of 11 bytes", entries[1].Error)
+}
+
+func TestAuditEndpoint(t *testing.T) {
+	assert.Equal(t, "installations/204", auditEndpoint("/v2/installations/204"))
+	assert.Equal(t, "measurements/point", auditEndpoint("/airly/v2/measurements/point"))
+	assert.Equal(t, "other", auditEndpoint("/other"))
+}
+
+func TestSummarizeAudit(t *testing.T) {
+	day1 := time.Date(2021, 3, 1, 12, 0, 0, 0, time.UTC)
+	day2 := day1.Add(24 * time.Hour)
+	entries := []AuditEntry{
+		{Time: day1, Endpoint: "installations/204", Status: 200, RateLimit: &RateLimit{DayRemaining: 99}},
+		{Time: day1, Endpoint: "installations/8077", Status: 404, RateLimit: &RateLimit{DayRemaining: 98}},
+		{Time: day1, Endpoint: "measurements/installation", Status: 200},
+		{Time: day2, Endpoint: "installations/204", Error: "connection refused"},
+	}
+	assert.Equal(t, []AuditSummary{
+		{Day: "2021-03-01", Endpoint: "installations/{id}", Requests: 2, Errors: 1, DayRemaining: 98},
+		{Day: "2021-03-01", Endpoint: "measurements/installation", Requests: 1, DayRemaining: -1},
+		{Day: "2021-03-02", Endpoint: "installations/{id}", Requests: 1, Errors: 1, DayRemaining: -1},
+	}, SummarizeAudit(entries))
+}
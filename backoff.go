@@ -0,0 +1,98 @@
+package airly
+
+import (
+	"math"
+	"math/rand"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// BackoffPolicy defines delays between attempts of RetryingClient. NextDelay is called after failed attempt
+// (attempt is 1 after the first one) with error or response of that attempt
+type BackoffPolicy interface {
+	NextDelay(attempt int, err error, res *http.Response) time.Duration
+}
+
+// ConstantBackoff waits the same Delay before each retry
+type ConstantBackoff struct {
+	Delay time.Duration
+}
+
+// NextDelay returns Delay
+func (b ConstantBackoff) NextDelay(int, error, *http.Response) time.Duration {
+	return b.Delay
+}
+
+// ExponentialBackoff multiplies delay with each retry
+type ExponentialBackoff struct {
+	// Initial delay, 1 second is used if not set
+	Initial time.Duration
+	// Multiplier of delay, 2 is used if not set
+	Multiplier float64
+	// Max delay, delays are not limited if not set
+	Max time.Duration
+}
+
+// NextDelay returns Initial*Multiplier^(attempt-1) limited to Max
+func (b ExponentialBackoff) NextDelay(attempt int, _ error, _ *http.Response) time.Duration {
+	initial := b.Initial
+	if initial == 0 {
+		initial = time.Second
+	}
+	multiplier := b.Multiplier
+	if multiplier == 0 {
+		multiplier = 2
+	}
+	delay := float64(initial) * math.Pow(multiplier, float64(attempt-1))
+	if b.Max > 0 && delay > float64(b.Max) {
+		return b.Max
+	}
+	if delay > math.MaxInt64 {
+		return math.MaxInt64
+	}
+	return time.Duration(delay)
+}
+
+// DecorrelatedJitterBackoff picks random delay between Base and 3 times the previous delay, limited to Max,
+// which spreads retries of many clients failing at the same time, see
+// https://aws.amazon.com/blogs/architecture/exponential-backoff-and-jitter/.
+// Previous delay is reset by the first retry, policy is safe for concurrent use but requests sharing it
+// also share previous delay
+type DecorrelatedJitterBackoff struct {
+	// Base delay, 1 second is used if not set
+	Base time.Duration
+	// Max delay, 30 seconds is used if not set
+	Max time.Duration
+
+	mu     sync.Mutex
+	prev   time.Duration
+	random func() float64
+}
+
+// NextDelay returns random delay between Base and 3 times previous delay
+func (b *DecorrelatedJitterBackoff) NextDelay(attempt int, _ error, _ *http.Response) time.Duration {
+	base := b.Base
+	if base == 0 {
+		base = time.Second
+	}
+	max := b.Max
+	if max == 0 {
+		max = 30 * time.Second
+	}
+	random := b.random
+	if random == nil {
+		random = rand.Float64
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if attempt <= 1 || b.prev < base {
+		b.prev = base
+	}
+	delay := base + time.Duration(random()*float64(3*b.prev-base))
+	if delay > max {
+		delay = max
+	}
+	b.prev = delay
+	return delay
+}
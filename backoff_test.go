@@ -0,0 +1,45 @@
+package airly
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestConstantBackoff(t *testing.T) {
+	b := ConstantBackoff{Delay: 3 * time.Second}
+	assert.Equal(t, 3*time.Second, b.NextDelay(1, nil, nil))
+	assert.Equal(t, 3*time.Second, b.NextDelay(5, nil, nil))
+}
+
+func TestExponentialBackoff(t *testing.T) {
+	var b ExponentialBackoff
+	assert.Equal(t, time.Second, b.NextDelay(1, nil, nil))
+	assert.Equal(t, 2*time.Second, b.NextDelay(2, nil, nil))
+	assert.Equal(t, 8*time.Second, b.NextDelay(4, nil, nil))
+
+	b = ExponentialBackoff{Initial: 100 * time.Millisecond, Multiplier: 1.5, Max: time.Second}
+	assert.Equal(t, 150*time.Millisecond, b.NextDelay(2, nil, nil))
+	assert.Equal(t, time.Second, b.NextDelay(10, nil, nil))
+
+	assert.True(t, ExponentialBackoff{}.NextDelay(100, nil, nil) > 0)
+}
+
+func TestDecorrelatedJitterBackoff(t *testing.T) {
+	r := 1.0
+	b := &DecorrelatedJitterBackoff{Max: 20 * time.Second, random: func() float64 { return r }}
+	assert.Equal(t, 3*time.Second, b.NextDelay(1, nil, nil))
+	assert.Equal(t, 9*time.Second, b.NextDelay(2, nil, nil))
+	assert.Equal(t, 20*time.Second, b.NextDelay(3, nil, nil))
+	// first retry resets previous delay
+	r = 0.5
+	assert.Equal(t, 2*time.Second, b.NextDelay(1, nil, nil))
+	r = 0
+	assert.Equal(t, time.Second, b.NextDelay(2, nil, nil))
+
+	b = &DecorrelatedJitterBackoff{}
+	for i := 1; i < 20; i++ {
+		d := b.NextDelay(i, nil, nil)
+		assert.True(t, d >= time.Second && d <= 30*time.Second)
+	}
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"flag"
+	"github.com/probakowski/go-airly"
+	"os"
+	"strconv"
+)
+
+func init() {
+	commands["audit"] = command{"Summarize API usage per day and endpoint from audit log", audit}
+}
+
+func audit(args []string) int {
+	fs := flag.NewFlagSet("audit", flag.ContinueOnError)
+	out := outputFlags(fs)
+	errorFlags(fs)
+	file := fs.String("file", os.Getenv("AIRLY_AUDIT_LOG"), "Audit log written with --audit-log, "+
+		"AIRLY_AUDIT_LOG environment variable is used by default")
+	if err := parseFlags(fs, args); err != nil {
+		return exitUsage
+	}
+	if *file == "" {
+		printError(usageError("Usage: airly audit --file audit.jsonl [flags]"))
+		return exitUsage
+	}
+
+	f, err := os.Open(*file)
+	if err != nil {
+		printError(err)
+		return exitError
+	}
+	defer f.Close()
+	entries, err := airly.ReadAuditLog(f)
+	if err != nil {
+		printError(err)
+		return exitError
+	}
+	summaries := airly.SummarizeAudit(entries)
+	if err := out.print(summaries, func() [][]string { return auditTable(summaries) }); err != nil {
+		printError(err)
+		return exitError
+	}
+	return exitOK
+}
+
+func auditTable(summaries []airly.AuditSummary) [][]string {
+	rows := [][]string{{"DAY", "ENDPOINT", "REQUESTS", "ERRORS", "REMAINING"}}
+	for _, s := range summaries {
+		remaining := "-"
+		if s.DayRemaining >= 0 {
+			remaining = strconv.Itoa(s.DayRemaining)
+		}
+		rows = append(rows, []string{s.Day, s.Endpoint, strconv.Itoa(s.Requests), strconv.Itoa(s.Errors), remaining})
+	}
+	return rows
+}
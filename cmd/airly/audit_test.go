@@ -0,0 +1,26 @@
+package main
+
+import (
+	"github.com/probakowski/go-airly"
+	"github.com/stretchr/testify/assert"
+	"path/filepath"
+	"testing"
+)
+
+func TestAuditTable(t *testing.T) {
+	assert.Equal(t, [][]string{
+		{"DAY", "ENDPOINT", "REQUESTS", "ERRORS", "REMAINING"},
+		{"2021-03-01", "installations/{id}", "2", "1", "98"},
+		{"2021-03-01", "measurements/installation", "1", "0", "-"},
+	}, auditTable([]airly.AuditSummary{
+		{Day: "2021-03-01", Endpoint: "installations/{id}", Requests: 2, Errors: 1, DayRemaining: 98},
+		{Day: "2021-03-01", Endpoint: "measurements/installation", Requests: 1, DayRemaining: -1},
+	}))
+}
+
+func TestAuditErrorFormat(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "audit.jsonl")
+	assert.Equal(t, exitError, audit([]string{"--file", missing, "--error-format", "json"}))
+	assert.Equal(t, exitUsage, audit([]string{"--file", missing, "--error-format", "xml"}))
+	errorFormat = "text"
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Nagios plugin exit codes, see https://nagios-plugins.org/doc/guidelines.html#AEN78
+const (
+	stateOK = iota
+	stateWarning
+	stateCritical
+	stateUnknown
+)
+
+var stateNames = []string{"OK", "WARNING", "CRITICAL", "UNKNOWN"}
+
+func init() {
+	commands["check"] = command{"Nagios/Icinga compatible check with perfdata", check}
+}
+
+// threshold for single value, nil warn or crit means given level is not checked
+type threshold struct {
+	name       string
+	warn, crit *float64
+}
+
+// state returns Nagios state of v, thresholds are exceeded only by values above them
+func (t threshold) state(v float64) int {
+	switch {
+	case t.crit != nil && v > *t.crit:
+		return stateCritical
+	case t.warn != nil && v > *t.warn:
+		return stateWarning
+	}
+	return stateOK
+}
+
+// thresholdFlag sets threshold level, which stays nil until the flag is set, so zero can be used as threshold
+type thresholdFlag struct {
+	level **float64
+}
+
+func (f thresholdFlag) String() string {
+	if f.level == nil {
+		return ""
+	}
+	return formatThreshold(*f.level)
+}
+
+func (f thresholdFlag) Set(s string) error {
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return err
+	}
+	*f.level = &v
+	return nil
+}
+
+func check(args []string) int {
+	fs := flag.NewFlagSet("check", flag.ContinueOnError)
+	client := clientFlags(fs)
+	target := targetFlags(fs)
+	thresholds := []*threshold{{name: "PM25"}, {name: "PM10"}, {name: "AIRLY_CAQI"}}
+	for _, t := range thresholds {
+		flagName := strings.ToLower(strings.TrimPrefix(t.name, "AIRLY_"))
+		fs.Var(thresholdFlag{&t.warn}, "warn-"+flagName, "Warning threshold for "+t.name)
+		fs.Var(thresholdFlag{&t.crit}, "crit-"+flagName, "Critical threshold for "+t.name)
+	}
+	if err := parseFlags(fs, args); err != nil {
+		fmt.Printf("AIRLY UNKNOWN - %s\n", err)
+		return stateUnknown
+	}
+
+	measurements, err := target.measurements(*client)
+	if err != nil {
+		fmt.Printf("AIRLY UNKNOWN - %s\n", err)
+		return stateUnknown
+	}
+
+	state := stateOK
+	var summary, perfdata []string
+	for _, t := range thresholds {
+		v, ok := value(measurements.Current, t.name)
+		if !ok {
+			if t.warn != nil || t.crit != nil {
+				state = stateUnknown
+				summary = append(summary, t.name+" missing")
+			}
+			continue
+		}
+		if s := t.state(v); s > state {
+			state = s
+		}
+		summary = append(summary, fmt.Sprintf("%s %.2f", t.name, v))
+		perfdata = append(perfdata, fmt.Sprintf("%s=%s;%s;%s", strings.ToLower(t.name), formatFloat(v),
+			formatThreshold(t.warn), formatThreshold(t.crit)))
+	}
+	fmt.Printf("AIRLY %s - %s | %s\n", stateNames[state], strings.Join(summary, ", "), strings.Join(perfdata, " "))
+	return state
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+// formatThreshold formats threshold level for perfdata, empty string is returned if it's not set
+func formatThreshold(v *float64) string {
+	if v == nil {
+		return ""
+	}
+	return formatFloat(*v)
+}
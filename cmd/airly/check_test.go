@@ -0,0 +1,45 @@
+package main
+
+import (
+	"flag"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestThresholdState(t *testing.T) {
+	warn, crit, zero := 25.0, 50.0, 0.0
+	th := threshold{name: "PM25", warn: &warn, crit: &crit}
+	assert.Equal(t, stateOK, th.state(10))
+	assert.Equal(t, stateOK, th.state(25))
+	assert.Equal(t, stateWarning, th.state(25.1))
+	assert.Equal(t, stateWarning, th.state(50))
+	assert.Equal(t, stateCritical, th.state(50.1))
+	assert.Equal(t, stateOK, threshold{name: "PM10"}.state(500))
+	assert.Equal(t, stateOK, threshold{name: "PM10", warn: &zero}.state(0))
+	assert.Equal(t, stateWarning, threshold{name: "PM10", warn: &zero}.state(0.5))
+}
+
+func TestThresholdFlag(t *testing.T) {
+	var th threshold
+	fs := flag.NewFlagSet("check", flag.ContinueOnError)
+	fs.Var(thresholdFlag{&th.warn}, "warn-pm25", "")
+	fs.Var(thresholdFlag{&th.crit}, "crit-pm25", "")
+	assert.Nil(t, fs.Parse([]string{"--warn-pm25", "0"}))
+	if assert.NotNil(t, th.warn) {
+		assert.Equal(t, 0.0, *th.warn)
+	}
+	assert.Nil(t, th.crit)
+	assert.Error(t, fs.Parse([]string{"--crit-pm25", "x"}))
+}
+
+func TestFormatThreshold(t *testing.T) {
+	zero, v := 0.0, 25.5
+	assert.Equal(t, "", formatThreshold(nil))
+	assert.Equal(t, "0", formatThreshold(&zero))
+	assert.Equal(t, "25.5", formatThreshold(&v))
+}
+
+func TestCheckUsage(t *testing.T) {
+	assert.Equal(t, stateUnknown, check([]string{"--warn-pm25", "x"}))
+	assert.Equal(t, stateUnknown, check([]string{"--installation", "204", "extra"}))
+}
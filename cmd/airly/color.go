@@ -0,0 +1,101 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// levelColors are colors used by Airly for index levels
+var levelColors = map[string]string{
+	"VERY_LOW":    "#6BC926",
+	"LOW":         "#D1CF1E",
+	"MEDIUM":      "#EFBB0F",
+	"HIGH":        "#EF7120",
+	"VERY_HIGH":   "#EF2A36",
+	"EXTREME":     "#B00057",
+	"AIRMAGEDDON": "#770078",
+}
+
+// ansi256 returns index of the closest color of ANSI 256-color 6x6x6 cube, false for invalid #RRGGBB color
+func ansi256(hex string) (int, bool) {
+	if len(hex) != 7 || hex[0] != '#' {
+		return 0, false
+	}
+	rgb, err := strconv.ParseUint(hex[1:], 16, 32)
+	if err != nil {
+		return 0, false
+	}
+	level := func(c uint64) int {
+		// cube levels are 0, 95, 135, 175, 215 and 255
+		if c < 48 {
+			return 0
+		}
+		if c < 115 {
+			return 1
+		}
+		return int(c-35) / 40
+	}
+	return 16 + 36*level(rgb>>16) + 6*level(rgb>>8&0xff) + level(rgb&0xff), true
+}
+
+// colorEnabled returns true if output can be colored: --no-color is not set, NO_COLOR environment variable is not
+// set (see https://no-color.org) and standard output is a terminal
+func colorEnabled(noColor bool) bool {
+	if noColor || os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	info, err := os.Stdout.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// cellColor returns ANSI color of table cell ending with index level, e.g. "35.53 LOW"
+func cellColor(cell string) (int, bool) {
+	fields := strings.Fields(cell)
+	if len(fields) == 0 {
+		return 0, false
+	}
+	hex, ok := levelColors[fields[len(fields)-1]]
+	if !ok {
+		return 0, false
+	}
+	return ansi256(hex)
+}
+
+// writeTable writes rows aligned in columns separated by 2 spaces, cells with index level are colored if color is set.
+// Colors are added after padding, so escape sequences don't break alignment
+func writeTable(w io.Writer, rows [][]string, color bool) error {
+	var widths []int
+	for _, row := range rows {
+		for i, cell := range row {
+			if i >= len(widths) {
+				widths = append(widths, 0)
+			}
+			if n := utf8.RuneCountInString(cell); n > widths[i] {
+				widths[i] = n
+			}
+		}
+	}
+	for _, row := range rows {
+		var sb strings.Builder
+		for i, cell := range row {
+			c, colored := cellColor(cell)
+			if color && colored {
+				fmt.Fprintf(&sb, "\x1b[38;5;%dm%s\x1b[0m", c, cell)
+			} else {
+				sb.WriteString(cell)
+			}
+			if i < len(row)-1 {
+				sb.WriteString(strings.Repeat(" ", widths[i]-utf8.RuneCountInString(cell)+2))
+			}
+		}
+		sb.WriteString("\n")
+		if _, err := io.WriteString(w, sb.String()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
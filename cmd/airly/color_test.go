@@ -0,0 +1,40 @@
+package main
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"testing"
+)
+
+func TestAnsi256(t *testing.T) {
+	for hex, expected := range map[string]int{
+		"#000000": 16, "#FFFFFF": 231, "#FF0000": 196, "#6BC926": 76, "#EF7120": 202, "#770078": 90,
+	} {
+		c, ok := ansi256(hex)
+		assert.True(t, ok, hex)
+		assert.Equal(t, expected, c, hex)
+	}
+	for _, hex := range []string{"", "red", "#12345", "#GG0000"} {
+		_, ok := ansi256(hex)
+		assert.False(t, ok, hex)
+	}
+}
+
+func TestWriteTable(t *testing.T) {
+	rows := [][]string{{"NAME", "VALUE"}, {"PM25", "18.7"}, {"AIRLY_CAQI", "35.53 LOW"}, {"ŁÓDŹ", "-"}}
+	var buf bytes.Buffer
+	assert.Nil(t, writeTable(&buf, rows, false))
+	assert.Equal(t, "NAME        VALUE\nPM25        18.7\nAIRLY_CAQI  35.53 LOW\nŁÓDŹ        -\n", buf.String())
+
+	buf.Reset()
+	assert.Nil(t, writeTable(&buf, [][]string{{"LOW", "x"}, {"AIRLY_CAQI", "35.53 LOW"}}, true))
+	assert.Equal(t, "\x1b[38;5;184mLOW\x1b[0m         x\nAIRLY_CAQI  \x1b[38;5;184m35.53 LOW\x1b[0m\n", buf.String())
+}
+
+func TestColorEnabled(t *testing.T) {
+	assert.False(t, colorEnabled(true))
+	defer os.Setenv("NO_COLOR", os.Getenv("NO_COLOR"))
+	os.Setenv("NO_COLOR", "1")
+	assert.False(t, colorEnabled(false))
+}
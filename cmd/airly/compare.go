@@ -0,0 +1,130 @@
+package main
+
+import (
+	"fmt"
+	"github.com/probakowski/go-airly"
+	"os"
+	"strconv"
+	"sync"
+)
+
+// maxConcurrentRequests limits number of requests sent at the same time by commands with multiple targets
+const maxConcurrentRequests = 4
+
+// installationMeasurements are measurements of one of installations requested in a single run
+type installationMeasurements struct {
+	Installation int                `json:"installation"`
+	Measurements airly.Measurements `json:"measurements"`
+	err          error
+}
+
+// fetchAll gets measurements of installations concurrently, results are in the same order as installations
+func fetchAll(client airly.Client, installations []int, options ...airly.MeasurementsOption) []installationMeasurements {
+	results := make([]installationMeasurements, len(installations))
+	limit := make(chan struct{}, maxConcurrentRequests)
+	var wg sync.WaitGroup
+	for i, id := range installations {
+		results[i].Installation = id
+		wg.Add(1)
+		go func(r *installationMeasurements) {
+			defer wg.Done()
+			limit <- struct{}{}
+			defer func() { <-limit }()
+			r.Measurements, r.err = client.InstallationMeasurements(r.Installation, options...)
+		}(&results[i])
+	}
+	wg.Wait()
+	return results
+}
+
+// comparisonTable returns table with current values of installations side by side, one row per installation.
+// Columns are all names of values and indexes in order of appearance, indexes include level
+func comparisonTable(results []installationMeasurements) [][]string {
+	header := []string{"INSTALLATION"}
+	columns := map[string]int{}
+	column := func(name string) int {
+		if i, ok := columns[name]; ok {
+			return i
+		}
+		header = append(header, name)
+		columns[name] = len(header) - 1
+		return len(header) - 1
+	}
+	cells := make([]map[int]string, len(results))
+	for i, r := range results {
+		cells[i] = map[int]string{}
+		for _, v := range r.Measurements.Current.Values {
+			cells[i][column(v.Name)] = formatValue(v)
+		}
+		for _, index := range r.Measurements.Current.Indexes {
+			cells[i][column(index.Name)] = formatFloat(index.Value) + " " + index.Level
+		}
+	}
+	rows := [][]string{header}
+	for i, r := range results {
+		row := make([]string, len(header))
+		row[0] = strconv.Itoa(r.Installation)
+		for j := 1; j < len(row); j++ {
+			if cell, ok := cells[i][j]; ok {
+				row[j] = cell
+			} else {
+				row[j] = "-"
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// compareInstallations prints measurements of multiple installations fetched concurrently, it returns exit code
+// like measurements command
+func compareInstallations(client airly.Client, installations []int, options []airly.MeasurementsOption,
+	out *outputOptions, history, forecast bool, templateFile string, failIf conditions) int {
+	results := fetchAll(client, installations, options...)
+	code := exitOK
+	var fetched []installationMeasurements
+	for _, r := range results {
+		if r.err != nil {
+			printInstallationError(r.Installation, r.err)
+			code = exitError
+			continue
+		}
+		if !history {
+			r.Measurements.History = nil
+		}
+		if !forecast {
+			r.Measurements.Forecast = nil
+		}
+		fetched = append(fetched, r)
+	}
+
+	var err error
+	if templateFile != "" {
+		for _, r := range fetched {
+			if err = executeTemplate(os.Stdout, templateFile, r.Measurements); err != nil {
+				break
+			}
+		}
+	} else {
+		err = out.print(fetched, func() [][]string { return comparisonTable(fetched) })
+	}
+	if err != nil {
+		printError(err)
+		return exitError
+	}
+
+	for _, r := range fetched {
+		failed, err := failIf.failed(r.Measurements.Current)
+		if err != nil {
+			printInstallationError(r.Installation, err)
+			return exitError
+		}
+		for _, c := range failed {
+			fmt.Fprintf(os.Stderr, "installation %d: condition met: %s\n", r.Installation, c)
+		}
+		if len(failed) > 0 && code == exitOK {
+			code = exitFailed
+		}
+	}
+	return code
+}
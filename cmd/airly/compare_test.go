@@ -0,0 +1,69 @@
+package main
+
+import (
+	"fmt"
+	"github.com/probakowski/go-airly"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestFetchAll(t *testing.T) {
+	var mu sync.Mutex
+	var running, maxRunning int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		running++
+		if running > maxRunning {
+			maxRunning = running
+		}
+		mu.Unlock()
+		time.Sleep(10 * time.Millisecond)
+		mu.Lock()
+		running--
+		mu.Unlock()
+		id := r.URL.Query().Get("installationId")
+		if id == "911" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprintf(w, `{"current": {"values": [{"name": "PM25", "value": %s}]}}`, id)
+	}))
+	defer server.Close()
+	client := airly.Client{BaseURLs: []string{server.URL}, HttpClient: server.Client()}
+
+	installations := []int{204, 8077, 911, 1, 2, 3, 4, 5}
+	results := fetchAll(client, installations)
+	assert.Len(t, results, len(installations))
+	for i, r := range results {
+		assert.Equal(t, installations[i], r.Installation)
+		if r.Installation == 911 {
+			assert.Error(t, r.err)
+			continue
+		}
+		assert.NoError(t, r.err)
+		assert.Equal(t, []airly.Value{{Name: "PM25", Value: float64(r.Installation)}}, r.Measurements.Current.Values)
+	}
+	assert.True(t, maxRunning > 1, "requests should be sent concurrently")
+	assert.True(t, maxRunning <= maxConcurrentRequests, "at most %d requests should be sent at once", maxConcurrentRequests)
+}
+
+func TestComparisonTable(t *testing.T) {
+	results := []installationMeasurements{
+		{Installation: 204, Measurements: airly.Measurements{Current: airly.Measurement{
+			Values:  []airly.Value{{Name: "PM25", Value: 18.7}, {Name: "PM10", Value: 25}},
+			Indexes: []airly.Index{{Name: "AIRLY_CAQI", Value: 35.53, Level: "LOW"}},
+		}}},
+		{Installation: 8077, Measurements: airly.Measurements{Current: airly.Measurement{
+			Values: []airly.Value{{Name: "PM10", Value: 40}, {Name: "NO2", Value: 12}},
+		}}},
+	}
+	assert.Equal(t, [][]string{
+		{"INSTALLATION", "PM25", "PM10", "AIRLY_CAQI", "NO2"},
+		{"204", "18.7", "25", "35.53 LOW", "-"},
+		{"8077", "-", "40", "-", "12"},
+	}, comparisonTable(results))
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/probakowski/go-airly"
+	"strconv"
+	"strings"
+)
+
+// condition makes command exit with exitFailed when named value is above (or below) limit
+type condition struct {
+	name  string
+	limit float64
+	above bool
+}
+
+func (c condition) String() string {
+	op := "<"
+	if c.above {
+		op = ">"
+	}
+	return fmt.Sprintf("%s %s %s", c.name, op, formatFloat(c.limit))
+}
+
+type conditions []condition
+
+// flags registers --fail-above and --fail-below flags, both can be repeated
+func (c *conditions) flags(fs *flag.FlagSet) {
+	fs.Var(conditionFlag{c, true}, "fail-above", "Exit with code 3 if value is above limit, NAME:LIMIT, e.g. CAQI:75")
+	fs.Var(conditionFlag{c, false}, "fail-below", "Exit with code 3 if value is below limit, NAME:LIMIT")
+}
+
+// failed returns conditions met by measurement, missing value is an error
+func (c conditions) failed(m airly.Measurement) ([]condition, error) {
+	var failed []condition
+	for _, cond := range c {
+		v, ok := value(m, cond.name)
+		if !ok {
+			return nil, fmt.Errorf("no value %s in measurement", cond.name)
+		}
+		if (cond.above && v > cond.limit) || (!cond.above && v < cond.limit) {
+			failed = append(failed, cond)
+		}
+	}
+	return failed, nil
+}
+
+type conditionFlag struct {
+	conditions *conditions
+	above      bool
+}
+
+func (f conditionFlag) String() string {
+	return ""
+}
+
+func (f conditionFlag) Set(s string) error {
+	i := strings.LastIndex(s, ":")
+	if i <= 0 {
+		return fmt.Errorf("expected NAME:LIMIT, got %q", s)
+	}
+	limit, err := strconv.ParseFloat(s[i+1:], 64)
+	if err != nil {
+		return err
+	}
+	*f.conditions = append(*f.conditions, condition{s[:i], limit, f.above})
+	return nil
+}
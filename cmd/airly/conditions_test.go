@@ -0,0 +1,34 @@
+package main
+
+import (
+	"flag"
+	"github.com/probakowski/go-airly"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestConditions(t *testing.T) {
+	var c conditions
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	c.flags(fs)
+	assert.Nil(t, fs.Parse([]string{"--fail-above", "CAQI:75", "--fail-above", "pm25:25", "--fail-below", "TEMPERATURE:0"}))
+	assert.Equal(t, conditions{{"CAQI", 75, true}, {"pm25", 25, true}, {"TEMPERATURE", 0, false}}, c)
+
+	failed, err := c.failed(airly.Measurement{
+		Values:  []airly.Value{{Name: "PM25", Value: 30}, {Name: "TEMPERATURE", Value: -2}},
+		Indexes: []airly.Index{{Name: "AIRLY_CAQI", Value: 40}},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, []condition{{"pm25", 25, true}, {"TEMPERATURE", 0, false}}, failed)
+	assert.Equal(t, "pm25 > 25", failed[0].String())
+
+	_, err = c.failed(airly.Measurement{})
+	assert.NotNil(t, err)
+}
+
+func TestConditionFlagInvalid(t *testing.T) {
+	var c conditions
+	assert.NotNil(t, conditionFlag{&c, true}.Set("CAQI"))
+	assert.NotNil(t, conditionFlag{&c, true}.Set("CAQI:x"))
+	assert.Empty(t, c)
+}
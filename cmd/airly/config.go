@@ -0,0 +1,92 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// config is read from JSON file at configPath, e.g. {"aliases": {"home": 204, "office": 8077}}
+type config struct {
+	// Aliases are names that can be used wherever installation ID is expected
+	Aliases map[string]int `json:"aliases"`
+}
+
+// loadedConfig is read on first use by currentConfig
+var loadedConfig *config
+
+// configPath returns AIRLY_CONFIG environment variable or airly/config.json in user configuration directory
+func configPath() string {
+	if path := os.Getenv("AIRLY_CONFIG"); path != "" {
+		return path
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "airly", "config.json")
+}
+
+// currentConfig returns configuration read from configPath, empty configuration is used if the file doesn't exist
+func currentConfig() (*config, error) {
+	if loadedConfig != nil {
+		return loadedConfig, nil
+	}
+	c, err := readConfig(configPath())
+	if err != nil {
+		return nil, err
+	}
+	loadedConfig = c
+	return c, nil
+}
+
+func readConfig(path string) (*config, error) {
+	c := &config{}
+	if path == "" {
+		return c, nil
+	}
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, c); err != nil {
+		return nil, fmt.Errorf("invalid config file %s: %v", path, err)
+	}
+	return c, nil
+}
+
+// installationID parses installation ID given as a number or alias defined in config
+func installationID(s string) (int, error) {
+	s = strings.TrimSpace(s)
+	if id, err := strconv.Atoi(s); err == nil {
+		return id, nil
+	}
+	c, err := currentConfig()
+	if err != nil {
+		return 0, err
+	}
+	if id, ok := c.Aliases[s]; ok {
+		return id, nil
+	}
+	return 0, fmt.Errorf("invalid installation ID %q, it's neither a number nor an alias defined in %s", s, configPath())
+}
+
+// installationFlag is a flag accepting installation ID or alias
+type installationFlag int
+
+func (f *installationFlag) String() string {
+	return strconv.Itoa(int(*f))
+}
+
+func (f *installationFlag) Set(s string) error {
+	id, err := installationID(s)
+	*f = installationFlag(id)
+	return err
+}
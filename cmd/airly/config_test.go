@@ -0,0 +1,50 @@
+package main
+
+import (
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReadConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "airly")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "config.json")
+
+	c, err := readConfig(path)
+	assert.NoError(t, err)
+	assert.Equal(t, &config{}, c)
+
+	assert.NoError(t, ioutil.WriteFile(path, []byte(`{"aliases": {"home": 204, "office": 8077}}`), 0600))
+	c, err = readConfig(path)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int{"home": 204, "office": 8077}, c.Aliases)
+
+	assert.NoError(t, ioutil.WriteFile(path, []byte(`{"aliases": {"home": "x"}}`), 0600))
+	_, err = readConfig(path)
+	assert.Error(t, err)
+}
+
+func TestInstallationID(t *testing.T) {
+	defer func(c *config) { loadedConfig = c }(loadedConfig)
+	loadedConfig = &config{Aliases: map[string]int{"home": 204}}
+
+	id, err := installationID("8077")
+	assert.NoError(t, err)
+	assert.Equal(t, 8077, id)
+	id, err = installationID("home")
+	assert.NoError(t, err)
+	assert.Equal(t, 204, id)
+	_, err = installationID("office")
+	assert.Error(t, err)
+
+	var f installationFlag
+	assert.NoError(t, f.Set("home"))
+	assert.Equal(t, "204", f.String())
+	var l installationList
+	assert.NoError(t, l.Set("home,911"))
+	assert.Equal(t, installationList{204, 911}, l)
+}
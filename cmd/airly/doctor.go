@@ -0,0 +1,195 @@
+package main
+
+import (
+	"crypto/tls"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/probakowski/go-airly"
+	"net"
+	"net/url"
+	"os"
+	"sort"
+	"time"
+)
+
+func init() {
+	commands["doctor"] = command{"Check configuration, connectivity, API key and configured installations", doctor}
+}
+
+// doctorTimeout limits connection checks of doctor command
+const doctorTimeout = 10 * time.Second
+
+// diagnosis is result of a single doctor check
+type diagnosis struct {
+	Check  string `json:"check"`
+	Status string `json:"status"`
+	Detail string `json:"detail"`
+}
+
+// Statuses of doctor checks
+const (
+	statusPass = "PASS"
+	statusWarn = "WARN"
+	statusFail = "FAIL"
+)
+
+func doctor(args []string) int {
+	fs := flag.NewFlagSet("doctor", flag.ContinueOnError)
+	client := clientFlags(fs)
+	out := outputFlags(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return exitUsage
+	}
+	results := diagnose(*client, nil)
+	if err := out.print(results, func() [][]string { return doctorTable(results) }); err != nil {
+		printError(err)
+		return exitError
+	}
+	for _, r := range results {
+		if r.Status == statusFail {
+			return exitFailed
+		}
+	}
+	return exitOK
+}
+
+// diagnose runs all checks, tlsConfig is used for TLS handshakes, nil means default configuration
+func diagnose(client airly.Client, tlsConfig *tls.Config) []diagnosis {
+	var results []diagnosis
+	add := func(check, status, detail string) {
+		results = append(results, diagnosis{Check: check, Status: status, Detail: detail})
+	}
+
+	cfg, err := currentConfig()
+	if err != nil {
+		add("config", statusFail, err.Error())
+		cfg = &config{}
+	} else if _, statErr := os.Stat(configPath()); statErr != nil {
+		add("config", statusPass, "no config file at "+configPath()+", defaults are used")
+	} else {
+		add("config", statusPass, fmt.Sprintf("%s, %d aliases", configPath(), len(cfg.Aliases)))
+	}
+
+	baseURLs := client.BaseURLs
+	if len(baseURLs) == 0 {
+		baseURLs = []string{airly.DefaultBaseURL}
+	}
+	for _, baseURL := range baseURLs {
+		detail, err := checkConnection(baseURL, tlsConfig)
+		if err != nil {
+			add("connection "+baseURL, statusFail, err.Error())
+		} else {
+			add("connection "+baseURL, statusPass, detail)
+		}
+	}
+
+	if client.Key == "" {
+		add("API key", statusFail, "not set, use --key or AIRLY_API_KEY")
+		return results
+	}
+	rateLimit, err := client.RateLimit()
+	var apiErr *airly.APIError
+	switch {
+	case errors.As(err, &apiErr) && (apiErr.StatusCode == 401 || apiErr.StatusCode == 403):
+		add("API key", statusFail, "rejected by API: "+err.Error())
+		return results
+	case err != nil && rateLimit.DayLimit == 0:
+		add("API key", statusFail, err.Error())
+		return results
+	}
+	add("API key", statusPass, "accepted")
+	switch {
+	case rateLimit.DayLimit == 0:
+		add("rate limit", statusWarn, "rate limit headers missing in response")
+	case rateLimit.DayRemaining == 0 || rateLimit.MinuteRemaining == 0:
+		add("rate limit", statusWarn, formatRateLimit(rateLimit))
+	default:
+		add("rate limit", statusPass, formatRateLimit(rateLimit))
+	}
+
+	aliases := make([]string, 0, len(cfg.Aliases))
+	for alias := range cfg.Aliases {
+		aliases = append(aliases, alias)
+	}
+	sort.Strings(aliases)
+	for _, alias := range aliases {
+		id := cfg.Aliases[alias]
+		check := fmt.Sprintf("installation %s (%d)", alias, id)
+		if i, err := client.Installation(id); err != nil {
+			add(check, statusFail, err.Error())
+		} else {
+			add(check, statusPass, i.Address.DisplayAddress1+" "+i.Address.DisplayAddress2)
+		}
+	}
+	return results
+}
+
+// checkConnection connects to host of baseURL and, for HTTPS, performs TLS handshake, it returns description of
+// connection
+func checkConnection(baseURL string, tlsConfig *tls.Config) (string, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return "", err
+	}
+	host := u.Host
+	if u.Port() == "" {
+		if u.Scheme == "https" {
+			host = net.JoinHostPort(u.Hostname(), "443")
+		} else {
+			host = net.JoinHostPort(u.Hostname(), "80")
+		}
+	}
+	dialer := &net.Dialer{Timeout: doctorTimeout}
+	start := time.Now()
+	if u.Scheme != "https" {
+		conn, err := dialer.Dial("tcp", host)
+		if err != nil {
+			return "", err
+		}
+		_ = conn.Close()
+		return fmt.Sprintf("connected in %s, without TLS", time.Since(start).Round(time.Millisecond)), nil
+	}
+	if tlsConfig == nil {
+		tlsConfig = &tls.Config{}
+	}
+	conn, err := tls.DialWithDialer(dialer, "tcp", host, tlsConfig)
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+	state := conn.ConnectionState()
+	detail := fmt.Sprintf("connected in %s, %s", time.Since(start).Round(time.Millisecond), tlsVersion(state.Version))
+	if len(state.PeerCertificates) > 0 {
+		detail += ", certificate valid until " + state.PeerCertificates[0].NotAfter.Format("2006-01-02")
+	}
+	return detail, nil
+}
+
+// tlsVersion returns name of TLS version, e.g. TLS 1.3
+func tlsVersion(version uint16) string {
+	switch version {
+	case tls.VersionTLS10:
+		return "TLS 1.0"
+	case tls.VersionTLS11:
+		return "TLS 1.1"
+	case tls.VersionTLS12:
+		return "TLS 1.2"
+	case tls.VersionTLS13:
+		return "TLS 1.3"
+	}
+	return fmt.Sprintf("TLS 0x%04X", version)
+}
+
+func formatRateLimit(r airly.RateLimit) string {
+	return fmt.Sprintf("%d of %d daily and %d of %d per minute requests remaining", r.DayRemaining, r.DayLimit,
+		r.MinuteRemaining, r.MinuteLimit)
+}
+
+func doctorTable(results []diagnosis) [][]string {
+	rows := [][]string{{"CHECK", "STATUS", "DETAIL"}}
+	for _, r := range results {
+		rows = append(rows, []string{r.Check, r.Status, r.Detail})
+	}
+	return rows
+}
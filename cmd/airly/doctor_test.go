@@ -0,0 +1,84 @@
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"github.com/probakowski/go-airly"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDiagnose(t *testing.T) {
+	dir, err := ioutil.TempDir("", "airly")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "config.json")
+	assert.NoError(t, ioutil.WriteFile(path, []byte(`{"aliases": {"home": 204, "old": 911}}`), 0600))
+	defer os.Setenv("AIRLY_CONFIG", os.Getenv("AIRLY_CONFIG"))
+	os.Setenv("AIRLY_CONFIG", path)
+	defer func(c *config) { loadedConfig = c }(loadedConfig)
+	loadedConfig = nil
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Apikey") != "key" {
+			w.WriteHeader(401)
+			return
+		}
+		switch r.URL.Path {
+		case "/v2/meta/indexes":
+			w.Header().Set("X-RateLimit-Limit-day", "100")
+			w.Header().Set("X-RateLimit-Remaining-day", "42")
+			w.Header().Set("X-RateLimit-Limit-minute", "50")
+			w.Header().Set("X-RateLimit-Remaining-minute", "49")
+			fmt.Fprint(w, `[]`)
+		case "/v2/installations/204":
+			fmt.Fprint(w, `{"id": 204, "address": {"displayAddress1": "Kraków", "displayAddress2": "Mikołajska"}}`)
+		default:
+			w.WriteHeader(404)
+		}
+	}))
+	defer server.Close()
+	roots := x509.NewCertPool()
+	roots.AddCert(server.Certificate())
+	client := airly.Client{Key: "key", BaseURLs: []string{server.URL}, HttpClient: server.Client(),
+		RequestID: func() string { return "1" }}
+
+	results := diagnose(client, &tls.Config{RootCAs: roots})
+	var statuses []string
+	for _, r := range results {
+		statuses = append(statuses, r.Check+" "+r.Status)
+	}
+	assert.Equal(t, []string{
+		"config PASS",
+		"connection " + server.URL + " PASS",
+		"API key PASS",
+		"rate limit PASS",
+		"installation home (204) PASS",
+		"installation old (911) FAIL",
+	}, statuses)
+	assert.Equal(t, path+", 2 aliases", results[0].Detail)
+	assert.True(t, strings.HasPrefix(results[1].Detail, "connected in "), results[1].Detail)
+	assert.Contains(t, results[1].Detail, "TLS 1.3")
+	assert.Equal(t, "42 of 100 daily and 49 of 50 per minute requests remaining", results[3].Detail)
+	assert.Equal(t, "Kraków Mikołajska", results[4].Detail)
+
+	client.Key = "invalid"
+	results = diagnose(client, &tls.Config{})
+	assert.Equal(t, statusFail, results[1].Status, "certificate of test server is not trusted")
+	assert.Equal(t, diagnosis{Check: "API key", Status: statusFail, Detail: "rejected by API: 401:  (request ID 1)"},
+		results[2])
+	assert.Len(t, results, 3)
+}
+
+func TestTLSVersion(t *testing.T) {
+	assert.Equal(t, "TLS 1.2", tlsVersion(tls.VersionTLS12))
+	assert.Equal(t, "TLS 1.3", tlsVersion(tls.VersionTLS13))
+	assert.Equal(t, "TLS 0x0300", tlsVersion(0x0300))
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/probakowski/go-airly"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// errorFormat is format of errors printed by printError, text or json, set with --error-format
+var errorFormat = "text"
+
+// errorFormatFlag sets errorFormat, it accepts text or json
+type errorFormatFlag struct{}
+
+func (errorFormatFlag) String() string {
+	return errorFormat
+}
+
+func (errorFormatFlag) Set(s string) error {
+	if s != "text" && s != "json" {
+		return fmt.Errorf("unknown error format %q, text or json expected", s)
+	}
+	errorFormat = s
+	return nil
+}
+
+// errorFlags registers --error-format flag shared by all commands
+func errorFlags(fs *flag.FlagSet) {
+	fs.Var(errorFormatFlag{}, "error-format", "Format of errors printed on standard error, text or json "+
+		"(one object per line with errorKind, message, status, endpoint, requestId and retryAfter)")
+}
+
+// scanErrorFormat sets errorFormat from --error-format in args, it's used if flags can't be parsed
+// so the parse error is printed in requested format
+func scanErrorFormat(args []string) {
+	for i, arg := range args {
+		if arg == "--" {
+			return
+		}
+		name := strings.TrimLeft(arg, "-")
+		if name == arg {
+			continue
+		}
+		if v := strings.TrimPrefix(name, "error-format="); v != name {
+			_ = errorFormatFlag{}.Set(v)
+		} else if name == "error-format" && i+1 < len(args) {
+			_ = errorFormatFlag{}.Set(args[i+1])
+		}
+	}
+}
+
+// usageError is invalid usage of command, e.g. missing or conflicting arguments
+type usageError string
+
+func (e usageError) Error() string {
+	return string(e)
+}
+
+// errorOutput is error printed in JSON format, fields are stable so scripts can rely on them
+type errorOutput struct {
+	// ErrorKind is one of usage, unauthorized, rate_limited, not_found, server, api, invalid_location,
+	// no_installation, decode, response_too_large, timeout, network or other
+	ErrorKind  string `json:"errorKind"`
+	Message    string `json:"message"`
+	Status     int    `json:"status,omitempty"`
+	Endpoint   string `json:"endpoint,omitempty"`
+	RequestID  string `json:"requestId,omitempty"`
+	RetryAfter int    `json:"retryAfter,omitempty"`
+	// Retryable is set if the same command may succeed if repeated, see airly.IsRetryable
+	Retryable    bool `json:"retryable"`
+	Installation int  `json:"installation,omitempty"`
+}
+
+// errorKind classifies err for errorOutput
+func errorKind(err error) string {
+	var apiErr *airly.APIError
+	var decodeErr *airly.DecodeError
+	var tooLargeErr *airly.ResponseTooLargeError
+	var netErr net.Error
+	var urlErr *url.Error
+	var opErr *net.OpError
+	var usageErr usageError
+	switch {
+	case errors.As(err, &usageErr):
+		return "usage"
+	case errors.As(err, &apiErr):
+		switch {
+		case apiErr.StatusCode == 401 || apiErr.StatusCode == 403:
+			return "unauthorized"
+		case apiErr.StatusCode == 429:
+			return "rate_limited"
+		case apiErr.StatusCode == 404:
+			return "not_found"
+		case apiErr.StatusCode >= 500:
+			return "server"
+		}
+		return "api"
+	case errors.Is(err, airly.ErrInvalidLocation):
+		return "invalid_location"
+	case errors.Is(err, airly.ErrNoInstallation):
+		return "no_installation"
+	case errors.As(err, &decodeErr):
+		return "decode"
+	case errors.As(err, &tooLargeErr):
+		return "response_too_large"
+	case errors.As(err, &netErr) && netErr.Timeout():
+		return "timeout"
+	case errors.As(err, &urlErr), errors.As(err, &opErr):
+		return "network"
+	}
+	return "other"
+}
+
+// newErrorOutput returns errorOutput describing err
+func newErrorOutput(err error) errorOutput {
+	o := errorOutput{ErrorKind: errorKind(err), Message: err.Error(), Retryable: airly.IsRetryable(err)}
+	var apiErr *airly.APIError
+	if errors.As(err, &apiErr) {
+		o.Status = apiErr.StatusCode
+		o.RetryAfter = int(apiErr.RetryAfter.Seconds())
+	}
+	var decodeErr *airly.DecodeError
+	if errors.As(err, &decodeErr) {
+		o.Endpoint = decodeErr.Endpoint
+	}
+	var requestErr *airly.RequestError
+	if errors.As(err, &requestErr) {
+		o.Endpoint = requestErr.Endpoint
+		o.RequestID = requestErr.RequestID
+	}
+	return o
+}
+
+// writeError writes err as text or, with json format, as errorOutput in a single line
+func writeError(w io.Writer, format string, o errorOutput) {
+	if format == "json" {
+		if b, err := json.Marshal(o); err == nil {
+			fmt.Fprintln(w, string(b))
+			return
+		}
+	}
+	if o.Installation != 0 {
+		fmt.Fprintf(w, "installation %d: %s\n", o.Installation, o.Message)
+		return
+	}
+	fmt.Fprintln(w, o.Message)
+}
+
+// printError prints err on standard error in errorFormat
+func printError(err error) {
+	writeError(os.Stderr, errorFormat, newErrorOutput(err))
+}
+
+// printInstallationError prints err of one of installations requested in a single run
+func printInstallationError(installation int, err error) {
+	o := newErrorOutput(err)
+	o.Installation = installation
+	writeError(os.Stderr, errorFormat, o)
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/probakowski/go-airly"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"testing"
+)
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func TestErrorKind(t *testing.T) {
+	for kind, err := range map[string]error{
+		"unauthorized":       &airly.APIError{StatusCode: 401},
+		"rate_limited":       &airly.RequestError{Err: &airly.APIError{StatusCode: 429}},
+		"not_found":          &airly.APIError{StatusCode: 404},
+		"server":             &airly.APIError{StatusCode: 503},
+		"api":                &airly.APIError{StatusCode: 400},
+		"invalid_location":   fmt.Errorf("lat 91: %w", airly.ErrInvalidLocation),
+		"no_installation":    airly.ErrNoInstallation,
+		"decode":             &airly.DecodeError{Err: errors.New("x")},
+		"response_too_large": &airly.ResponseTooLargeError{Limit: 1},
+		"timeout":            fmt.Errorf("get: %w", timeoutError{}),
+		"usage":              usageError("x"),
+		"network":            &url.Error{Op: "Get", Err: errors.New(`unsupported protocol scheme "ftp"`)},
+		"other":              errors.New("x"),
+	} {
+		assert.Equal(t, kind, errorKind(err), err.Error())
+	}
+	assert.Equal(t, "other", errorKind(&os.PathError{Op: "open", Path: "audit.jsonl", Err: os.ErrNotExist}))
+}
+
+func TestWriteError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "60")
+		w.WriteHeader(429)
+		fmt.Fprint(w, "Too Many Requests")
+	}))
+	defer server.Close()
+	client := airly.Client{BaseURLs: []string{server.URL}, RequestID: func() string { return "42" }}
+	_, err := client.InstallationMeasurements(204)
+	var buf bytes.Buffer
+	writeError(&buf, "json", newErrorOutput(err))
+	assert.Equal(t, `{"errorKind":"rate_limited","message":"429: Too Many Requests (request ID 42)","status":429,`+
+		`"endpoint":"measurements/installation","requestId":"42","retryAfter":60,"retryable":true}`+"\n", buf.String())
+
+	buf.Reset()
+	writeError(&buf, "text", newErrorOutput(err))
+	assert.Equal(t, "429: Too Many Requests (request ID 42)\n", buf.String())
+
+	buf.Reset()
+	o := newErrorOutput(errors.New("failed"))
+	o.Installation = 204
+	writeError(&buf, "text", o)
+	writeError(&buf, "json", o)
+	assert.Equal(t, "installation 204: failed\n"+`{"errorKind":"other","message":"failed","retryable":false,"installation":204}`+"\n",
+		buf.String())
+}
+
+func TestErrorFlags(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	errorFlags(fs)
+	assert.Nil(t, fs.Parse([]string{"--error-format", "json"}))
+	assert.Equal(t, "json", errorFormat)
+	assert.NotNil(t, fs.Parse([]string{"--error-format", "xml"}))
+	assert.Equal(t, "json", errorFormat)
+	errorFormat = "text"
+}
+
+func TestScanErrorFormat(t *testing.T) {
+	scanErrorFormat([]string{"--lat", "abc", "--error-format", "json"})
+	assert.Equal(t, "json", errorFormat)
+	scanErrorFormat([]string{"-error-format=text"})
+	assert.Equal(t, "text", errorFormat)
+	scanErrorFormat([]string{"--error-format", "xml", "--", "--error-format=json"})
+	assert.Equal(t, "text", errorFormat)
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/probakowski/go-airly"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+)
+
+// geolocationClient is used to query IP geolocation services
+var geolocationClient = &http.Client{Timeout: 10 * time.Second}
+
+// geolocationResponse covers fields used by popular IP geolocation services
+type geolocationResponse struct {
+	Latitude  *float64 `json:"latitude"`
+	Longitude *float64 `json:"longitude"`
+	Lat       *float64 `json:"lat"`
+	Lon       *float64 `json:"lon"`
+	Lng       *float64 `json:"lng"`
+	Loc       string   `json:"loc"`
+}
+
+// geolocate returns approximate location of public IP address of this machine from IP geolocation service at url.
+// JSON responses with latitude and longitude (e.g. https://ipapi.co/json/), lat and lon (http://ip-api.com/json/),
+// lat and lng or loc (https://ipinfo.io/json) fields are supported
+func geolocate(client *http.Client, url string) (airly.Location, error) {
+	res, err := client.Get(url)
+	if err != nil {
+		return airly.Location{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return airly.Location{}, fmt.Errorf("IP geolocation failed with status %s", res.Status)
+	}
+	body, err := ioutil.ReadAll(io.LimitReader(res.Body, 1<<20))
+	if err != nil {
+		return airly.Location{}, err
+	}
+	var r geolocationResponse
+	if err := json.Unmarshal(body, &r); err != nil {
+		return airly.Location{}, fmt.Errorf("invalid IP geolocation response: %v", err)
+	}
+	switch {
+	case r.Latitude != nil && r.Longitude != nil:
+		return validLocation(*r.Latitude, *r.Longitude)
+	case r.Lat != nil && r.Lon != nil:
+		return validLocation(*r.Lat, *r.Lon)
+	case r.Lat != nil && r.Lng != nil:
+		return validLocation(*r.Lat, *r.Lng)
+	case r.Loc != "":
+		return airly.ParseLocation(r.Loc)
+	}
+	return airly.Location{}, fmt.Errorf("IP geolocation response doesn't contain location")
+}
+
+func validLocation(lat, lng float64) (airly.Location, error) {
+	loc := airly.Location{Latitude: lat, Longitude: lng}
+	return loc, loc.Validate()
+}
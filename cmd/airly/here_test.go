@@ -0,0 +1,42 @@
+package main
+
+import (
+	"github.com/probakowski/go-airly"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGeolocate(t *testing.T) {
+	for body, expected := range map[string]airly.Location{
+		`{"ip":"1.2.3.4","city":"Kraków","latitude":50.0614,"longitude":19.9366}`: {Latitude: 50.0614, Longitude: 19.9366},
+		`{"status":"success","lat":50.0614,"lon":19.9366}`:                        {Latitude: 50.0614, Longitude: 19.9366},
+		`{"lat":50.0614,"lng":19.9366}`:                                           {Latitude: 50.0614, Longitude: 19.9366},
+		`{"ip":"1.2.3.4","loc":"50.0614,19.9366"}`:                                {Latitude: 50.0614, Longitude: 19.9366},
+	} {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(body))
+		}))
+		loc, err := geolocate(server.Client(), server.URL)
+		server.Close()
+		assert.NoError(t, err, body)
+		assert.Equal(t, expected, loc, body)
+	}
+
+	for _, body := range []string{`{"ip":"1.2.3.4"}`, `{"latitude":100,"longitude":0}`, `<html>`} {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(body))
+		}))
+		_, err := geolocate(server.Client(), server.URL)
+		server.Close()
+		assert.Error(t, err, body)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+	_, err := geolocate(server.Client(), server.URL)
+	assert.EqualError(t, err, "IP geolocation failed with status 429 Too Many Requests")
+}
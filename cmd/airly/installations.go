@@ -0,0 +1,106 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/probakowski/go-airly"
+	"strconv"
+	"strings"
+)
+
+func init() {
+	commands["installations"] = command{"Find installations: get <id>, nearest, search", installations}
+}
+
+func installations(args []string) int {
+	if len(args) == 0 {
+		printError(usageError("Usage: airly installations get|nearest|search [flags]"))
+		return exitUsage
+	}
+	fs := flag.NewFlagSet("installations "+args[0], flag.ContinueOnError)
+	client := clientFlags(fs)
+	out := outputFlags(fs)
+	var positional []string
+	var center *airly.Location
+	var run func() ([]airly.Installation, error)
+	switch args[0] {
+	case "get":
+		run = func() ([]airly.Installation, error) {
+			id, err := installationID(positional[0])
+			if err != nil {
+				return nil, err
+			}
+			i, err := client.Installation(id)
+			return []airly.Installation{i}, err
+		}
+	case "nearest":
+		loc := locationFlags(fs, "")
+		maxDistance := fs.Float64("max-distance", 3, "Maximum distance in km")
+		maxResults := fs.Int("max-results", 1, "Maximum number of results, -1 means no limit")
+		run = func() ([]airly.Installation, error) {
+			return client.NearestInstallations(*loc,
+				airly.MaxDistance(*maxDistance), airly.MaxResults(*maxResults))
+		}
+	case "search":
+		city := fs.String("city", "", "City to search installations in")
+		loc := locationFlags(fs, " of the search area center, required as API can't look up cities")
+		center = loc
+		maxDistance := fs.Float64("max-distance", 25, "Search area radius in km")
+		run = func() ([]airly.Installation, error) {
+			all, err := client.NearestInstallations(*loc,
+				airly.MaxDistance(*maxDistance), airly.AllResults())
+			return filterCity(all, *city), err
+		}
+	default:
+		printError(usageError(fmt.Sprintf("unknown installations mode %q", args[0])))
+		return exitUsage
+	}
+	var err error
+	if positional, err = parse(fs, args[1:]); err != nil {
+		return exitUsage
+	}
+	if args[0] == "get" && len(positional) != 1 {
+		printError(usageError("Usage: airly installations get <id|alias> [flags]"))
+		return exitUsage
+	}
+	if center != nil && *center == (airly.Location{}) {
+		printError(usageError("Usage: airly installations search --lat <lat> --lng <lng> [--city city] [flags]"))
+		return exitUsage
+	}
+
+	result, err := run()
+	if err != nil {
+		printError(err)
+		return exitError
+	}
+	var v interface{} = result
+	if args[0] == "get" {
+		v = result[0]
+	}
+	if err := out.print(v, func() [][]string { return installationsTable(result) }); err != nil {
+		printError(err)
+		return exitError
+	}
+	return exitOK
+}
+
+// filterCity returns installations in given city, ignoring case, empty city matches all installations
+func filterCity(installations []airly.Installation, city string) []airly.Installation {
+	result := []airly.Installation{}
+	for _, i := range installations {
+		if city == "" || strings.EqualFold(i.Address.City, city) {
+			result = append(result, i)
+		}
+	}
+	return result
+}
+
+func installationsTable(installations []airly.Installation) [][]string {
+	rows := [][]string{{"ID", "CITY", "STREET", "NUMBER", "LATITUDE", "LONGITUDE", "ELEVATION", "AIRLY", "SPONSOR"}}
+	for _, i := range installations {
+		rows = append(rows, []string{strconv.Itoa(i.Id), i.Address.City, i.Address.Street, i.Address.Number,
+			formatFloat(i.Location.Latitude), formatFloat(i.Location.Longitude), formatFloat(i.Elevation),
+			strconv.FormatBool(i.Airly), i.Sponsor.Name})
+	}
+	return rows
+}
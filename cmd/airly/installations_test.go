@@ -0,0 +1,31 @@
+package main
+
+import (
+	"github.com/probakowski/go-airly"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestFilterCity(t *testing.T) {
+	installations := []airly.Installation{
+		{Id: 204, Address: airly.Address{City: "Kraków"}},
+		{Id: 8077, Address: airly.Address{City: "Wieliczka"}},
+	}
+	assert.Equal(t, []airly.Installation{installations[0]}, filterCity(installations, "kraków"))
+	assert.Equal(t, installations, filterCity(installations, ""))
+	assert.Equal(t, []airly.Installation{}, filterCity(installations, "Warszawa"))
+}
+
+func TestInstallationsTable(t *testing.T) {
+	assert.Equal(t, [][]string{
+		{"ID", "CITY", "STREET", "NUMBER", "LATITUDE", "LONGITUDE", "ELEVATION", "AIRLY", "SPONSOR"},
+		{"204", "Kraków", "Mikołajska", "4B", "50.062006", "19.940984", "220.38", "true", "KrakówOddycha"},
+	}, installationsTable([]airly.Installation{{
+		Id:        204,
+		Location:  airly.Location{Latitude: 50.062006, Longitude: 19.940984},
+		Address:   airly.Address{City: "Kraków", Street: "Mikołajska", Number: "4B"},
+		Elevation: 220.38,
+		Airly:     true,
+		Sponsor:   airly.Sponsor{Name: "KrakówOddycha"},
+	}}))
+}
@@ -0,0 +1,244 @@
+// Command airly is a command line client for Airly API, see https://developer.airly.org/docs
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/probakowski/go-airly"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Exit codes of airly commands, check command uses Nagios plugin codes instead
+const (
+	exitOK = iota
+	exitError
+	exitUsage
+	exitFailed
+)
+
+// command is a single airly subcommand, run returns process exit code
+type command struct {
+	usage string
+	run   func(args []string) int
+}
+
+var commands = map[string]command{}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(exitUsage)
+	}
+	cmd, ok := commands[os.Args[1]]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n", os.Args[1])
+		usage()
+		os.Exit(exitUsage)
+	}
+	os.Exit(cmd.run(os.Args[2:]))
+}
+
+func usage() {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	fmt.Fprintln(os.Stderr, "Usage: airly <command> [flags]")
+	fmt.Fprintln(os.Stderr, "Commands:")
+	for _, name := range names {
+		fmt.Fprintf(os.Stderr, "  %-14s %s\n", name, commands[name].usage)
+	}
+}
+
+// parse parses flags allowing them to be mixed with positional arguments, which are returned. Errors are printed
+// with printError as usage errors, --help prints flag usage
+func parse(fs *flag.FlagSet, args []string) ([]string, error) {
+	fs.SetOutput(ioutil.Discard)
+	all := args
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				fs.SetOutput(os.Stderr)
+				fs.Usage()
+				return nil, err
+			}
+			scanErrorFormat(all)
+			printError(usageError(err.Error()))
+			return nil, usageError(err.Error())
+		}
+		if fs.NArg() == 0 {
+			return positional, nil
+		}
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+}
+
+// parseFlags parses flags of command which doesn't accept positional arguments, errors are printed like in parse
+func parseFlags(fs *flag.FlagSet, args []string) error {
+	positional, err := parse(fs, args)
+	if err == nil && len(positional) > 0 {
+		err = usageError(fmt.Sprintf("unexpected argument %q", positional[0]))
+		printError(err)
+	}
+	return err
+}
+
+// clientFlags registers flags shared by all commands calling Airly API
+func clientFlags(fs *flag.FlagSet) *airly.Client {
+	c := &airly.Client{}
+	fs.StringVar(&c.Key, "key", os.Getenv("AIRLY_API_KEY"), "API key, AIRLY_API_KEY environment variable is used by default")
+	c.Language = airly.English
+	fs.Var(languageFlag{&c.Language}, "lang", "Language, "+languages())
+	t := &transport{client: c, envAuditLog: os.Getenv("AIRLY_AUDIT_LOG")}
+	if t.envAuditLog != "" {
+		c.HttpClient = t
+	}
+	fs.Var(&auditLogFlag{transport: t}, "audit-log", "File to append audit log of API requests to, AIRLY_AUDIT_LOG "+
+		"environment variable is used by default, see airly audit")
+	fs.Var(&auditLogFlag{transport: t, bodies: true}, "record", "File to append audit log with response bodies to, "+
+		"it can be used with --replay in later runs")
+	fs.Var(replayFlag{t}, "replay", "Serve responses from file written with --record instead of calling API")
+	errorFlags(fs)
+	return c
+}
+
+// languageFlag sets language, it accepts values supported by airly.ParseLanguage
+type languageFlag struct {
+	language *string
+}
+
+func (f languageFlag) String() string {
+	if f.language == nil {
+		return ""
+	}
+	return *f.language
+}
+
+func (f languageFlag) Set(s string) error {
+	l, err := airly.ParseLanguage(s)
+	if err != nil {
+		return err
+	}
+	*f.language = string(l)
+	return nil
+}
+
+// languages returns supported languages for flag usage, e.g. "en or pl"
+func languages() string {
+	var codes []string
+	for _, l := range airly.SupportedLanguages() {
+		codes = append(codes, string(l))
+	}
+	return strings.Join(codes[:len(codes)-1], ", ") + " or " + codes[len(codes)-1]
+}
+
+// locationFlag sets both coordinates of location, it accepts formats supported by airly.ParseLocation
+type locationFlag struct {
+	location *airly.Location
+}
+
+func (f locationFlag) String() string {
+	if f.location == nil || *f.location == (airly.Location{}) {
+		return ""
+	}
+	return formatFloat(f.location.Latitude) + "," + formatFloat(f.location.Longitude)
+}
+
+func (f locationFlag) Set(s string) error {
+	loc, err := airly.ParseLocation(s)
+	if err != nil {
+		return err
+	}
+	*f.location = loc
+	return nil
+}
+
+// locationFlags registers --lat, --lng and --location flags setting returned location, description is appended
+// to flag usages
+func locationFlags(fs *flag.FlagSet, description string) *airly.Location {
+	loc := &airly.Location{}
+	fs.Float64Var(&loc.Latitude, "lat", 0, "Latitude"+description)
+	fs.Float64Var(&loc.Longitude, "lng", 0, "Longitude"+description)
+	fs.Var(locationFlag{loc}, "location", "Location"+description+` instead of --lat and --lng, e.g. "50.062,19.941", `+
+		`"50°03'43.2\"N 19°56'27.5\"E" or geo:50.062,19.941`)
+	return loc
+}
+
+// target selects measurements either by installation or by location
+type target struct {
+	installation int
+	location     *airly.Location
+}
+
+func targetFlags(fs *flag.FlagSet) *target {
+	t := &target{}
+	t.installation = -1
+	fs.Var((*installationFlag)(&t.installation), "installation", "Installation ID or alias to get measurements from, "+
+		"-1 means location will be used")
+	t.location = locationFlags(fs, "")
+	return t
+}
+
+func (t target) measurements(c airly.Client) (airly.Measurements, error) {
+	if t.installation == -1 {
+		return c.NearestMeasurements(*t.location)
+	}
+	return c.InstallationMeasurements(t.installation)
+}
+
+// formatValue formats value for tables, "-" is returned for missing reading
+func formatValue(v airly.Value) string {
+	if v.Null {
+		return "-"
+	}
+	return formatFloat(v.Value)
+}
+
+// value returns value with given name from measurement, indexes are matched by name as well.
+// Names are case-insensitive and AIRLY_ prefix of index names can be omitted, so CAQI matches AIRLY_CAQI
+// if there is no CAQI index
+func value(m airly.Measurement, name string) (float64, bool) {
+	for _, v := range m.Values {
+		if strings.EqualFold(v.Name, name) {
+			return v.Value, !v.Null
+		}
+	}
+	for _, prefix := range []string{"", "AIRLY_"} {
+		for _, i := range m.Indexes {
+			if strings.EqualFold(i.Name, prefix+name) {
+				return i.Value, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// installationList is a flag accepting comma separated list of installation IDs or aliases
+type installationList []int
+
+func (l *installationList) String() string {
+	s := make([]string, len(*l))
+	for i, v := range *l {
+		s[i] = strconv.Itoa(v)
+	}
+	return strings.Join(s, ",")
+}
+
+func (l *installationList) Set(s string) error {
+	for _, part := range strings.Split(s, ",") {
+		v, err := installationID(part)
+		if err != nil {
+			return err
+		}
+		*l = append(*l, v)
+	}
+	return nil
+}
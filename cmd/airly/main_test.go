@@ -0,0 +1,73 @@
+package main
+
+import (
+	"flag"
+	"github.com/probakowski/go-airly"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"testing"
+)
+
+func TestInstallationList(t *testing.T) {
+	defer func(c *config) { loadedConfig = c }(loadedConfig)
+	loadedConfig = &config{}
+	var l installationList
+	assert.Nil(t, l.Set("204, 8077"))
+	assert.Nil(t, l.Set("911"))
+	assert.Equal(t, installationList{204, 8077, 911}, l)
+	assert.Equal(t, "204,8077,911", l.String())
+	assert.NotNil(t, l.Set("x"))
+}
+
+func TestParse(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	output := fs.String("output", "json", "")
+	positional, err := parse(fs, []string{"204", "--output", "table", "8077"})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"204", "8077"}, positional)
+	assert.Equal(t, "table", *output)
+
+	fs = flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.Float64("lat", 0, "")
+	_, err = parse(fs, []string{"--lat", "abc"})
+	var usageErr usageError
+	assert.ErrorAs(t, err, &usageErr)
+	err = parseFlags(fs, []string{"--lat", "50", "204"})
+	assert.ErrorAs(t, err, &usageErr)
+	assert.EqualError(t, err, `unexpected argument "204"`)
+}
+
+func TestPositionalUsage(t *testing.T) {
+	assert.Equal(t, exitUsage, installations([]string{"get"}))
+	assert.Equal(t, exitUsage, installations([]string{"get", "204", "8077"}))
+	assert.Equal(t, exitUsage, installations([]string{"search", "--city", "Kraków"}))
+	assert.Equal(t, exitUsage, measurements([]string{"installation"}))
+	assert.Equal(t, exitUsage, measurements([]string{"installation", "204", "abc"}))
+	assert.Equal(t, exitUsage, measurements([]string{"point", "--lat", "abc"}))
+}
+
+func TestLocationFlags(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	loc := locationFlags(fs, "")
+	assert.Nil(t, fs.Parse([]string{"--location", `50°03'43.2"N 19°56'27.5"E`}))
+	assert.InDelta(t, 50.062, loc.Latitude, 1e-6)
+	assert.InDelta(t, 19.940972, loc.Longitude, 1e-6)
+	assert.Equal(t, "50.062,19.94097222222222", fs.Lookup("location").Value.String())
+
+	fs = flag.NewFlagSet("test", flag.ContinueOnError)
+	loc = locationFlags(fs, "")
+	assert.Nil(t, fs.Parse([]string{"--lat", "50.062", "--lng", "19.941"}))
+	assert.Equal(t, airly.Location{Latitude: 50.062, Longitude: 19.941}, *loc)
+	assert.NotNil(t, locationFlag{loc}.Set("91,0"))
+}
+
+func TestLanguageFlag(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(ioutil.Discard)
+	client := clientFlags(fs)
+	assert.Equal(t, airly.English, client.Language)
+	assert.Nil(t, fs.Parse([]string{"--lang", "PL"}))
+	assert.Equal(t, airly.Polish, client.Language)
+	assert.ErrorIs(t, fs.Set("lang", "eng"), airly.ErrUnsupportedLanguage)
+	assert.Equal(t, "Language, en or pl", fs.Lookup("lang").Usage)
+}
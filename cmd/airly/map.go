@@ -0,0 +1,325 @@
+package main
+
+import (
+	"embed"
+	"flag"
+	"fmt"
+	"github.com/probakowski/go-airly"
+	"html/template"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+)
+
+//go:generate curl -fsSL -o leaflet/leaflet.js https://unpkg.com/leaflet@1.9.4/dist/leaflet.js
+//go:generate curl -fsSL -o leaflet/leaflet.css https://unpkg.com/leaflet@1.9.4/dist/leaflet.css
+
+// leafletFS contains vendored Leaflet assets inlined in generated maps, see leaflet/README.md
+//
+//go:embed leaflet
+var leafletFS embed.FS
+
+func init() {
+	commands["map"] = command{"Generate self-contained HTML file with Leaflet map of installations in area", mapCommand}
+}
+
+// bbox is a geographical bounding box, it implements flag.Value in minLat,minLng,maxLat,maxLng format
+type bbox struct {
+	MinLatitude, MinLongitude, MaxLatitude, MaxLongitude float64
+}
+
+func (b *bbox) String() string {
+	return fmt.Sprintf("%s,%s,%s,%s", formatFloat(b.MinLatitude), formatFloat(b.MinLongitude),
+		formatFloat(b.MaxLatitude), formatFloat(b.MaxLongitude))
+}
+
+func (b *bbox) Set(s string) error {
+	parts := strings.Split(s, ",")
+	if len(parts) != 4 {
+		return fmt.Errorf("bounding box must be given as minLat,minLng,maxLat,maxLng")
+	}
+	var coords [4]float64
+	for i, part := range parts {
+		v, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil {
+			return fmt.Errorf("invalid coordinate %q", part)
+		}
+		coords[i] = v
+	}
+	if coords[0] > coords[2] || coords[1] > coords[3] {
+		return fmt.Errorf("minimal coordinates must not be greater than maximal ones")
+	}
+	*b = bbox{coords[0], coords[1], coords[2], coords[3]}
+	return nil
+}
+
+func (b bbox) contains(loc airly.Location) bool {
+	return loc.Latitude >= b.MinLatitude && loc.Latitude <= b.MaxLatitude &&
+		loc.Longitude >= b.MinLongitude && loc.Longitude <= b.MaxLongitude
+}
+
+func (b bbox) center() airly.Location {
+	return airly.Location{Latitude: (b.MinLatitude + b.MaxLatitude) / 2, Longitude: (b.MinLongitude + b.MaxLongitude) / 2}
+}
+
+// radius returns distance in km from center to the furthest corner
+func (b bbox) radius() float64 {
+	corner := airly.Location{Latitude: b.MaxLatitude, Longitude: b.MaxLongitude}
+	if math.Abs(b.MinLatitude) < math.Abs(b.MaxLatitude) {
+		// corners closer to the equator are further away from center
+		corner.Latitude = b.MinLatitude
+	}
+	return b.center().Distance(corner)
+}
+
+// mapMarker is a single installation shown on map
+type mapMarker struct {
+	Latitude  float64  `json:"lat"`
+	Longitude float64  `json:"lng"`
+	Color     string   `json:"color"`
+	Title     string   `json:"title"`
+	Lines     []string `json:"lines"`
+}
+
+func mapCommand(args []string) int {
+	fs := flag.NewFlagSet("map", flag.ContinueOnError)
+	client := clientFlags(fs)
+	var area bbox
+	fs.Var(&area, "bbox", "Area to show as minLat,minLng,maxLat,maxLng")
+	out := fs.String("out", "", "File to write HTML to, standard output is used by default")
+	indexType := fs.String("index-type", "", "Index type used to color markers, e.g. AIRLY_CAQI, CAQI or PIJP")
+	if err := parseFlags(fs, args); err != nil {
+		return exitUsage
+	}
+	if area == (bbox{}) {
+		printError(usageError("Usage: airly map --bbox minLat,minLng,maxLat,maxLng [flags]"))
+		return exitUsage
+	}
+
+	installations, err := client.NearestInstallations(area.center(), airly.MaxDistance(area.radius()), airly.AllResults())
+	if err != nil {
+		printError(err)
+		return exitError
+	}
+	var options []airly.MeasurementsOption
+	if *indexType != "" {
+		options = append(options, airly.WithIndexType(*indexType))
+	}
+	var markers []mapMarker
+	for _, i := range installations {
+		if !area.contains(i.Location) {
+			continue
+		}
+		m, err := client.InstallationMeasurements(i.Id, options...)
+		if err != nil {
+			printError(err)
+			return exitError
+		}
+		markers = append(markers, newMapMarker(i, m))
+	}
+
+	if *out == "" {
+		if err := renderMap(os.Stdout, area, markers); err != nil {
+			printError(err)
+			return exitError
+		}
+		return exitOK
+	}
+	f, err := os.Create(*out)
+	if err != nil {
+		printError(err)
+		return exitError
+	}
+	err = renderMap(f, area, markers)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		printError(err)
+		return exitError
+	}
+	return exitOK
+}
+
+func newMapMarker(i airly.Installation, m airly.Measurements) mapMarker {
+	marker := mapMarker{
+		Latitude:  i.Location.Latitude,
+		Longitude: i.Location.Longitude,
+		Color:     "#999999",
+		Title:     strings.TrimSpace(fmt.Sprintf("%s %s %s", i.Address.City, i.Address.Street, i.Address.Number)),
+	}
+	if len(m.Current.Indexes) > 0 {
+		index := m.Current.Indexes[0]
+		if index.Color != "" {
+			marker.Color = index.Color
+		}
+		marker.Lines = append(marker.Lines, fmt.Sprintf("%s: %s (%s)", index.Name, formatFloat(index.Value), index.Description))
+	}
+	for _, v := range m.Current.Values {
+		marker.Lines = append(marker.Lines, fmt.Sprintf("%s: %s", v.Name, formatValue(v)))
+	}
+	return marker
+}
+
+// mapWidth is width of SVG map, height follows aspect ratio of the area in Web Mercator projection
+const mapWidth = 800.0
+
+// leafletAssets are Leaflet JavaScript and CSS inlined in generated maps
+type leafletAssets struct {
+	JS  template.JS
+	CSS template.CSS
+}
+
+// loadLeaflet returns embedded Leaflet assets, nil is returned if they were not vendored
+var loadLeaflet = func() *leafletAssets {
+	js, jsErr := leafletFS.ReadFile("leaflet/leaflet.js")
+	css, cssErr := leafletFS.ReadFile("leaflet/leaflet.css")
+	if jsErr != nil || cssErr != nil {
+		return nil
+	}
+	return &leafletAssets{template.JS(js), template.CSS(css)}
+}
+
+var mapTemplate = template.Must(template.New("map").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Airly map</title>
+{{with .Leaflet}}<style>{{.CSS}}</style>
+<script>{{.JS}}</script>
+{{end}}<style>
+body { display: flex; margin: 0; font-family: sans-serif; }
+#map { flex: 1; height: 100vh; display: none; }
+svg { flex: 1; max-height: 100vh; background: #f4f4f0; }
+#details { width: 280px; padding: 12px; }
+.marker { cursor: pointer; stroke: #333333; stroke-width: 1; fill-opacity: 0.8; }
+.grid { stroke: #dddddd; stroke-width: 1; }
+.label { fill: #777777; font-size: 12px; }
+</style>
+</head>
+<body>
+<div id="map"></div>
+<svg id="fallback" viewBox="0 0 {{.Width}} {{.Height}}" xmlns="http://www.w3.org/2000/svg">
+{{range .Grid}}<line class="grid" x1="{{.X1}}" y1="{{.Y1}}" x2="{{.X2}}" y2="{{.Y2}}"/>
+{{end}}<text class="label" x="4" y="14">{{.Area.MaxLatitude}}, {{.Area.MinLongitude}}</text>
+<text class="label" x="{{.Width}}" y="{{.Height}}" dy="-4" dx="-4" text-anchor="end">{{.Area.MinLatitude}}, {{.Area.MaxLongitude}}</text>
+{{range $i, $m := .Markers}}<circle class="marker" cx="{{$m.X}}" cy="{{$m.Y}}" r="8" fill="{{$m.Color}}" data-index="{{$i}}"><title>{{$m.Title}}{{range $m.Lines}}
+{{.}}{{end}}</title></circle>
+{{end}}</svg>
+<div id="details">Click a marker to see current values. Data by Airly.</div>
+<script>
+var markers = {{.Markers}};
+var bounds = {{.Bounds}};
+function showDetails(m) {
+	var details = document.getElementById('details');
+	details.textContent = '';
+	var title = document.createElement('b');
+	title.textContent = m.title;
+	details.appendChild(title);
+	(m.lines || []).forEach(function (line) {
+		details.appendChild(document.createElement('br'));
+		details.appendChild(document.createTextNode(line));
+	});
+}
+function showFallback() {
+	document.getElementById('map').style.display = 'none';
+	document.getElementById('fallback').style.display = '';
+}
+document.querySelectorAll('.marker').forEach(function (c) {
+	c.addEventListener('click', function () {
+		showDetails(markers[c.getAttribute('data-index')]);
+	});
+});
+if (window.L && navigator.onLine !== false) {
+	document.getElementById('map').style.display = 'block';
+	document.getElementById('fallback').style.display = 'none';
+	var map = L.map('map');
+	map.fitBounds(bounds);
+	var tiles = L.tileLayer('https://tile.openstreetmap.org/{z}/{x}/{y}.png', {
+		maxZoom: 19,
+		attribution: '&copy; <a href="https://www.openstreetmap.org/copyright">OpenStreetMap</a> contributors'
+	}).addTo(map);
+	var loaded = false;
+	tiles.on('tileload', function () { loaded = true; });
+	tiles.on('tileerror', function () {
+		if (!loaded) {
+			map.remove();
+			showFallback();
+		}
+	});
+	markers.forEach(function (m) {
+		L.circleMarker([m.lat, m.lng], {radius: 8, color: '#333333', weight: 1, fillColor: m.color, fillOpacity: 0.8})
+			.on('click', function () { showDetails(m); })
+			.addTo(map);
+	});
+}
+</script>
+</body>
+</html>
+`))
+
+// svgMarker is mapMarker with position on SVG map
+type svgMarker struct {
+	mapMarker
+	X float64 `json:"-"`
+	Y float64 `json:"-"`
+}
+
+// gridLine is a line of coordinate grid on SVG map
+type gridLine struct {
+	X1, Y1, X2, Y2 float64
+}
+
+// mercator returns Web Mercator y coordinate of latitude, in radians
+func mercator(latitude float64) float64 {
+	return math.Log(math.Tan(math.Pi/4 + latitude*math.Pi/360))
+}
+
+// project returns function returning position of location on SVG map of area with given width and map height
+func project(area bbox) (func(airly.Location) (float64, float64), float64) {
+	lngSpan := (area.MaxLongitude - area.MinLongitude) * math.Pi / 180
+	latSpan := mercator(area.MaxLatitude) - mercator(area.MinLatitude)
+	height := mapWidth
+	if lngSpan > 0 && latSpan > 0 {
+		height = math.Round(math.Min(math.Max(mapWidth*latSpan/lngSpan, mapWidth/4), mapWidth*2))
+	}
+	scale := func(v, span, size float64) float64 {
+		if span <= 0 {
+			return size / 2
+		}
+		return math.Round(v/span*size*10) / 10
+	}
+	return func(loc airly.Location) (float64, float64) {
+		x := scale((loc.Longitude-area.MinLongitude)*math.Pi/180, lngSpan, mapWidth)
+		y := scale(mercator(area.MaxLatitude)-mercator(loc.Latitude), latSpan, height)
+		return x, y
+	}, height
+}
+
+// renderMap writes self-contained HTML page with map of area with given markers. Leaflet is inlined if it's vendored,
+// only OpenStreetMap tiles are loaded from the Internet. SVG map without basemap is shown if tiles can't be loaded
+// or Leaflet is not vendored, so the page works offline too
+func renderMap(w io.Writer, area bbox, markers []mapMarker) error {
+	position, height := project(area)
+	svgMarkers := make([]svgMarker, len(markers))
+	for i, m := range markers {
+		x, y := position(airly.Location{Latitude: m.Latitude, Longitude: m.Longitude})
+		svgMarkers[i] = svgMarker{m, x, y}
+	}
+	var grid []gridLine
+	for i := 1; i < 4; i++ {
+		grid = append(grid, gridLine{mapWidth * float64(i) / 4, 0, mapWidth * float64(i) / 4, height},
+			gridLine{0, height * float64(i) / 4, mapWidth, height * float64(i) / 4})
+	}
+	bounds := [][2]float64{{area.MinLatitude, area.MinLongitude}, {area.MaxLatitude, area.MaxLongitude}}
+	return mapTemplate.Execute(w, struct {
+		Leaflet       *leafletAssets
+		Area          bbox
+		Bounds        [][2]float64
+		Width, Height float64
+		Grid          []gridLine
+		Markers       []svgMarker
+	}{loadLeaflet(), area, bounds, mapWidth, height, grid, svgMarkers})
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"bytes"
+	"github.com/probakowski/go-airly"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestBbox(t *testing.T) {
+	var b bbox
+	assert.Nil(t, b.Set("50.0,19.8, 50.1,20.0"))
+	assert.Equal(t, bbox{50, 19.8, 50.1, 20}, b)
+	assert.Equal(t, "50,19.8,50.1,20", b.String())
+	assert.True(t, b.contains(airly.Location{Latitude: 50.062006, Longitude: 19.940984}))
+	assert.False(t, b.contains(airly.Location{Latitude: 50.2, Longitude: 19.940984}))
+	assert.Equal(t, airly.Location{Latitude: 50.05, Longitude: 19.9}, b.center())
+	assert.InDelta(t, 9.05, b.radius(), 0.01)
+
+	assert.Error(t, b.Set("50,19.8,50.1"))
+	assert.Error(t, b.Set("50,x,50.1,20"))
+	assert.Error(t, b.Set("50.1,19.8,50,20"))
+}
+
+func TestNewMapMarker(t *testing.T) {
+	marker := newMapMarker(airly.Installation{
+		Location: airly.Location{Latitude: 50.062006, Longitude: 19.940984},
+		Address:  airly.Address{City: "Kraków", Street: "Mikołajska", Number: "4B"},
+	}, airly.Measurements{Current: airly.Measurement{
+		Values:  []airly.Value{{Name: "PM25", Value: 18.7}},
+		Indexes: []airly.Index{{Name: "AIRLY_CAQI", Value: 35.53, Description: "Air is quite good.", Color: "#D1CF1E"}},
+	}})
+	assert.Equal(t, mapMarker{
+		Latitude:  50.062006,
+		Longitude: 19.940984,
+		Color:     "#D1CF1E",
+		Title:     "Kraków Mikołajska 4B",
+		Lines:     []string{"AIRLY_CAQI: 35.53 (Air is quite good.)", "PM25: 18.7"},
+	}, marker)
+
+	assert.Equal(t, "#999999", newMapMarker(airly.Installation{}, airly.Measurements{}).Color)
+}
+
+func TestRenderMap(t *testing.T) {
+	var buf bytes.Buffer
+	err := renderMap(&buf, bbox{50, 19.8, 50.1, 20}, []mapMarker{{
+		Latitude: 50.062006, Longitude: 19.940984, Color: "#D1CF1E", Title: "<script>alert(1)</script>",
+		Lines: []string{"PM25: 18.7"},
+	}})
+	assert.Nil(t, err)
+	html := buf.String()
+	assert.Contains(t, html, `viewBox="0 0 800 623"`)
+	assert.Contains(t, html, `<circle class="marker" cx="563.9" cy="236.9" r="8" fill="#D1CF1E" data-index="0">`)
+	assert.Contains(t, html, "PM25: 18.7")
+	assert.Contains(t, html, `"lat":50.062006`)
+	assert.NotContains(t, html, "<script>alert(1)</script>")
+	assert.NotContains(t, html, `src="http`, "map should not load external scripts")
+	assert.NotContains(t, html, "<link", "map should not load external styles")
+
+	buf.Reset()
+	assert.Nil(t, renderMap(&buf, bbox{50, 19.8, 50.1, 20}, nil))
+	assert.Contains(t, buf.String(), "var markers = [];")
+
+	buf.Reset()
+	assert.Nil(t, renderMap(&buf, bbox{50, 19.8, 50, 19.8}, []mapMarker{{Latitude: 50, Longitude: 19.8}}))
+	assert.Contains(t, buf.String(), `cx="400" cy="400"`)
+}
+
+func TestRenderMapLeaflet(t *testing.T) {
+	defer func(load func() *leafletAssets) { loadLeaflet = load }(loadLeaflet)
+	assert.Nil(t, loadLeaflet(), "Leaflet assets are not vendored")
+
+	loadLeaflet = func() *leafletAssets {
+		return &leafletAssets{JS: "window.L = {version: '1.9.4'};", CSS: ".leaflet-container { overflow: hidden; }"}
+	}
+	var buf bytes.Buffer
+	assert.Nil(t, renderMap(&buf, bbox{50, 19.8, 50.1, 20}, nil))
+	html := buf.String()
+	assert.Contains(t, html, "<script>window.L = {version: '1.9.4'};</script>")
+	assert.Contains(t, html, "<style>.leaflet-container { overflow: hidden; }</style>")
+	assert.Contains(t, html, "var bounds = [[50,19.8],[50.1,20]];")
+	assert.Contains(t, html, `<svg id="fallback"`)
+}
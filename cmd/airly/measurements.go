@@ -0,0 +1,193 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/probakowski/go-airly"
+	"github.com/probakowski/go-airly/report"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+func init() {
+	commands["measurements"] = command{"Get measurements: installation <id>..., nearest, point, here", measurements}
+}
+
+func measurements(args []string) int {
+	if len(args) == 0 {
+		printError(usageError("Usage: airly measurements installation|nearest|point|here [flags]"))
+		return exitUsage
+	}
+	fs := flag.NewFlagSet("measurements "+args[0], flag.ContinueOnError)
+	client := clientFlags(fs)
+	out := outputFlags(fs)
+	indexType := fs.String("index-type", "", "Index type, e.g. AIRLY_CAQI, CAQI or PIJP, see airly meta indexes. "+
+		"Comma separated types are fetched separately and shown side by side")
+	indexPollutant := fs.String("index-pollutant", "", "Pollutant indexes are calculated for, e.g. PM25, all by default")
+	history := fs.Bool("history", false, "Include history in the output")
+	forecast := fs.Bool("forecast", false, "Include forecast in the output")
+	templateFile := fs.String("template", "", "Go text/template file used to format measurements instead of --output, "+
+		"see github.com/probakowski/go-airly/report.Funcs for available functions")
+	var failIf conditions
+	failIf.flags(fs)
+	var positional []string
+	var run func(indexType airly.IndexTypeOption) (airly.Measurements, error)
+	switch args[0] {
+	case "installation":
+		run = func(indexType airly.IndexTypeOption) (airly.Measurements, error) {
+			id, err := installationID(positional[0])
+			if err != nil {
+				return airly.Measurements{}, err
+			}
+			return client.InstallationMeasurements(id, indexType, airly.WithIndexPollutant(*indexPollutant))
+		}
+	case "nearest":
+		loc := locationFlags(fs, "")
+		maxDistance := fs.Float64("max-distance", 3, "Maximum distance to installation in km")
+		run = func(indexType airly.IndexTypeOption) (airly.Measurements, error) {
+			return client.NearestMeasurements(*loc,
+				airly.MaxDistance(*maxDistance), indexType, airly.WithIndexPollutant(*indexPollutant))
+		}
+	case "point":
+		loc := locationFlags(fs, "")
+		run = func(indexType airly.IndexTypeOption) (airly.Measurements, error) {
+			return client.PointMeasurements(*loc, indexType, airly.WithIndexPollutant(*indexPollutant))
+		}
+	case "here":
+		loc := locationFlags(fs, ", approximate location of IP address is used if not set")
+		maxDistance := fs.Float64("max-distance", 10, "Maximum distance to installation in km")
+		geolocationURL := fs.String("geolocation-url", os.Getenv("AIRLY_GEOLOCATION_URL"), "IP geolocation service "+
+			"returning JSON, e.g. https://ipapi.co/json/, AIRLY_GEOLOCATION_URL environment variable is used by default. "+
+			"Location is looked up only if this is set")
+		var once sync.Once
+		var locErr error
+		run = func(indexType airly.IndexTypeOption) (airly.Measurements, error) {
+			// run is called concurrently for multiple index types, location is looked up once
+			once.Do(func() {
+				if *loc != (airly.Location{}) {
+					return
+				}
+				if *geolocationURL == "" {
+					locErr = fmt.Errorf("location unknown, set --lat and --lng or enable IP " +
+						"geolocation with --geolocation-url or AIRLY_GEOLOCATION_URL")
+					return
+				}
+				var l airly.Location
+				if l, locErr = geolocate(geolocationClient, *geolocationURL); locErr == nil {
+					fmt.Fprintf(os.Stderr, "approximate location: %s,%s\n", formatFloat(l.Latitude), formatFloat(l.Longitude))
+					*loc = l
+				}
+			})
+			if locErr != nil {
+				return airly.Measurements{}, locErr
+			}
+			return client.NearestMeasurements(*loc, airly.MaxDistance(*maxDistance), indexType,
+				airly.WithIndexPollutant(*indexPollutant))
+		}
+	default:
+		printError(usageError(fmt.Sprintf("unknown measurements mode %q", args[0])))
+		return exitUsage
+	}
+	var err error
+	if positional, err = parse(fs, args[1:]); err != nil {
+		return exitUsage
+	}
+	if args[0] == "installation" && len(positional) == 0 {
+		printError(usageError("Usage: airly measurements installation <id|alias>... [flags]"))
+		return exitUsage
+	}
+	if args[0] == "installation" && len(positional) > 1 {
+		if strings.Contains(*indexType, ",") {
+			printError(usageError("multiple index types can't be used with multiple installations"))
+			return exitUsage
+		}
+		installations := make([]int, len(positional))
+		for i, p := range positional {
+			if installations[i], err = installationID(p); err != nil {
+				printError(usageError(err.Error()))
+				return exitUsage
+			}
+		}
+		options := []airly.MeasurementsOption{airly.WithIndexType(*indexType), airly.WithIndexPollutant(*indexPollutant)}
+		return compareInstallations(*client, installations, options, out, *history, *forecast, *templateFile, failIf)
+	}
+
+	var m airly.Measurements
+	if strings.Contains(*indexType, ",") {
+		m, err = airly.MeasurementsWithIndexes(run, strings.Split(*indexType, ",")...)
+	} else {
+		m, err = run(airly.WithIndexType(*indexType))
+	}
+	if err != nil {
+		printError(err)
+		return exitError
+	}
+	if !*history {
+		m.History = nil
+	}
+	if !*forecast {
+		m.Forecast = nil
+	}
+	if *templateFile != "" {
+		err = executeTemplate(os.Stdout, *templateFile, m)
+	} else {
+		err = out.print(m, func() [][]string { return measurementsTable(m) })
+	}
+	if err != nil {
+		printError(err)
+		return exitError
+	}
+
+	failed, err := failIf.failed(m.Current)
+	if err != nil {
+		printError(err)
+		return exitError
+	}
+	for _, c := range failed {
+		fmt.Fprintf(os.Stderr, "condition met: %s\n", c)
+	}
+	if len(failed) > 0 {
+		return exitFailed
+	}
+	return exitOK
+}
+
+func measurementsTable(m airly.Measurements) [][]string {
+	rows := [][]string{{"PERIOD", "FROM", "TILL", "NAME", "VALUE"}}
+	add := func(period string, measurement airly.Measurement) {
+		from := measurement.FromDateTime.Local().Format(time.RFC3339)
+		till := measurement.TillDateTime.Local().Format(time.RFC3339)
+		for _, v := range measurement.Values {
+			rows = append(rows, []string{period, from, till, v.Name, formatValue(v)})
+		}
+		for _, i := range measurement.Indexes {
+			rows = append(rows, []string{period, from, till, i.Name, formatFloat(i.Value) + " " + i.Level})
+		}
+	}
+	for _, h := range m.History {
+		add("history", h)
+	}
+	add("current", m.Current)
+	for _, f := range m.Forecast {
+		add("forecast", f)
+	}
+	return rows
+}
+
+// executeTemplate formats measurements with template read from file
+func executeTemplate(w io.Writer, file string, m airly.Measurements) error {
+	text, err := ioutil.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	t, err := report.NewTemplate(filepath.Base(file)).Parse(string(text))
+	if err != nil {
+		return err
+	}
+	return t.Execute(w, m)
+}
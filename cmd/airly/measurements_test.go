@@ -0,0 +1,56 @@
+package main
+
+import (
+	"bytes"
+	"github.com/probakowski/go-airly"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestMeasurementsTable(t *testing.T) {
+	from := time.Date(2018, 8, 24, 8, 0, 0, 0, time.UTC)
+	till := from.Add(time.Hour)
+	m := airly.Measurements{
+		Current: airly.Measurement{
+			FromDateTime: from,
+			TillDateTime: till,
+			Values:       []airly.Value{{Name: "PM25", Value: 18.7}},
+			Indexes:      []airly.Index{{Name: "AIRLY_CAQI", Value: 35.53, Level: "LOW"}},
+		},
+		Forecast: []airly.Measurement{{
+			FromDateTime: till,
+			TillDateTime: till.Add(time.Hour),
+			Values:       []airly.Value{{Name: "PM25", Value: 20}},
+		}},
+	}
+	f := func(t time.Time) string {
+		return t.Local().Format(time.RFC3339)
+	}
+	assert.Equal(t, [][]string{
+		{"PERIOD", "FROM", "TILL", "NAME", "VALUE"},
+		{"current", f(from), f(till), "PM25", "18.7"},
+		{"current", f(from), f(till), "AIRLY_CAQI", "35.53 LOW"},
+		{"forecast", f(till), f(till.Add(time.Hour)), "PM25", "20"},
+	}, measurementsTable(m))
+}
+
+func TestExecuteTemplate(t *testing.T) {
+	dir, err := ioutil.TempDir("", "airly")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "status.tmpl")
+	assert.Nil(t, ioutil.WriteFile(file, []byte(`PM2.5 {{value .Current "pm25"}}`), 0644))
+
+	var buf bytes.Buffer
+	m := airly.Measurements{Current: airly.Measurement{Values: []airly.Value{{Name: "PM25", Value: 18.7}}}}
+	assert.Nil(t, executeTemplate(&buf, file, m))
+	assert.Equal(t, "PM2.5 18.7", buf.String())
+
+	assert.Nil(t, ioutil.WriteFile(file, []byte(`{{value`), 0644))
+	assert.NotNil(t, executeTemplate(&buf, file, m))
+	assert.NotNil(t, executeTemplate(&buf, filepath.Join(dir, "missing.tmpl"), m))
+}
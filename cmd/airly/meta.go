@@ -0,0 +1,59 @@
+package main
+
+import (
+	"flag"
+	"github.com/probakowski/go-airly"
+)
+
+func init() {
+	commands["meta"] = command{"Show metadata: indexes, measurements", meta}
+}
+
+func meta(args []string) int {
+	if len(args) == 0 || (args[0] != "indexes" && args[0] != "measurements") {
+		printError(usageError("Usage: airly meta indexes|measurements [flags]"))
+		return exitUsage
+	}
+	fs := flag.NewFlagSet("meta "+args[0], flag.ContinueOnError)
+	client := clientFlags(fs)
+	out := outputFlags(fs)
+	if err := parseFlags(fs, args[1:]); err != nil {
+		return exitUsage
+	}
+
+	var err error
+	if args[0] == "indexes" {
+		var indexTypes []airly.IndexType
+		if indexTypes, err = client.IndexTypes(); err == nil {
+			err = out.print(indexTypes, func() [][]string { return indexTypesTable(indexTypes) })
+		}
+	} else {
+		var measurementTypes []airly.MeasurementType
+		if measurementTypes, err = client.MeasurementTypes(); err == nil {
+			err = out.print(measurementTypes, func() [][]string { return measurementTypesTable(measurementTypes) })
+		}
+	}
+	if err != nil {
+		printError(err)
+		return exitError
+	}
+	return exitOK
+}
+
+func indexTypesTable(indexTypes []airly.IndexType) [][]string {
+	rows := [][]string{{"INDEX", "LEVEL", "VALUES", "DESCRIPTION", "COLOR"}}
+	for _, i := range indexTypes {
+		for _, l := range i.Levels {
+			rows = append(rows, []string{i.Name, l.Level, l.Values, l.Description, l.Color})
+		}
+	}
+	return rows
+}
+
+func measurementTypesTable(measurementTypes []airly.MeasurementType) [][]string {
+	rows := [][]string{{"NAME", "LABEL", "UNIT"}}
+	for _, m := range measurementTypes {
+		rows = append(rows, []string{m.Name, m.Label, m.Unit})
+	}
+	return rows
+}
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"github.com/probakowski/go-airly"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestIndexTypesTable(t *testing.T) {
+	assert.Equal(t, [][]string{
+		{"INDEX", "LEVEL", "VALUES", "DESCRIPTION", "COLOR"},
+		{"AIRLY_CAQI", "VERY_LOW", "0-25", "Very Low", "#6BC926"},
+		{"AIRLY_CAQI", "LOW", "25-50", "Low", "#D1CF1E"},
+	}, indexTypesTable([]airly.IndexType{{
+		Name: "AIRLY_CAQI",
+		Levels: []airly.Level{
+			{Values: "0-25", Level: "VERY_LOW", Description: "Very Low", Color: "#6BC926"},
+			{Values: "25-50", Level: "LOW", Description: "Low", Color: "#D1CF1E"},
+		},
+	}}))
+}
+
+func TestMeasurementTypesTable(t *testing.T) {
+	assert.Equal(t, [][]string{
+		{"NAME", "LABEL", "UNIT"},
+		{"PM25", "PM2.5", "µg/m³"},
+	}, measurementTypesTable([]airly.MeasurementType{{Name: "PM25", Label: "PM2.5", Unit: "µg/m³"}}))
+}
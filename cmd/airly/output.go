@@ -0,0 +1,52 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/probakowski/go-airly/report"
+	"os"
+)
+
+// outputOptions are flags shared by all commands printing API results
+type outputOptions struct {
+	format  string
+	query   string
+	noColor bool
+}
+
+func outputFlags(fs *flag.FlagSet) *outputOptions {
+	o := &outputOptions{}
+	fs.StringVar(&o.format, "output", "json", "Output format, json, table or markdown")
+	fs.StringVar(&o.query, "query", "", "Print only part of the output selected by path, e.g. current.indexes[0].value")
+	fs.BoolVar(&o.noColor, "no-color", false, "Don't color index levels in table output, NO_COLOR environment "+
+		"variable has the same effect")
+	return o
+}
+
+// print writes v to standard output, table is used to get rows (first one being header) for table format
+func (o outputOptions) print(v interface{}, table func() [][]string) error {
+	if o.query != "" || o.format == "json" {
+		return output(v, o.query)
+	}
+	if o.format == "markdown" {
+		return report.MarkdownTable(os.Stdout, table())
+	}
+	if o.format != "table" {
+		return fmt.Errorf("unknown output format %q", o.format)
+	}
+	return writeTable(os.Stdout, table(), colorEnabled(o.noColor))
+}
+
+// output prints v as indented JSON, if path is not empty only the selected part of v is printed, see query
+func output(v interface{}, path string) error {
+	selected, err := query(v, path)
+	if err != nil {
+		return err
+	}
+	s, err := formatQueryResult(selected)
+	if err != nil {
+		return err
+	}
+	fmt.Println(s)
+	return nil
+}
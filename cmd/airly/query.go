@@ -0,0 +1,85 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// query selects part of v using simple path expression over its JSON form,
+// e.g. current.indexes[0].value. Empty path selects whole value
+func query(v interface{}, path string) (interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var current interface{}
+	if err := dec.Decode(&current); err != nil {
+		return nil, err
+	}
+
+	path = strings.TrimPrefix(path, ".")
+	if path == "" {
+		return current, nil
+	}
+	for _, segment := range strings.Split(path, ".") {
+		name := segment
+		var indexes []string
+		if i := strings.Index(segment, "["); i >= 0 {
+			name = segment[:i]
+			for _, index := range strings.Split(segment[i+1:], "[") {
+				if !strings.HasSuffix(index, "]") {
+					return nil, fmt.Errorf("invalid query segment %q", segment)
+				}
+				indexes = append(indexes, strings.TrimSuffix(index, "]"))
+			}
+		}
+		if name != "" {
+			m, ok := current.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("cannot select %q from non-object", name)
+			}
+			if current, ok = m[name]; !ok {
+				return nil, fmt.Errorf("no field %q", name)
+			}
+		}
+		for _, index := range indexes {
+			i, err := strconv.Atoi(index)
+			if err != nil {
+				return nil, fmt.Errorf("invalid index %q", index)
+			}
+			a, ok := current.([]interface{})
+			if !ok {
+				return nil, fmt.Errorf("cannot index non-array with %d", i)
+			}
+			if i < 0 {
+				i += len(a)
+			}
+			if i < 0 || i >= len(a) {
+				return nil, fmt.Errorf("index %d out of range", i)
+			}
+			current = a[i]
+		}
+	}
+	return current, nil
+}
+
+// formatQueryResult prints scalars as plain text and objects and arrays as indented JSON
+func formatQueryResult(v interface{}) (string, error) {
+	switch v := v.(type) {
+	case string:
+		return v, nil
+	case json.Number:
+		return v.String(), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case nil:
+		return "null", nil
+	}
+	data, err := json.MarshalIndent(v, "", "  ")
+	return string(data), err
+}
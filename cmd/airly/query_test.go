@@ -0,0 +1,48 @@
+package main
+
+import (
+	"encoding/json"
+	"github.com/probakowski/go-airly"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestQuery(t *testing.T) {
+	m := airly.Measurements{
+		Current: airly.Measurement{
+			Values:  []airly.Value{{Name: "PM1", Value: 12.73}, {Name: "PM25", Value: 18.7}},
+			Indexes: []airly.Index{{Name: "AIRLY_CAQI", Value: 35.53, Level: "LOW"}},
+		},
+	}
+	for _, tc := range []struct {
+		path     string
+		expected string
+	}{
+		{"current.indexes[0].value", "35.53"},
+		{".current.indexes[0].level", "LOW"},
+		{"current.values[-1].name", "PM25"},
+		{"history", "null"},
+		{"current.values[1]", "{\n  \"name\": \"PM25\",\n  \"value\": 18.7\n}"},
+	} {
+		v, err := query(m, tc.path)
+		assert.Nil(t, err, tc.path)
+		s, err := formatQueryResult(v)
+		assert.Nil(t, err, tc.path)
+		assert.Equal(t, tc.expected, s, tc.path)
+	}
+
+	for _, path := range []string{"current.unknown", "current.values[2]", "current.values[x]", "current[0]",
+		"current.values.name", "current.values[0"} {
+		_, err := query(m, path)
+		assert.NotNil(t, err, path)
+	}
+}
+
+func TestQueryWhole(t *testing.T) {
+	v, err := query(airly.Location{Latitude: 50.06, Longitude: 19.94}, "")
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"latitude":  json.Number("50.06"),
+		"longitude": json.Number("19.94"),
+	}, v)
+}
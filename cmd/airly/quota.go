@@ -0,0 +1,39 @@
+package main
+
+import (
+	"flag"
+	"github.com/probakowski/go-airly"
+	"strconv"
+)
+
+func init() {
+	commands["quota"] = command{"Show used and remaining requests", quota}
+}
+
+func quota(args []string) int {
+	fs := flag.NewFlagSet("quota", flag.ContinueOnError)
+	client := clientFlags(fs)
+	out := outputFlags(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return exitUsage
+	}
+
+	rateLimit, err := client.RateLimit()
+	if err != nil && rateLimit.DayLimit == 0 {
+		printError(err)
+		return exitError
+	}
+	if err := out.print(rateLimit, func() [][]string { return quotaTable(rateLimit) }); err != nil {
+		printError(err)
+		return exitError
+	}
+	return exitOK
+}
+
+func quotaTable(r airly.RateLimit) [][]string {
+	return [][]string{
+		{"PERIOD", "LIMIT", "USED", "REMAINING"},
+		{"day", strconv.Itoa(r.DayLimit), strconv.Itoa(r.DayLimit - r.DayRemaining), strconv.Itoa(r.DayRemaining)},
+		{"minute", strconv.Itoa(r.MinuteLimit), strconv.Itoa(r.MinuteLimit - r.MinuteRemaining), strconv.Itoa(r.MinuteRemaining)},
+	}
+}
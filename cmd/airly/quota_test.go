@@ -0,0 +1,15 @@
+package main
+
+import (
+	"github.com/probakowski/go-airly"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestQuotaTable(t *testing.T) {
+	assert.Equal(t, [][]string{
+		{"PERIOD", "LIMIT", "USED", "REMAINING"},
+		{"day", "100", "58", "42"},
+		{"minute", "50", "1", "49"},
+	}, quotaTable(airly.RateLimit{DayLimit: 100, DayRemaining: 42, MinuteLimit: 50, MinuteRemaining: 49}))
+}
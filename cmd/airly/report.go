@@ -0,0 +1,77 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/probakowski/go-airly"
+	"github.com/probakowski/go-airly/report"
+	"io"
+	"os"
+)
+
+func init() {
+	commands["report"] = command{"Generate Markdown or HTML report of current measurements and forecast", reportCommand}
+}
+
+func reportCommand(args []string) int {
+	fs := flag.NewFlagSet("report", flag.ContinueOnError)
+	client := clientFlags(fs)
+	target := targetFlags(fs)
+	format := fs.String("format", "markdown", "Report format, markdown or html")
+	out := fs.String("out", "", "File to write report to, standard output is used by default")
+	if err := parseFlags(fs, args); err != nil {
+		return exitUsage
+	}
+	if *format != "markdown" && *format != "html" {
+		printError(usageError(fmt.Sprintf("unknown report format %q", *format)))
+		return exitUsage
+	}
+
+	r, err := target.report(*client)
+	if err != nil {
+		printError(err)
+		return exitError
+	}
+	w := io.Writer(os.Stdout)
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			printError(err)
+			return exitError
+		}
+		defer f.Close()
+		w = f
+	}
+	if err := writeReport(w, r, *format); err != nil {
+		printError(err)
+		return exitError
+	}
+	return exitOK
+}
+
+// report returns report with measurements and metadata of target installation
+func (t target) report(c airly.Client) (report.Report, error) {
+	var r report.Report
+	var err error
+	if t.installation == -1 {
+		var installations []airly.Installation
+		installations, err = c.NearestInstallations(*t.location)
+		if len(installations) > 0 {
+			r.Installation = installations[0]
+		}
+	} else {
+		r.Installation, err = c.Installation(t.installation)
+	}
+	if err != nil {
+		return r, err
+	}
+	r.Measurements, err = t.measurements(c)
+	return r, err
+}
+
+func writeReport(w io.Writer, r report.Report, format string) error {
+	if format == "html" {
+		return r.HTML(w)
+	}
+	return r.Markdown(w)
+}
@@ -0,0 +1,22 @@
+package main
+
+import (
+	"bytes"
+	"github.com/probakowski/go-airly"
+	"github.com/probakowski/go-airly/report"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestWriteReport(t *testing.T) {
+	r := report.Report{Installation: airly.Installation{Id: 204}}
+	var buf bytes.Buffer
+	assert.Nil(t, writeReport(&buf, r, "markdown"))
+	assert.Equal(t, "## Installation 204\n\n", buf.String())
+
+	buf.Reset()
+	assert.Nil(t, writeReport(&buf, r, "html"))
+	assert.True(t, strings.HasPrefix(buf.String(), "<!DOCTYPE html>"))
+	assert.Contains(t, buf.String(), "<h1>Installation 204</h1>")
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/probakowski/go-airly"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+func init() {
+	commands["top"] = command{"Rank installations in area by current value, worst first", top}
+}
+
+// topEntry is a single installation ranked by top command
+type topEntry struct {
+	Rank         int                `json:"rank"`
+	Installation airly.Installation `json:"installation"`
+	// Distance from area center in km
+	Distance float64 `json:"distance"`
+	Value    float64 `json:"value"`
+	// Level of the first index of current measurement
+	Level string `json:"level,omitempty"`
+}
+
+func top(args []string) int {
+	fs := flag.NewFlagSet("top", flag.ContinueOnError)
+	client := clientFlags(fs)
+	out := outputFlags(fs)
+	loc := locationFlags(fs, " of the area center")
+	radius := fs.Float64("radius", 10, "Area radius in km")
+	by := fs.String("by", "pm25", "Value or index to rank by, e.g. pm25, pm10, no2 or caqi")
+	best := fs.Bool("best", false, "Show the best locations first")
+	limit := fs.Int("limit", 10, "Number of installations to show, -1 means all")
+	maxRequests := fs.Int("max-requests", 50, "Maximum number of installations to fetch measurements of, the nearest "+
+		"ones are used, each of them costs one request of API quota, -1 means no limit")
+	if err := parseFlags(fs, args); err != nil {
+		return exitUsage
+	}
+	if *maxRequests < 1 && *maxRequests != -1 {
+		printError(usageError("--max-requests must be positive or -1 for no limit"))
+		return exitUsage
+	}
+	if *loc == (airly.Location{}) {
+		printError(usageError("Usage: airly top --lat <lat> --lng <lng> [--radius km] [--by pm25] [flags]"))
+		return exitUsage
+	}
+
+	installations, err := client.NearestInstallations(*loc, airly.MaxDistance(*radius), airly.AllResults())
+	if err != nil {
+		printError(err)
+		return exitError
+	}
+	if *maxRequests != -1 && len(installations) > *maxRequests {
+		fmt.Fprintf(os.Stderr, "%d installations in area, measurements of %d nearest are fetched, see --max-requests\n",
+			len(installations), *maxRequests)
+		sort.SliceStable(installations, func(i, j int) bool {
+			return loc.Distance(installations[i].Location) < loc.Distance(installations[j].Location)
+		})
+		installations = installations[:*maxRequests]
+	}
+	ids := make([]int, len(installations))
+	for i, installation := range installations {
+		ids[i] = installation.Id
+	}
+
+	code := exitOK
+	results := fetchAll(*client, ids)
+	for _, r := range results {
+		if r.err != nil {
+			printInstallationError(r.Installation, r.err)
+			code = exitError
+		}
+	}
+	entries := rank(*loc, installations, results, *by, *best)
+	if *limit >= 0 && len(entries) > *limit {
+		entries = entries[:*limit]
+	}
+	if err := out.print(entries, func() [][]string { return topTable(entries, *by) }); err != nil {
+		printError(err)
+		return exitError
+	}
+	return code
+}
+
+// rank returns installations with value by fetched successfully, sorted by it in descending order (ascending if
+// best is set). results must be in the same order as installations
+func rank(center airly.Location, installations []airly.Installation, results []installationMeasurements, by string,
+	best bool) []topEntry {
+	entries := []topEntry{}
+	for i, r := range results {
+		if r.err != nil {
+			continue
+		}
+		v, ok := value(r.Measurements.Current, by)
+		if !ok {
+			continue
+		}
+		entry := topEntry{Installation: installations[i], Distance: center.Distance(installations[i].Location), Value: v}
+		if indexes := r.Measurements.Current.Indexes; len(indexes) > 0 {
+			entry.Level = indexes[0].Level
+		}
+		entries = append(entries, entry)
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		if best {
+			return entries[i].Value < entries[j].Value
+		}
+		return entries[i].Value > entries[j].Value
+	})
+	for i := range entries {
+		entries[i].Rank = i + 1
+	}
+	return entries
+}
+
+func topTable(entries []topEntry, by string) [][]string {
+	rows := [][]string{{"RANK", "INSTALLATION", "ADDRESS", "DISTANCE", strings.ToUpper(by), "LEVEL"}}
+	for _, e := range entries {
+		address := strings.TrimSpace(fmt.Sprintf("%s %s %s", e.Installation.Address.City, e.Installation.Address.Street,
+			e.Installation.Address.Number))
+		rows = append(rows, []string{strconv.Itoa(e.Rank), strconv.Itoa(e.Installation.Id), address,
+			strconv.FormatFloat(e.Distance, 'f', 1, 64) + " km", formatFloat(e.Value), e.Level})
+	}
+	return rows
+}
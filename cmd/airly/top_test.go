@@ -0,0 +1,77 @@
+package main
+
+import (
+	"errors"
+	"github.com/probakowski/go-airly"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRank(t *testing.T) {
+	center := airly.Location{Latitude: 50.062006, Longitude: 19.940984}
+	installations := []airly.Installation{
+		{Id: 204, Location: center, Address: airly.Address{City: "Kraków", Street: "Mikołajska", Number: "4B"}},
+		{Id: 8077, Location: airly.Location{Latitude: 50.08, Longitude: 19.940984}},
+		{Id: 911},
+		{Id: 1},
+	}
+	current := func(pm25 float64, level string) airly.Measurements {
+		return airly.Measurements{Current: airly.Measurement{
+			Values:  []airly.Value{{Name: "PM25", Value: pm25}},
+			Indexes: []airly.Index{{Name: "AIRLY_CAQI", Level: level}},
+		}}
+	}
+	results := []installationMeasurements{
+		{Installation: 204, Measurements: current(18.7, "LOW")},
+		{Installation: 8077, Measurements: current(42, "MEDIUM")},
+		{Installation: 911, err: errors.New("not found")},
+		{Installation: 1, Measurements: airly.Measurements{}},
+	}
+
+	entries := rank(center, installations, results, "pm25", false)
+	assert.Len(t, entries, 2)
+	assert.Equal(t, topEntry{Rank: 1, Installation: installations[1], Distance: entries[0].Distance, Value: 42,
+		Level: "MEDIUM"}, entries[0])
+	assert.InDelta(t, 2, entries[0].Distance, 0.01)
+	assert.Equal(t, topEntry{Rank: 2, Installation: installations[0], Value: 18.7, Level: "LOW"}, entries[1])
+
+	entries = rank(center, installations, results, "PM25", true)
+	assert.Equal(t, 204, entries[0].Installation.Id)
+	assert.Equal(t, 8077, entries[1].Installation.Id)
+	assert.Empty(t, rank(center, installations, results, "no2", false))
+
+	assert.Equal(t, [][]string{
+		{"RANK", "INSTALLATION", "ADDRESS", "DISTANCE", "PM25", "LEVEL"},
+		{"1", "204", "Kraków Mikołajska 4B", "0.0 km", "18.7", "LOW"},
+		{"2", "8077", "", "2.0 km", "42", "MEDIUM"},
+	}, topTable(entries, "pm25"))
+}
+
+func TestTopUsage(t *testing.T) {
+	assert.Equal(t, exitUsage, top([]string{"--radius", "5"}))
+}
+
+func TestTopMaxRequests(t *testing.T) {
+	dir, err := ioutil.TempDir("", "airly")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "recorded.jsonl")
+	log := `{"endpoint":"installations/nearest","params":{"lat":"50.062","lng":"19.941","maxDistanceKM":"10","maxResults":"-1"},"status":200,"body":` +
+		`"[{\"id\":1,\"location\":{\"latitude\":50.062,\"longitude\":19.941}},{\"id\":2,\"location\":{\"latitude\":50.07,\"longitude\":19.941}},{\"id\":3,\"location\":{\"latitude\":50.08,\"longitude\":19.941}}]"}` + "\n"
+	for _, id := range []string{"1", "2"} {
+		log += `{"endpoint":"measurements/installation","params":{"installationId":"` + id + `"},"status":200,"body":` +
+			`"{\"current\":{\"values\":[{\"name\":\"PM25\",\"value\":` + id + `}]}}"}` + "\n"
+	}
+	assert.NoError(t, ioutil.WriteFile(path, []byte(log), 0600))
+	args := []string{"--replay", path, "--lat", "50.062", "--lng", "19.941", "--output", "table", "--max-requests"}
+
+	// measurements of installation 3 are not recorded, so it must not be fetched
+	assert.Equal(t, exitOK, top(append(args, "2")))
+	assert.Equal(t, exitError, top(append(args, "-1")))
+	for _, maxRequests := range []string{"0", "-2"} {
+		assert.Equal(t, exitUsage, top(append(args, maxRequests)))
+	}
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"github.com/probakowski/go-airly"
+	"net/http"
+	"os"
+	"sync"
+)
+
+// transport is HttpClient of client configured with --audit-log, --record and --replay. Clients are composed when
+// the first request is sent, so flags can be combined and given in any order, and audit log from AIRLY_AUDIT_LOG
+// is opened only if --audit-log is not given
+type transport struct {
+	client      *airly.Client
+	envAuditLog string
+	auditLog    *os.File
+	record      *os.File
+	replay      *airly.ReplayClient
+
+	once       sync.Once
+	httpClient airly.HttpClient
+	err        error
+}
+
+// errRecordReplay is returned when both --record and --replay are given
+var errRecordReplay = errors.New("--record can't be used with --replay")
+
+// Do sends request with composed clients
+func (t *transport) Do(req *http.Request) (*http.Response, error) {
+	t.once.Do(t.init)
+	if t.err != nil {
+		return nil, t.err
+	}
+	return t.httpClient.Do(req)
+}
+
+func (t *transport) init() {
+	if t.auditLog == nil && t.envAuditLog != "" {
+		if t.auditLog, t.err = openLog(t.envAuditLog); t.err != nil {
+			t.err = fmt.Errorf("AIRLY_AUDIT_LOG: %w", t.err)
+			return
+		}
+	}
+	// nil makes AuditingClient use its default client
+	var client airly.HttpClient
+	if t.replay != nil {
+		client = t.replay
+	}
+	if t.record != nil {
+		client = auditingClient(t.record, client, true)
+	}
+	if t.auditLog != nil {
+		client = auditingClient(t.auditLog, client, false)
+	}
+	if client == nil {
+		client = &http.Client{Timeout: airly.DefaultTimeout}
+	}
+	t.httpClient = client
+}
+
+func openLog(path string) (*os.File, error) {
+	return os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+}
+
+// auditingClient returns AuditingClient writing to log and sending requests with client
+func auditingClient(log *os.File, client airly.HttpClient, bodies bool) *airly.AuditingClient {
+	return &airly.AuditingClient{HttpClient: client, Log: log, RecordBodies: bodies, OnError: func(err error) {
+		printError(fmt.Errorf("audit log: %w", err))
+	}}
+}
+
+// auditLogFlag makes client record requests in audit log file, with response bodies if bodies is set
+type auditLogFlag struct {
+	transport *transport
+	bodies    bool
+	path      string
+}
+
+func (f *auditLogFlag) String() string {
+	return f.path
+}
+
+func (f *auditLogFlag) Set(path string) error {
+	if f.bodies && f.transport.replay != nil {
+		return errRecordReplay
+	}
+	file, err := openLog(path)
+	if err != nil {
+		return err
+	}
+	log := &f.transport.auditLog
+	if f.bodies {
+		log = &f.transport.record
+	}
+	if *log != nil {
+		_ = (*log).Close()
+	}
+	*log = file
+	f.path = path
+	f.transport.client.HttpClient = f.transport
+	return nil
+}
+
+// replayFlag makes client serve responses recorded in file
+type replayFlag struct {
+	transport *transport
+}
+
+func (f replayFlag) String() string {
+	return ""
+}
+
+func (f replayFlag) Set(path string) error {
+	if f.transport.record != nil {
+		return errRecordReplay
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	replay, err := airly.NewReplayClient(file)
+	if err != nil {
+		return err
+	}
+	f.transport.replay = replay
+	f.transport.client.HttpClient = f.transport
+	return nil
+}
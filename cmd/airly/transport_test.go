@@ -0,0 +1,79 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func lines(t *testing.T, path string) []string {
+	data, err := ioutil.ReadFile(path)
+	assert.NoError(t, err)
+	return strings.Split(strings.TrimSpace(string(data)), "\n")
+}
+
+func TestAuditLogAndRecord(t *testing.T) {
+	dir, err := ioutil.TempDir("", "airly")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id": 204}`)
+	}))
+	defer server.Close()
+
+	env := filepath.Join(dir, "env.jsonl")
+	defer os.Setenv("AIRLY_AUDIT_LOG", os.Getenv("AIRLY_AUDIT_LOG"))
+	assert.NoError(t, os.Setenv("AIRLY_AUDIT_LOG", env))
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	client := clientFlags(fs)
+	audit, record := filepath.Join(dir, "audit.jsonl"), filepath.Join(dir, "recorded.jsonl")
+	assert.Nil(t, fs.Parse([]string{"--record", record, "--audit-log", audit}))
+	client.BaseURLs = []string{server.URL}
+	_, err = client.Installation(204)
+	assert.NoError(t, err)
+
+	assert.Len(t, lines(t, audit), 1)
+	assert.NotContains(t, lines(t, audit)[0], `"body"`)
+	assert.Len(t, lines(t, record), 1)
+	assert.Contains(t, lines(t, record)[0], `"body"`)
+	_, err = os.Stat(env)
+	assert.True(t, os.IsNotExist(err), "audit log from environment should not be created")
+
+	fs = flag.NewFlagSet("test", flag.ContinueOnError)
+	clientFlags(fs)
+	assert.NotNil(t, fs.Set("audit-log", filepath.Join(dir, "missing", "audit.jsonl")))
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	dir, err := ioutil.TempDir("", "airly")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "recorded.jsonl")
+	assert.NoError(t, ioutil.WriteFile(path, []byte(`{"endpoint":"installations/204","status":200,"body":"{\"id\":204}"}`+"\n"), 0600))
+
+	env := filepath.Join(dir, "env.jsonl")
+	defer os.Setenv("AIRLY_AUDIT_LOG", os.Getenv("AIRLY_AUDIT_LOG"))
+	assert.NoError(t, os.Setenv("AIRLY_AUDIT_LOG", env))
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	client := clientFlags(fs)
+	assert.Nil(t, fs.Parse([]string{"--replay", path}))
+	i, err := client.Installation(204)
+	assert.NoError(t, err)
+	assert.Equal(t, 204, i.Id)
+	assert.Len(t, lines(t, env), 1, "replayed requests should be recorded in audit log")
+
+	assert.Equal(t, errRecordReplay, fs.Set("record", filepath.Join(dir, "new.jsonl")))
+	fs = flag.NewFlagSet("test", flag.ContinueOnError)
+	clientFlags(fs)
+	assert.Nil(t, fs.Set("record", filepath.Join(dir, "new.jsonl")))
+	assert.Equal(t, errRecordReplay, fs.Set("replay", path))
+}
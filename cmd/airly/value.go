@@ -0,0 +1,116 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/probakowski/go-airly"
+	"sort"
+	"strings"
+)
+
+func init() {
+	commands["value"] = command{"Print single current value, e.g. value pm25 --installation 204", valueCommand}
+}
+
+func valueCommand(args []string) int {
+	fs := flag.NewFlagSet("value", flag.ContinueOnError)
+	client := clientFlags(fs)
+	target := targetFlags(fs)
+	field := fs.String("field", "value", "Field to print: value or index.name, index.value, index.level, "+
+		"index.description, index.advice, index.color, index.emoji, index.label of the first index")
+	labels := airly.LevelLabels{}
+	fs.Var(levelLabelsFlag(labels), "label", "Override emoji and label of index level as LEVEL=EMOJI:LABEL, "+
+		"either part can be empty, e.g. HIGH=🟠:Bad, can be repeated")
+	var failIf conditions
+	failIf.flags(fs)
+	positional, err := parse(fs, args)
+	if err != nil {
+		return exitUsage
+	}
+	var name string
+	if len(positional) == 1 {
+		name = positional[0]
+	}
+	if len(positional) > 1 || (name == "" && *field == "value") {
+		printError(usageError("Usage: airly value <name> [flags]"))
+		return exitUsage
+	}
+
+	m, err := target.measurements(*client)
+	if err != nil {
+		printError(err)
+		return exitError
+	}
+	s, err := fieldValue(m.Current, name, *field, labels)
+	if err != nil {
+		printError(err)
+		return exitError
+	}
+	fmt.Println(s)
+
+	failed, err := failIf.failed(m.Current)
+	if err != nil {
+		printError(err)
+		return exitError
+	}
+	if len(failed) > 0 {
+		return exitFailed
+	}
+	return exitOK
+}
+
+// fieldValue formats single field of measurement, see value command --field flag
+func fieldValue(m airly.Measurement, name, field string, labels airly.LevelLabels) (string, error) {
+	if field == "value" {
+		v, ok := value(m, name)
+		if !ok {
+			return "", fmt.Errorf("no value %s in measurement", name)
+		}
+		return formatFloat(v), nil
+	}
+	if len(m.Indexes) == 0 {
+		return "", fmt.Errorf("no index in measurement")
+	}
+	index := m.Indexes[0]
+	switch field {
+	case "index.name":
+		return index.Name, nil
+	case "index.value":
+		return formatFloat(index.Value), nil
+	case "index.level":
+		return index.Level, nil
+	case "index.description":
+		return index.Description, nil
+	case "index.advice":
+		return index.Advice, nil
+	case "index.color":
+		return index.Color, nil
+	case "index.emoji":
+		return labels.Label(index.Level).Emoji, nil
+	case "index.label":
+		return labels.Label(index.Level).Short, nil
+	}
+	return "", fmt.Errorf("unknown field %q", field)
+}
+
+// levelLabelsFlag is flag.Value adding LEVEL=EMOJI:LABEL overrides to level labels
+type levelLabelsFlag airly.LevelLabels
+
+func (l levelLabelsFlag) String() string {
+	var parts []string
+	for level, label := range l {
+		parts = append(parts, level+"="+label.Emoji+":"+label.Short)
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, ",")
+}
+
+func (l levelLabelsFlag) Set(s string) error {
+	i := strings.Index(s, "=")
+	j := strings.LastIndex(s, ":")
+	if i <= 0 || j < i {
+		return fmt.Errorf("level label must be given as LEVEL=EMOJI:LABEL")
+	}
+	l[strings.ToUpper(s[:i])] = airly.LevelLabel{Emoji: s[i+1 : j], Short: s[j+1:]}
+	return nil
+}
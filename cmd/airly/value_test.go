@@ -0,0 +1,59 @@
+package main
+
+import (
+	"github.com/probakowski/go-airly"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestFieldValue(t *testing.T) {
+	m := airly.Measurement{
+		Values:  []airly.Value{{Name: "PM25", Value: 18.7}},
+		Indexes: []airly.Index{{Name: "AIRLY_CAQI", Value: 35.53, Level: "LOW", Color: "#D1CF1E"}},
+	}
+	for _, tc := range []struct {
+		name, field, expected string
+	}{
+		{"pm25", "value", "18.7"},
+		{"caqi", "value", "35.53"},
+		{"", "index.level", "LOW"},
+		{"", "index.color", "#D1CF1E"},
+		{"", "index.value", "35.53"},
+		{"", "index.emoji", "🟢"},
+		{"", "index.label", "Good"},
+	} {
+		s, err := fieldValue(m, tc.name, tc.field, nil)
+		assert.Nil(t, err)
+		assert.Equal(t, tc.expected, s)
+	}
+
+	_, err := fieldValue(m, "pm10", "value", nil)
+	assert.NotNil(t, err)
+	_, err = fieldValue(m, "", "index.unknown", nil)
+	assert.NotNil(t, err)
+	_, err = fieldValue(airly.Measurement{}, "", "index.level", nil)
+	assert.NotNil(t, err)
+}
+
+func TestLevelLabelsFlag(t *testing.T) {
+	labels := airly.LevelLabels{}
+	f := levelLabelsFlag(labels)
+	assert.Nil(t, f.Set("low=:OK"))
+	assert.Nil(t, f.Set("HIGH=🔥:Bad"))
+	assert.Equal(t, "HIGH=🔥:Bad,LOW=:OK", f.String())
+	assert.Equal(t, airly.LevelLabel{Emoji: "🟢", Short: "OK"}, labels.Label("LOW"))
+
+	m := airly.Measurement{Indexes: []airly.Index{{Level: "HIGH"}}}
+	s, err := fieldValue(m, "", "index.emoji", labels)
+	assert.Nil(t, err)
+	assert.Equal(t, "🔥", s)
+
+	assert.NotNil(t, f.Set("HIGH"))
+	assert.NotNil(t, f.Set("=🔥:Bad"))
+	assert.NotNil(t, f.Set("HIGH:x=y"))
+}
+
+func TestValueCommandUsage(t *testing.T) {
+	assert.Equal(t, exitUsage, valueCommand(nil))
+	assert.Equal(t, exitUsage, valueCommand([]string{"pm25", "pm10", "--installation", "204"}))
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"github.com/probakowski/go-airly"
+	"os"
+	"strconv"
+)
+
+func init() {
+	commands["zabbix"] = command{"Zabbix low-level discovery (discover) and item values (item)", zabbix}
+}
+
+// zabbixDiscovery is low-level discovery output, see https://www.zabbix.com/documentation/current/manual/discovery/low_level_discovery
+type zabbixDiscovery struct {
+	Data []map[string]string `json:"data"`
+}
+
+func zabbix(args []string) int {
+	if len(args) == 0 {
+		printError(usageError("Usage: airly zabbix discover|item [flags]"))
+		return exitUsage
+	}
+	switch args[0] {
+	case "discover":
+		return zabbixDiscover(args[1:])
+	case "item":
+		return zabbixItem(args[1:])
+	}
+	printError(usageError(fmt.Sprintf("unknown zabbix mode %q", args[0])))
+	return exitUsage
+}
+
+func zabbixDiscover(args []string) int {
+	fs := flag.NewFlagSet("zabbix discover", flag.ContinueOnError)
+	client := clientFlags(fs)
+	var installations installationList
+	fs.Var(&installations, "installations", "Comma separated list of installation IDs or aliases to discover, "+
+		"all installations in area are discovered if location is set")
+	loc := locationFlags(fs, " of the area to discover, installations in area are fetched with a single request")
+	maxDistance := fs.Float64("max-distance", 3, "Area radius in km")
+	if err := parseFlags(fs, args); err != nil {
+		return exitUsage
+	}
+	if len(installations) == 0 && *loc == (airly.Location{}) {
+		printError(usageError("Usage: airly zabbix discover --installations <id,...> | --lat <lat> --lng <lng> [flags]"))
+		return exitUsage
+	}
+
+	// installations in area are reused, only installations outside of it are fetched one by one
+	discover := []int(installations)
+	found := map[int]airly.Installation{}
+	if *loc != (airly.Location{}) {
+		all, err := client.NearestInstallations(*loc, airly.MaxDistance(*maxDistance), airly.AllResults())
+		if err != nil {
+			printError(err)
+			return exitError
+		}
+		for _, installation := range all {
+			found[installation.Id] = installation
+			if len(installations) == 0 {
+				discover = append(discover, installation.Id)
+			}
+		}
+	}
+
+	discovery := zabbixDiscovery{Data: []map[string]string{}}
+	for _, id := range discover {
+		installation, ok := found[id]
+		if !ok {
+			var err error
+			if installation, err = client.Installation(id); err != nil {
+				printInstallationError(id, err)
+				return exitError
+			}
+		}
+		measurements, err := client.InstallationMeasurements(id)
+		if err != nil {
+			printInstallationError(id, err)
+			return exitError
+		}
+		discovery.Data = append(discovery.Data, zabbixMacros(installation, measurements.Current)...)
+	}
+	if err := json.NewEncoder(os.Stdout).Encode(discovery); err != nil {
+		printError(err)
+		return exitError
+	}
+	return exitOK
+}
+
+// zabbixMacros returns one discovery entry per value and index reported by installation
+func zabbixMacros(installation airly.Installation, current airly.Measurement) []map[string]string {
+	var names []string
+	for _, v := range current.Values {
+		names = append(names, v.Name)
+	}
+	for _, i := range current.Indexes {
+		names = append(names, i.Name)
+	}
+	data := make([]map[string]string, 0, len(names))
+	for _, name := range names {
+		data = append(data, map[string]string{
+			"{#INSTALLATION.ID}":      strconv.Itoa(installation.Id),
+			"{#INSTALLATION.CITY}":    installation.Address.City,
+			"{#INSTALLATION.ADDRESS}": installation.Address.DisplayAddress2,
+			"{#VALUE.NAME}":           name,
+		})
+	}
+	return data
+}
+
+func zabbixItem(args []string) int {
+	fs := flag.NewFlagSet("zabbix item", flag.ContinueOnError)
+	client := clientFlags(fs)
+	id := -1
+	fs.Var((*installationFlag)(&id), "installation", "Installation ID or alias, required")
+	name := fs.String("name", "", "Value or index name, e.g. PM25 or AIRLY_CAQI, required")
+	if err := parseFlags(fs, args); err != nil {
+		return exitUsage
+	}
+	if id == -1 || *name == "" {
+		printError(usageError("Usage: airly zabbix item --installation <id> --name <name> [flags]"))
+		return exitUsage
+	}
+
+	measurements, err := client.InstallationMeasurements(id)
+	if err != nil {
+		printError(err)
+		return exitError
+	}
+	v, ok := value(measurements.Current, *name)
+	if !ok {
+		printError(fmt.Errorf("no value %s for installation %d", *name, id))
+		return exitError
+	}
+	fmt.Println(formatFloat(v))
+	return exitOK
+}
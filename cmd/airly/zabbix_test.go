@@ -0,0 +1,55 @@
+package main
+
+import (
+	"github.com/probakowski/go-airly"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+func TestZabbixMacros(t *testing.T) {
+	installation := airly.Installation{
+		Id:      204,
+		Address: airly.Address{City: "Kraków", DisplayAddress2: "Mikołajska"},
+	}
+	current := airly.Measurement{
+		Values:  []airly.Value{{Name: "PM25", Value: 18.7}},
+		Indexes: []airly.Index{{Name: "AIRLY_CAQI", Value: 35.53}},
+	}
+	assert.Equal(t, []map[string]string{{
+		"{#INSTALLATION.ID}":      "204",
+		"{#INSTALLATION.CITY}":    "Kraków",
+		"{#INSTALLATION.ADDRESS}": "Mikołajska",
+		"{#VALUE.NAME}":           "PM25",
+	}, {
+		"{#INSTALLATION.ID}":      "204",
+		"{#INSTALLATION.CITY}":    "Kraków",
+		"{#INSTALLATION.ADDRESS}": "Mikołajska",
+		"{#VALUE.NAME}":           "AIRLY_CAQI",
+	}}, zabbixMacros(installation, current))
+}
+
+func TestZabbixUsage(t *testing.T) {
+	assert.Equal(t, exitUsage, zabbix([]string{"discover"}))
+	assert.Equal(t, exitUsage, zabbix([]string{"discover", "--installations", "204", "8077"}))
+	assert.Equal(t, exitUsage, zabbix([]string{"item", "--name", "PM25"}))
+	assert.Equal(t, exitUsage, zabbix([]string{"item", "--installation", "204"}))
+}
+
+func TestZabbixDiscoverArea(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "recorded.jsonl")
+	log := `{"endpoint":"installations/nearest","params":{"lat":"50.062","lng":"19.941","maxDistanceKM":"3","maxResults":"-1"},"status":200,"body":` +
+		`"[{\"id\":1,\"address\":{\"city\":\"Kraków\"}},{\"id\":2}]"}` + "\n"
+	for _, id := range []string{"1", "2"} {
+		log += `{"endpoint":"measurements/installation","params":{"installationId":"` + id + `"},"status":200,"body":` +
+			`"{\"current\":{\"values\":[{\"name\":\"PM25\",\"value\":` + id + `}]}}"}` + "\n"
+	}
+	assert.NoError(t, ioutil.WriteFile(path, []byte(log), 0600))
+	args := []string{"discover", "--replay", path, "--lat", "50.062", "--lng", "19.941"}
+
+	// installations aren't recorded one by one, so they must be taken from the area
+	assert.Equal(t, exitOK, zabbix(args))
+	assert.Equal(t, exitOK, zabbix(append(args, "--installations", "2")))
+	assert.Equal(t, exitError, zabbix(append(args, "--installations", "3")))
+}
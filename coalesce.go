@@ -0,0 +1,133 @@
+package airly
+
+import (
+	"sync"
+	"time"
+)
+
+// PointCoalescer serves PointMeasurements for locations close to each other from a single API call.
+// Interpolated values barely differ within short distance, so requests for locations within Radius of
+// location requested in the last TTL (or being requested at the moment) reuse its result.
+// PointCoalescer is safe for concurrent use, each caller gets its own copy of the result
+type PointCoalescer struct {
+	Client Client
+	// Radius in km, 0.25 is used if not set
+	Radius float64
+	// TTL of results, 5 minutes is used if not set
+	TTL time.Duration
+
+	mu      sync.Mutex
+	entries []*pointEntry
+	now     func() time.Time
+}
+
+type pointEntry struct {
+	location Location
+	// index is index type and pollutant requested
+	index   string
+	fetched time.Time
+	done    chan struct{}
+	m       Measurements
+	err     error
+}
+
+// PointMeasurements works like Client.PointMeasurements but reuses results for nearby locations
+func (p *PointCoalescer) PointMeasurements(loc Location, options ...MeasurementsOption) (Measurements, error) {
+	config := newMeasurementsConfig(options)
+	index := config.indexType + "/" + config.indexPollutant
+	p.mu.Lock()
+	now := p.time()
+	entry := p.lookup(loc, index, now)
+	if entry != nil {
+		p.mu.Unlock()
+		<-entry.done
+		return copyMeasurements(entry.m), entry.err
+	}
+	entry = &pointEntry{location: loc, index: index, done: make(chan struct{})}
+	p.entries = append(p.entries, entry)
+	p.mu.Unlock()
+
+	entry.m, entry.err = p.Client.PointMeasurements(loc, options...)
+	p.mu.Lock()
+	entry.fetched = p.time()
+	if entry.err != nil {
+		p.remove(entry)
+	}
+	p.mu.Unlock()
+	close(entry.done)
+	return copyMeasurements(entry.m), entry.err
+}
+
+// lookup returns in-flight or fresh entry close to loc, expired entries are dropped
+func (p *PointCoalescer) lookup(loc Location, index string, now time.Time) *pointEntry {
+	radius, ttl := p.Radius, p.TTL
+	if radius == 0 {
+		radius = 0.25
+	}
+	if ttl == 0 {
+		ttl = 5 * time.Minute
+	}
+	var found *pointEntry
+	entries := p.entries[:0]
+	for _, e := range p.entries {
+		inFlight := e.fetched.IsZero()
+		if !inFlight && now.Sub(e.fetched) > ttl {
+			continue
+		}
+		entries = append(entries, e)
+		if found == nil && e.index == index && e.location.Distance(loc) <= radius {
+			found = e
+		}
+	}
+	p.entries = entries
+	return found
+}
+
+// copyMeasurements copies slices of m so callers sharing an entry can't see each other's changes
+func copyMeasurements(m Measurements) Measurements {
+	m.Current = copyMeasurement(m.Current)
+	if m.History != nil {
+		history := make([]Measurement, len(m.History))
+		for i, h := range m.History {
+			history[i] = copyMeasurement(h)
+		}
+		m.History = history
+	}
+	if m.Forecast != nil {
+		forecast := make([]Measurement, len(m.Forecast))
+		for i, f := range m.Forecast {
+			forecast[i] = copyMeasurement(f)
+		}
+		m.Forecast = forecast
+	}
+	return m
+}
+
+func copyMeasurement(m Measurement) Measurement {
+	if m.Values != nil {
+		m.Values = append(make([]Value, 0, len(m.Values)), m.Values...)
+	}
+	if m.Indexes != nil {
+		m.Indexes = append(make([]Index, 0, len(m.Indexes)), m.Indexes...)
+	}
+	if m.Standards != nil {
+		m.Standards = append(make([]Standard, 0, len(m.Standards)), m.Standards...)
+	}
+	return m
+}
+
+func (p *PointCoalescer) remove(entry *pointEntry) {
+	for i, e := range p.entries {
+		if e == entry {
+			p.entries = append(p.entries[:i], p.entries[i+1:]...)
+			return
+		}
+	}
+}
+
+func (p *PointCoalescer) time() time.Time {
+	if p.now != nil {
+		return p.now()
+	}
+	return time.Now()
+}
@@ -0,0 +1,136 @@
+package airly
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestPointCoalescer(t *testing.T) {
+	calls := 0
+	now := time.Date(2021, 10, 1, 12, 0, 0, 0, time.UTC)
+	p := PointCoalescer{
+		Client: Client{
+			Key: "x1234x",
+			HttpClient: mockClient{func(req *http.Request) (*http.Response, error) {
+				calls++
+				return &http.Response{
+					StatusCode: 200,
+					Body:       readCloser(`{"current": {"values": [{"name": "PM25", "value": 18.7}]}}`),
+				}, nil
+			}},
+		},
+		now: func() time.Time { return now },
+	}
+
+	m, err := p.PointMeasurements(Location{50.062006, 19.940984})
+	assert.Nil(t, err)
+	assert.Equal(t, []Value{{Name: "PM25", Value: 18.7}}, m.Current.Values)
+
+	// ~100m away
+	_, err = p.PointMeasurements(Location{50.062906, 19.940984})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, calls)
+
+	// different index type
+	_, err = p.PointMeasurements(Location{50.062006, 19.940984}, WithIndexType("PIJP"))
+	assert.Nil(t, err)
+	assert.Equal(t, 2, calls)
+
+	// ~1km away
+	_, err = p.PointMeasurements(Location{50.071006, 19.940984})
+	assert.Nil(t, err)
+	assert.Equal(t, 3, calls)
+
+	now = now.Add(6 * time.Minute)
+	_, err = p.PointMeasurements(Location{50.062006, 19.940984})
+	assert.Nil(t, err)
+	assert.Equal(t, 4, calls)
+	assert.Len(t, p.entries, 1)
+}
+
+func TestPointCoalescerCopy(t *testing.T) {
+	p := PointCoalescer{
+		Client: Client{
+			Key: "x1234x",
+			HttpClient: mockClient{func(req *http.Request) (*http.Response, error) {
+				return &http.Response{
+					StatusCode: 200,
+					Body: readCloser(`{"current": {"values": [{"name": "PM25", "value": 18.7}],
+						"indexes": [{"name": "AIRLY_CAQI", "value": 35.5}]},
+						"history": [{"values": [{"name": "PM25", "value": 20.1}]}]}`),
+				}, nil
+			}},
+		},
+	}
+
+	m, err := p.PointMeasurements(Location{50.062006, 19.940984})
+	assert.Nil(t, err)
+	m.Current.Values[0].Value = 0
+	m.Current.Indexes[0].Value = 0
+	m.History[0].Values[0].Value = 0
+	m.History = append(m.History[:0], Measurement{})
+
+	m, err = p.PointMeasurements(Location{50.062006, 19.940984})
+	assert.Nil(t, err)
+	assert.Equal(t, []Value{{Name: "PM25", Value: 18.7}}, m.Current.Values)
+	assert.Equal(t, 35.5, m.Current.Indexes[0].Value)
+	assert.Equal(t, []Value{{Name: "PM25", Value: 20.1}}, m.History[0].Values)
+	assert.Nil(t, m.Forecast)
+}
+
+func TestPointCoalescerConcurrent(t *testing.T) {
+	var mu sync.Mutex
+	calls := 0
+	release := make(chan struct{})
+	p := PointCoalescer{
+		Client: Client{
+			Key: "x1234x",
+			HttpClient: mockClient{func(req *http.Request) (*http.Response, error) {
+				mu.Lock()
+				calls++
+				mu.Unlock()
+				<-release
+				return &http.Response{
+					StatusCode: 200,
+					Body:       readCloser(`{}`),
+				}, nil
+			}},
+		},
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := p.PointMeasurements(Location{50.062006, 19.940984})
+			assert.Nil(t, err)
+		}()
+	}
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	assert.Equal(t, 1, calls)
+}
+
+func TestPointCoalescerError(t *testing.T) {
+	err := errors.New("error")
+	calls := 0
+	p := PointCoalescer{
+		Client: Client{
+			Key: "x1234x",
+			HttpClient: mockClient{func(req *http.Request) (*http.Response, error) {
+				calls++
+				return nil, err
+			}},
+		},
+	}
+	_, err2 := p.PointMeasurements(Location{50.062006, 19.940984})
+	assert.ErrorIs(t, err2, err)
+	_, err2 = p.PointMeasurements(Location{50.062006, 19.940984})
+	assert.ErrorIs(t, err2, err)
+	assert.Equal(t, 2, calls)
+}
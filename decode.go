@@ -0,0 +1,192 @@
+package airly
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// snippetContext is number of bytes of body included in DecodeError before and after offending offset
+const snippetContext = 64
+
+// DecodeError is returned when response body can't be decoded, it gives context needed to debug changes of API
+// schema
+type DecodeError struct {
+	// Endpoint is request path without version and query, e.g. measurements/installation
+	Endpoint string
+	// Field is path of offending field, e.g. current.indexes.0.value, empty if unknown
+	Field string
+	// Offset in body where decoding failed, -1 if unknown
+	Offset int64
+	// Snippet of body around Offset (or its beginning), truncated parts are marked with ...
+	Snippet string
+	Err     error
+}
+
+func (e *DecodeError) Error() string {
+	var sb strings.Builder
+	sb.WriteString("cannot decode response of ")
+	sb.WriteString(e.Endpoint)
+	if e.Field != "" {
+		sb.WriteString(", field ")
+		sb.WriteString(e.Field)
+	}
+	sb.WriteString(": ")
+	sb.WriteString(e.Err.Error())
+	if e.Snippet != "" {
+		sb.WriteString(", body: ")
+		sb.WriteString(e.Snippet)
+	}
+	return sb.String()
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// newDecodeError returns DecodeError with context found in err and body (which can be nil for streamed responses),
+// nil is returned for nil err
+func newDecodeError(endpoint string, body []byte, err error) error {
+	if err == nil {
+		return nil
+	}
+	decodeErr := &DecodeError{Endpoint: endpoint, Offset: -1, Err: err}
+	var typeErr *json.UnmarshalTypeError
+	var syntaxErr *json.SyntaxError
+	var unknownErr *UnknownFieldsError
+	if errors.As(err, &typeErr) {
+		decodeErr.Field = typeErr.Field
+		decodeErr.Offset = typeErr.Offset
+	} else if errors.As(err, &syntaxErr) {
+		decodeErr.Offset = syntaxErr.Offset
+	} else if errors.As(err, &unknownErr) {
+		decodeErr.Field = unknownErr.Fields[0]
+	}
+	if len(body) > 0 {
+		decodeErr.Snippet = snippet(body, decodeErr.Offset)
+	}
+	return decodeErr
+}
+
+// snippet returns part of body around offset, beginning of body is used if offset is outside of it
+func snippet(body []byte, offset int64) string {
+	start, end := int64(0), int64(2*snippetContext)
+	if offset >= 0 && offset <= int64(len(body)) {
+		start, end = offset-snippetContext, offset+snippetContext
+		if start < 0 {
+			start = 0
+		}
+	}
+	if end > int64(len(body)) {
+		end = int64(len(body))
+	}
+	s := string(body[start:end])
+	if start > 0 {
+		s = "..." + s
+	}
+	if end < int64(len(body)) {
+		s += "..."
+	}
+	return fmt.Sprintf("%q", s)
+}
+
+// UnknownFieldsError is returned (as Err of DecodeError) in Client.Strict mode for responses with fields unknown to
+// this package
+type UnknownFieldsError struct {
+	// Fields are paths of unknown fields, sorted within objects, e.g. current.values.0.unit
+	Fields []string
+}
+
+func (e *UnknownFieldsError) Error() string {
+	return "unknown fields " + strings.Join(e.Fields, ", ")
+}
+
+var unmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+// checkUnknownFields returns UnknownFieldsError if JSON body has fields without matching field in type of v.
+// json.Decoder.DisallowUnknownFields can't be used, as it doesn't apply to types with custom UnmarshalJSON like Value
+func checkUnknownFields(body []byte, v interface{}) error {
+	var raw interface{}
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	if err := dec.Decode(&raw); err != nil {
+		return err
+	}
+	var unknown []string
+	walkUnknownFields(raw, reflect.TypeOf(v), "", &unknown)
+	if len(unknown) > 0 {
+		return &UnknownFieldsError{Fields: unknown}
+	}
+	return nil
+}
+
+func walkUnknownFields(raw interface{}, t reflect.Type, path string, unknown *[]string) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch raw := raw.(type) {
+	case map[string]interface{}:
+		switch {
+		case t.Kind() == reflect.Map:
+			for _, key := range sortedKeys(raw) {
+				walkUnknownFields(raw[key], t.Elem(), joinPath(path, key), unknown)
+			}
+		case t.Kind() == reflect.Struct && (t == reflect.TypeOf(Value{}) || !reflect.PtrTo(t).Implements(unmarshalerType)):
+			fields := jsonFields(t)
+			for _, key := range sortedKeys(raw) {
+				field, ok := fields[strings.ToLower(key)]
+				if !ok {
+					*unknown = append(*unknown, joinPath(path, key))
+					continue
+				}
+				walkUnknownFields(raw[key], field, joinPath(path, key), unknown)
+			}
+		}
+	case []interface{}:
+		if t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+			for i, element := range raw {
+				walkUnknownFields(element, t.Elem(), joinPath(path, strconv.Itoa(i)), unknown)
+			}
+		}
+	}
+}
+
+// jsonFields returns types of struct fields keyed by lowercase JSON names, encoding/json matches names
+// case-insensitively
+func jsonFields(t reflect.Type) map[string]reflect.Type {
+	fields := map[string]reflect.Type{}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" || f.PkgPath != "" {
+			continue
+		}
+		name := strings.Split(tag, ",")[0]
+		if name == "" {
+			name = f.Name
+		}
+		fields[strings.ToLower(name)] = f.Type
+	}
+	return fields
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func joinPath(path, element string) string {
+	if path == "" {
+		return element
+	}
+	return path + "." + element
+}
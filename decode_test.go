@@ -0,0 +1,78 @@
+package airly
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestDecodeError(t *testing.T) {
+	body := `{"current": {"indexes": [{"name": "AIRLY_CAQI", "value": "35.53"}]}}`
+	api := Client{HttpClient: mockClient{func(req *http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: 200, Body: readCloser(body)}, nil
+	}}}
+	_, err := api.InstallationMeasurements(204)
+	var decodeErr *DecodeError
+	if assert.True(t, errors.As(err, &decodeErr)) {
+		assert.Equal(t, "measurements/installation", decodeErr.Endpoint)
+		assert.Equal(t, "current.indexes.0.value", decodeErr.Field)
+		assert.Equal(t, int64(strings.Index(body, `"35.53"`)+len(`"35.53"`)), decodeErr.Offset)
+		assert.Equal(t, `"{\"current\": {\"indexes\": [{\"name\": \"AIRLY_CAQI\", \"value\": \"35.53\"}]}}"`, decodeErr.Snippet)
+		var typeErr *json.UnmarshalTypeError
+		assert.True(t, errors.As(err, &typeErr))
+	}
+	assert.Contains(t, err.Error(), "cannot decode response of measurements/installation, field current.indexes.0.value: ")
+
+	body = `{"current": {"values": [{"name": "PM25", "value": "n/a"}]}}`
+	_, err = api.InstallationMeasurements(204)
+	assert.Contains(t, err.Error(), `invalid value "n/a" of PM25`)
+
+	body = `{"id": 204,`
+	_, err = api.Installation(204)
+	if assert.True(t, errors.As(err, &decodeErr)) {
+		assert.Equal(t, "installations/204", decodeErr.Endpoint)
+		assert.Equal(t, int64(len(body)), decodeErr.Offset)
+	}
+}
+
+func TestSnippet(t *testing.T) {
+	body := []byte(strings.Repeat("a", 100) + "X" + strings.Repeat("b", 100))
+	assert.Equal(t, `"...`+strings.Repeat("a", 64)+"X"+strings.Repeat("b", 63)+`..."`, snippet(body, 100))
+	assert.Equal(t, `"`+strings.Repeat("a", 100)+"X"+strings.Repeat("b", 27)+`..."`, snippet(body, -1))
+	assert.Equal(t, `"short"`, snippet([]byte("short"), 3))
+}
+
+func TestStrict(t *testing.T) {
+	body := `{"current": {"fromDateTime": "2021-03-01T12:00:00Z", "values": [{"name": "PM25", "value": 18.7}],
+		"indexes": [{"name": "AIRLY_CAQI", "value": 35.53, "level": "LOW"}]}, "history": [], "forecast": []}`
+	api := Client{HttpClient: mockClient{func(req *http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: 200, Body: readCloser(body)}, nil
+	}}}
+	_, err := api.InstallationMeasurements(204)
+	assert.NoError(t, err)
+	api.Strict = true
+	_, err = api.InstallationMeasurements(204)
+	assert.NoError(t, err)
+
+	body = `{"current": {"values": [{"name": "PM25", "value": 18.7, "unit": "µg/m³"}], "quality": 1}, "Forecast": []}`
+	api.Strict = false
+	_, err = api.InstallationMeasurements(204)
+	assert.NoError(t, err, "unknown fields should be ignored by default")
+	api.Strict = true
+	_, err = api.InstallationMeasurements(204)
+	var unknownErr *UnknownFieldsError
+	if assert.True(t, errors.As(err, &unknownErr)) {
+		assert.Equal(t, []string{"current.quality", "current.values.0.unit"}, unknownErr.Fields)
+	}
+	var decodeErr *DecodeError
+	if assert.True(t, errors.As(err, &decodeErr)) {
+		assert.Equal(t, "current.quality", decodeErr.Field)
+	}
+
+	body = `[{"id": 204, "location": {"latitude": 50.06, "longitude": 19.94}, "tags": []}]`
+	err = api.EachNearestInstallation(Location{}, func(Installation) error { return nil })
+	assert.Contains(t, err.Error(), `unknown field "tags"`)
+}
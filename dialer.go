@@ -0,0 +1,117 @@
+package airly
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// HostResolver resolves host names to addresses, it's implemented by *net.Resolver
+type HostResolver interface {
+	LookupHost(ctx context.Context, host string) ([]string, error)
+}
+
+// CachingDialer dials connections to addresses resolved with Resolver (or FallbackResolver if it fails) and caches
+// resolved addresses. If both resolvers fail, addresses cached before are used even if expired, so temporary DNS
+// failures on flaky networks don't break requests. CachingDialer is safe for concurrent use
+type CachingDialer struct {
+	// Dial is used to open connections to resolved addresses, net.Dialer with 30s timeout is used if not set
+	Dial func(ctx context.Context, network, address string) (net.Conn, error)
+	// Resolver used to look up hosts, net.DefaultResolver is used if not set
+	Resolver HostResolver
+	// FallbackResolver used when Resolver fails, e.g. net.Resolver querying public DNS server, optional
+	FallbackResolver HostResolver
+	// TTL of cached addresses, 5 minutes is used if not set
+	TTL time.Duration
+
+	mu    sync.Mutex
+	cache map[string]dnsEntry
+	now   func() time.Time
+}
+
+type dnsEntry struct {
+	addrs    []string
+	resolved time.Time
+}
+
+// DialContext resolves host of address and dials resolved addresses until connection succeeds,
+// it can be used as http.Transport DialContext
+func (d *CachingDialer) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, err
+	}
+	dial := d.Dial
+	if dial == nil {
+		dial = (&net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}).DialContext
+	}
+	if net.ParseIP(host) != nil {
+		return dial(ctx, network, address)
+	}
+	addrs, err := d.lookup(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+	for _, addr := range addrs {
+		var conn net.Conn
+		if conn, err = dial(ctx, network, net.JoinHostPort(addr, port)); err == nil {
+			return conn, nil
+		}
+	}
+	return nil, err
+}
+
+// lookup returns cached addresses of host or resolves them if cache entry expired
+func (d *CachingDialer) lookup(ctx context.Context, host string) ([]string, error) {
+	ttl := d.TTL
+	if ttl == 0 {
+		ttl = 5 * time.Minute
+	}
+	d.mu.Lock()
+	entry, cached := d.cache[host]
+	now := d.time()
+	d.mu.Unlock()
+	if cached && now.Sub(entry.resolved) < ttl {
+		return entry.addrs, nil
+	}
+
+	resolver := d.Resolver
+	if resolver == nil {
+		resolver = net.DefaultResolver
+	}
+	addrs, err := resolver.LookupHost(ctx, host)
+	if err != nil && d.FallbackResolver != nil {
+		addrs, err = d.FallbackResolver.LookupHost(ctx, host)
+	}
+	if err != nil {
+		if cached {
+			return entry.addrs, nil
+		}
+		return nil, err
+	}
+
+	d.mu.Lock()
+	if d.cache == nil {
+		d.cache = map[string]dnsEntry{}
+	}
+	d.cache[host] = dnsEntry{addrs: addrs, resolved: now}
+	d.mu.Unlock()
+	return addrs, nil
+}
+
+func (d *CachingDialer) time() time.Time {
+	if d.now != nil {
+		return d.now()
+	}
+	return time.Now()
+}
+
+// NewHttpClient returns HTTP client with DefaultTimeout using given dial function, e.g. CachingDialer.DialContext,
+// other transport settings are the same as in http.DefaultTransport
+func NewHttpClient(dial func(ctx context.Context, network, address string) (net.Conn, error)) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = dial
+	return &http.Client{Transport: transport, Timeout: DefaultTimeout}
+}
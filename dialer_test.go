@@ -0,0 +1,95 @@
+package airly
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+)
+
+type mockResolver struct {
+	addrs []string
+	err   error
+	calls int
+}
+
+func (r *mockResolver) LookupHost(ctx context.Context, host string) ([]string, error) {
+	r.calls++
+	return r.addrs, r.err
+}
+
+func TestCachingDialer(t *testing.T) {
+	now := time.Date(2020, 11, 18, 20, 0, 0, 0, time.UTC)
+	resolver := &mockResolver{addrs: []string{"10.0.0.1", "10.0.0.2"}}
+	var dialed []string
+	d := &CachingDialer{
+		Resolver: resolver,
+		Dial: func(ctx context.Context, network, address string) (net.Conn, error) {
+			dialed = append(dialed, address)
+			if address == "10.0.0.1:443" {
+				return nil, errors.New("connection refused")
+			}
+			client, _ := net.Pipe()
+			return client, nil
+		},
+		now: func() time.Time { return now },
+	}
+
+	conn, err := d.DialContext(context.Background(), "tcp", "airapi.airly.eu:443")
+	assert.Nil(t, err)
+	assert.NotNil(t, conn)
+	assert.Equal(t, []string{"10.0.0.1:443", "10.0.0.2:443"}, dialed)
+
+	_, err = d.DialContext(context.Background(), "tcp", "airapi.airly.eu:443")
+	assert.Nil(t, err)
+	assert.Equal(t, 1, resolver.calls)
+
+	// expired entry is used if resolver fails
+	now = now.Add(10 * time.Minute)
+	resolver.err = errors.New("temporary failure in name resolution")
+	_, err = d.DialContext(context.Background(), "tcp", "airapi.airly.eu:443")
+	assert.Nil(t, err)
+	assert.Equal(t, 2, resolver.calls)
+
+	_, err = d.DialContext(context.Background(), "tcp", "example.com:443")
+	assert.EqualError(t, err, "temporary failure in name resolution")
+
+	// IP addresses are dialed directly
+	dialed = nil
+	_, err = d.DialContext(context.Background(), "tcp", "192.168.1.1:80")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"192.168.1.1:80"}, dialed)
+	assert.Equal(t, 3, resolver.calls)
+}
+
+func TestCachingDialerFallback(t *testing.T) {
+	fallback := &mockResolver{addrs: []string{"10.0.0.3"}}
+	var dialed string
+	d := &CachingDialer{
+		Resolver:         &mockResolver{err: errors.New("no such host")},
+		FallbackResolver: fallback,
+		Dial: func(ctx context.Context, network, address string) (net.Conn, error) {
+			dialed = address
+			return nil, errors.New("network is unreachable")
+		},
+	}
+	_, err := d.DialContext(context.Background(), "tcp", "airapi.airly.eu:443")
+	assert.EqualError(t, err, "network is unreachable")
+	assert.Equal(t, "10.0.0.3:443", dialed)
+	assert.Equal(t, 1, fallback.calls)
+
+	_, err = d.DialContext(context.Background(), "tcp", "airapi.airly.eu")
+	assert.NotNil(t, err)
+}
+
+func TestNewHttpClient(t *testing.T) {
+	d := &CachingDialer{}
+	client := NewHttpClient(d.DialContext)
+	assert.Equal(t, DefaultTimeout, client.Timeout)
+	transport := client.Transport.(*http.Transport)
+	assert.NotNil(t, transport.DialContext)
+	assert.Equal(t, http.DefaultTransport.(*http.Transport).TLSHandshakeTimeout, transport.TLSHandshakeTimeout)
+}
@@ -0,0 +1,127 @@
+// Package email sends HTML reports with inline images via SMTP
+package email
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"strings"
+	"time"
+)
+
+// Image embedded in message, it can be referenced in HTML with cid:ContentID
+type Image struct {
+	ContentID   string
+	ContentType string
+	Data        []byte
+}
+
+// Message with HTML body
+type Message struct {
+	Subject string
+	HTML    string
+	Images  []Image
+}
+
+// Sender of messages. STARTTLS is used if supported by server, authentication is used if Username is set,
+// net/smtp allows it only over TLS or to localhost
+type Sender struct {
+	// Addr of SMTP server, host:port
+	Addr     string
+	Username string
+	Password string
+	From     string
+	To       []string
+
+	// sendMail is used instead of smtp.SendMail if set
+	sendMail func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
+	// now is used instead of time.Now if set
+	now func() time.Time
+}
+
+// Send sends message to all recipients
+func (s Sender) Send(m Message) error {
+	if len(s.To) == 0 {
+		return fmt.Errorf("no recipients")
+	}
+	var auth smtp.Auth
+	if s.Username != "" {
+		host, _, err := net.SplitHostPort(s.Addr)
+		if err != nil {
+			return err
+		}
+		auth = smtp.PlainAuth("", s.Username, s.Password, host)
+	}
+	now := time.Now
+	if s.now != nil {
+		now = s.now
+	}
+	msg, err := m.Bytes(s.From, s.To, now())
+	if err != nil {
+		return err
+	}
+	send := smtp.SendMail
+	if s.sendMail != nil {
+		send = s.sendMail
+	}
+	return send(s.Addr, auth, s.From, s.To, msg)
+}
+
+// Bytes returns message in MIME format, HTML and images are sent as multipart/related parts
+func (m Message) Bytes(from string, to []string, date time.Time) ([]byte, error) {
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	fmt.Fprintf(&buf, "From: %s\r\n", from)
+	fmt.Fprintf(&buf, "To: %s\r\n", strings.Join(to, ", "))
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", m.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", date.Format(time.RFC1123Z))
+	fmt.Fprintf(&buf, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&buf, "Content-Type: multipart/related; boundary=%s\r\n\r\n", w.Boundary())
+
+	part, err := w.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {"text/html; charset=utf-8"},
+		"Content-Transfer-Encoding": {"quoted-printable"},
+	})
+	if err != nil {
+		return nil, err
+	}
+	qp := quotedprintable.NewWriter(part)
+	if _, err := qp.Write([]byte(m.HTML)); err != nil {
+		return nil, err
+	}
+	if err := qp.Close(); err != nil {
+		return nil, err
+	}
+
+	for _, image := range m.Images {
+		part, err := w.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {image.ContentType},
+			"Content-Transfer-Encoding": {"base64"},
+			"Content-ID":                {"<" + image.ContentID + ">"},
+			"Content-Disposition":       {"inline"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		encoded := base64.StdEncoding.EncodeToString(image.Data)
+		for len(encoded) > 76 {
+			if _, err := fmt.Fprintf(part, "%s\r\n", encoded[:76]); err != nil {
+				return nil, err
+			}
+			encoded = encoded[76:]
+		}
+		if _, err := fmt.Fprintf(part, "%s\r\n", encoded); err != nil {
+			return nil, err
+		}
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
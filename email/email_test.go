@@ -0,0 +1,88 @@
+package email
+
+import (
+	"bytes"
+	"encoding/base64"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"net/smtp"
+	"testing"
+	"time"
+)
+
+var date = time.Date(2020, 11, 18, 20, 0, 0, 0, time.UTC)
+
+func TestBytes(t *testing.T) {
+	image := bytes.Repeat([]byte{0x89, 'P', 'N', 'G'}, 50)
+	b, err := Message{
+		Subject: "Jakość powietrza",
+		HTML:    `<p>PM2.5: 18.7</p><img src="cid:chart">`,
+		Images:  []Image{{ContentID: "chart", ContentType: "image/png", Data: image}},
+	}.Bytes("airly@example.com", []string{"a@example.com", "b@example.com"}, date)
+	assert.Nil(t, err)
+
+	msg, err := mail.ReadMessage(bytes.NewReader(b))
+	assert.Nil(t, err)
+	assert.Equal(t, "airly@example.com", msg.Header.Get("From"))
+	assert.Equal(t, "a@example.com, b@example.com", msg.Header.Get("To"))
+	subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	assert.Nil(t, err)
+	assert.Equal(t, "Jakość powietrza", subject)
+	assert.Equal(t, "Wed, 18 Nov 2020 20:00:00 +0000", msg.Header.Get("Date"))
+
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	assert.Nil(t, err)
+	assert.Equal(t, "multipart/related", mediaType)
+	r := multipart.NewReader(msg.Body, params["boundary"])
+
+	part, err := r.NextPart()
+	assert.Nil(t, err)
+	assert.Equal(t, "text/html; charset=utf-8", part.Header.Get("Content-Type"))
+	html, _ := ioutil.ReadAll(part)
+	assert.Equal(t, `<p>PM2.5: 18.7</p><img src="cid:chart">`, string(html))
+
+	part, err = r.NextPart()
+	assert.Nil(t, err)
+	assert.Equal(t, "<chart>", part.Header.Get("Content-Id"))
+	assert.Equal(t, "image/png", part.Header.Get("Content-Type"))
+	data, err := ioutil.ReadAll(base64.NewDecoder(base64.StdEncoding, part))
+	assert.Nil(t, err)
+	assert.Equal(t, image, data)
+
+	_, err = r.NextPart()
+	assert.NotNil(t, err)
+}
+
+func TestSend(t *testing.T) {
+	var sent []byte
+	var auth smtp.Auth
+	s := Sender{
+		Addr:     "smtp.example.com:587",
+		Username: "user",
+		Password: "secret",
+		From:     "airly@example.com",
+		To:       []string{"a@example.com"},
+		sendMail: func(addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+			assert.Equal(t, "smtp.example.com:587", addr)
+			assert.Equal(t, "airly@example.com", from)
+			assert.Equal(t, []string{"a@example.com"}, to)
+			auth, sent = a, msg
+			return nil
+		},
+		now: func() time.Time { return date },
+	}
+	assert.Nil(t, s.Send(Message{Subject: "Daily report", HTML: "<p>ok</p>"}))
+	assert.NotNil(t, auth)
+	expected, _ := Message{Subject: "Daily report", HTML: "<p>ok</p>"}.Bytes(s.From, s.To, date)
+	assert.Equal(t, len(expected), len(sent))
+
+	s.Username = ""
+	assert.Nil(t, s.Send(Message{}))
+	assert.Nil(t, auth)
+
+	s.To = nil
+	assert.EqualError(t, s.Send(Message{}), "no recipients")
+}
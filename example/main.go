@@ -23,6 +23,11 @@ func main() {
 	password := flag.String("password", "", "Elasticsearch password")
 	flag.Parse()
 
+	lang, err := airly.ParseLanguage(*language)
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	cfg := elasticsearch.Config{
 		CloudID:  *cloudId,
 		Username: *user,
@@ -36,7 +41,7 @@ func main() {
 
 	air := airly.Client{
 		Key:      *key,
-		Language: *language,
+		Language: string(lang),
 	}
 
 	for {
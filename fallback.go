@@ -0,0 +1,76 @@
+package airly
+
+import (
+	"errors"
+	"net/http"
+)
+
+// NamedProvider is AirQualityProvider with name used to annotate data
+type NamedProvider struct {
+	Name     string
+	Provider AirQualityProvider
+}
+
+// FallbackProvider queries providers in order and returns the first result with data, with Source set to
+// provider's name. Next provider is tried when previous one returns an error, no installations or measurements
+// without values (e.g. there is no installation within range). If no provider has data, the last error
+// (or ErrNoInstallation) is returned
+type FallbackProvider []NamedProvider
+
+var _ AirQualityProvider = FallbackProvider{}
+
+// NearestInstallations returns installations near specified point from the first provider that has any
+func (f FallbackProvider) NearestInstallations(loc Location, options ...NearestInstallationsOption) ([]Installation, error) {
+	err := ErrNoInstallation
+	for _, p := range f {
+		installations, e := p.Provider.NearestInstallations(loc, options...)
+		if e != nil {
+			err = e
+			continue
+		}
+		if len(installations) == 0 {
+			continue
+		}
+		for i := range installations {
+			installations[i].Source = p.Name
+		}
+		return installations, nil
+	}
+	return nil, err
+}
+
+// NearestMeasurements returns measurements for an installation closest to a given location from the first provider
+// that has an installation in range
+func (f FallbackProvider) NearestMeasurements(loc Location, options ...NearestMeasurementsOption) (Measurements, error) {
+	return f.measurements(func(p AirQualityProvider) (Measurements, error) {
+		return p.NearestMeasurements(loc, options...)
+	})
+}
+
+// PointMeasurements returns measurements for a given location from the first provider that has data for it
+func (f FallbackProvider) PointMeasurements(loc Location, options ...MeasurementsOption) (Measurements, error) {
+	return f.measurements(func(p AirQualityProvider) (Measurements, error) {
+		return p.PointMeasurements(loc, options...)
+	})
+}
+
+func (f FallbackProvider) measurements(get func(p AirQualityProvider) (Measurements, error)) (Measurements, error) {
+	err := ErrNoInstallation
+	for _, p := range f {
+		m, e := get(p.Provider)
+		var apiErr *APIError
+		if errors.As(e, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+			continue
+		}
+		if e != nil {
+			err = e
+			continue
+		}
+		if len(m.Current.Values) == 0 {
+			continue
+		}
+		m.Source = p.Name
+		return m, nil
+	}
+	return Measurements{}, err
+}
@@ -0,0 +1,66 @@
+package airly
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type mockProvider struct {
+	installations []Installation
+	measurements  Measurements
+	err           error
+}
+
+func (m mockProvider) NearestInstallations(Location, ...NearestInstallationsOption) ([]Installation, error) {
+	return m.installations, m.err
+}
+
+func (m mockProvider) NearestMeasurements(Location, ...NearestMeasurementsOption) (Measurements, error) {
+	return m.measurements, m.err
+}
+
+func (m mockProvider) PointMeasurements(Location, ...MeasurementsOption) (Measurements, error) {
+	return m.measurements, m.err
+}
+
+func TestFallbackProvider(t *testing.T) {
+	withData := Measurements{Current: Measurement{Values: []Value{{Name: "PM25", Value: 18.7}}}}
+	f := FallbackProvider{
+		{"airly", mockProvider{err: errors.New("error")}},
+		{"empty", mockProvider{installations: []Installation{}}},
+		{"missing", mockProvider{err: &APIError{StatusCode: 404, Body: "not found"}}},
+		{"gios", mockProvider{installations: []Installation{{Id: 400}}, measurements: withData}},
+		{"other", mockProvider{installations: []Installation{{Id: 1}}, measurements: withData}},
+	}
+	loc := Location{50.062006, 19.940984}
+
+	installations, err := f.NearestInstallations(loc)
+	assert.Nil(t, err)
+	assert.Equal(t, []Installation{{Id: 400, Source: "gios"}}, installations)
+
+	m, err := f.NearestMeasurements(loc)
+	assert.Nil(t, err)
+	assert.Equal(t, "gios", m.Source)
+	assert.Equal(t, withData.Current, m.Current)
+
+	m, err = f.PointMeasurements(loc)
+	assert.Nil(t, err)
+	assert.Equal(t, "gios", m.Source)
+}
+
+func TestFallbackProviderNoData(t *testing.T) {
+	err := errors.New("error")
+	f := FallbackProvider{
+		{"airly", mockProvider{err: err}},
+		{"gios", mockProvider{}},
+	}
+	loc := Location{50.062006, 19.940984}
+	_, err2 := f.NearestInstallations(loc)
+	assert.Equal(t, err, err2)
+	_, err2 = f.NearestMeasurements(loc)
+	assert.Equal(t, err, err2)
+
+	_, err2 = FallbackProvider{{"gios", mockProvider{}}}.PointMeasurements(loc)
+	assert.Equal(t, ErrNoInstallation, err2)
+}
@@ -0,0 +1,190 @@
+package airly
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ForecastAccuracy shows how accurate forecasts made given time ahead were
+type ForecastAccuracy struct {
+	Horizon time.Duration `json:"horizon"`
+	// Count of forecasts compared with actual values
+	Count int `json:"count"`
+	// MAE is mean absolute error
+	MAE float64 `json:"mae"`
+	// Bias is mean error, positive values mean forecasts were too high
+	Bias float64 `json:"bias"`
+}
+
+// ForecastTracker keeps forecasts from fetched measurements in memory and, once actual values for forecasted
+// windows arrive in later measurements, computes errors per installation and forecast horizon. Forecast made for
+// the window starting at the time of current measurement has 1 hour horizon. ForecastTracker is safe for concurrent use
+type ForecastTracker struct {
+	// Name of tracked value or index, PM25 is used if empty
+	Name string
+
+	mu        sync.Mutex
+	forecasts map[int]map[time.Time]map[int]float64
+	stats     map[int]map[int]*forecastStats
+}
+
+type forecastStats struct {
+	count             int
+	sumAbs, sumErrors float64
+}
+
+// Add records forecasts from measurements of installation and compares earlier forecasts with current and
+// historical values
+func (t *ForecastTracker) Add(installationId int, m Measurements) {
+	name := t.Name
+	if name == "" {
+		name = PM25
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.forecasts == nil {
+		t.forecasts = map[int]map[time.Time]map[int]float64{}
+		t.stats = map[int]map[int]*forecastStats{}
+	}
+	forecasts := t.forecasts[installationId]
+	if forecasts == nil {
+		forecasts = map[time.Time]map[int]float64{}
+		t.forecasts[installationId] = forecasts
+	}
+	stats := t.stats[installationId]
+	if stats == nil {
+		stats = map[int]*forecastStats{}
+		t.stats[installationId] = stats
+	}
+
+	for _, actual := range append(append([]Measurement{}, m.History...), m.Current) {
+		window := hourKey(actual.FromDateTime)
+		v, ok := lookupValue(actual, name)
+		if !ok || forecasts[window] == nil {
+			continue
+		}
+		for horizon, forecast := range forecasts[window] {
+			s := stats[horizon]
+			if s == nil {
+				s = &forecastStats{}
+				stats[horizon] = s
+			}
+			s.count++
+			s.sumErrors += forecast - v
+			s.sumAbs += math.Abs(forecast - v)
+		}
+		delete(forecasts, window)
+	}
+
+	issued := m.Current.TillDateTime
+	for _, f := range m.Forecast {
+		v, ok := lookupValue(f, name)
+		if !ok {
+			continue
+		}
+		window := hourKey(f.FromDateTime)
+		horizon := int(math.Round(f.TillDateTime.Sub(issued).Hours()))
+		if forecasts[window] == nil {
+			forecasts[window] = map[int]float64{}
+		}
+		forecasts[window][horizon] = v
+	}
+
+	// forecasts that never got actual values, e.g. because of sensor downtime, are dropped after 2 days
+	for window := range forecasts {
+		if issued.Sub(window) > 48*time.Hour {
+			delete(forecasts, window)
+		}
+	}
+}
+
+// Accuracy returns forecast accuracy for installation sorted by horizon, only horizons with at least one
+// forecast compared with actual value are returned
+func (t *ForecastTracker) Accuracy(installationId int) []ForecastAccuracy {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var result []ForecastAccuracy
+	for horizon, s := range t.stats[installationId] {
+		result = append(result, ForecastAccuracy{
+			Horizon: time.Duration(horizon) * time.Hour,
+			Count:   s.count,
+			MAE:     s.sumAbs / float64(s.count),
+			Bias:    s.sumErrors / float64(s.count),
+		})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Horizon < result[j].Horizon
+	})
+	return result
+}
+
+// lookupValue returns value or index with given name from measurement
+func lookupValue(m Measurement, name string) (float64, bool) {
+	if v, ok := m.Value(name); ok {
+		return v, true
+	}
+	for _, i := range m.Indexes {
+		if i.Name == name {
+			return i.Value, true
+		}
+	}
+	return 0, false
+}
+
+// ForecastComparison pairs forecasted and actual measurement for the same window
+type ForecastComparison struct {
+	FromDateTime time.Time   `json:"fromDateTime"`
+	TillDateTime time.Time   `json:"tillDateTime"`
+	Forecast     Measurement `json:"forecast"`
+	Actual       Measurement `json:"actual"`
+	// Deltas are actual minus forecasted values for values and indexes present in both measurements
+	Deltas map[string]float64 `json:"deltas"`
+}
+
+// hourKey returns start of hour of t comparable with == regardless of location and monotonic clock reading
+func hourKey(t time.Time) time.Time {
+	return t.UTC().Round(0).Truncate(time.Hour)
+}
+
+// CompareForecast aligns forecast from earlier measurements with current and historical measurements fetched later.
+// Windows are matched by full hour of their start, comparisons are sorted by window
+func CompareForecast(earlier, later Measurements) []ForecastComparison {
+	actuals := map[time.Time]Measurement{}
+	for _, m := range append(append([]Measurement{}, later.History...), later.Current) {
+		actuals[hourKey(m.FromDateTime)] = m
+	}
+	var result []ForecastComparison
+	for _, forecast := range earlier.Forecast {
+		actual, ok := actuals[hourKey(forecast.FromDateTime)]
+		if !ok {
+			continue
+		}
+		deltas := map[string]float64{}
+		for _, v := range forecast.Values {
+			if v.Null {
+				continue
+			}
+			if a, ok := lookupValue(actual, v.Name); ok {
+				deltas[v.Name] = a - v.Value
+			}
+		}
+		for _, i := range forecast.Indexes {
+			if a, ok := lookupValue(actual, i.Name); ok {
+				deltas[i.Name] = a - i.Value
+			}
+		}
+		result = append(result, ForecastComparison{
+			FromDateTime: forecast.FromDateTime,
+			TillDateTime: forecast.TillDateTime,
+			Forecast:     forecast,
+			Actual:       actual,
+			Deltas:       deltas,
+		})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].FromDateTime.Before(result[j].FromDateTime)
+	})
+	return result
+}
@@ -0,0 +1,129 @@
+package airly
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func window(from time.Time, name string, v float64) Measurement {
+	return Measurement{
+		FromDateTime: from,
+		TillDateTime: from.Add(time.Hour),
+		Values:       []Value{{Name: name, Value: v}},
+	}
+}
+
+func TestForecastTracker(t *testing.T) {
+	h := func(hour int) time.Time {
+		return time.Date(2021, 10, 1, hour, 0, 0, 0, time.UTC)
+	}
+	tracker := ForecastTracker{}
+	tracker.Add(204, Measurements{
+		Current:  window(h(10), PM25, 10),
+		Forecast: []Measurement{window(h(11), PM25, 20), window(h(12), PM25, 30), window(h(13), "PM10", 30)},
+	})
+	assert.Empty(t, tracker.Accuracy(204))
+
+	tracker.Add(204, Measurements{
+		History:  []Measurement{window(h(10), PM25, 10)},
+		Current:  window(h(11), PM25, 16),
+		Forecast: []Measurement{window(h(12), PM25, 25)},
+	})
+	assert.Equal(t, []ForecastAccuracy{{Horizon: time.Hour, Count: 1, MAE: 4, Bias: 4}}, tracker.Accuracy(204))
+
+	tracker.Add(204, Measurements{
+		History: []Measurement{window(h(11), PM25, 16)},
+		Current: window(h(12), PM25, 30),
+	})
+	assert.Equal(t, []ForecastAccuracy{
+		{Horizon: time.Hour, Count: 2, MAE: 4.5, Bias: -0.5},
+		{Horizon: 2 * time.Hour, Count: 1, MAE: 0, Bias: 0},
+	}, tracker.Accuracy(204))
+	assert.Empty(t, tracker.Accuracy(8077))
+	assert.Empty(t, tracker.forecasts[204])
+}
+
+func TestForecastTrackerExpiry(t *testing.T) {
+	h := func(hour int) time.Time {
+		return time.Date(2021, 10, 1, hour, 0, 0, 0, time.UTC)
+	}
+	tracker := ForecastTracker{Name: "AIRLY_CAQI"}
+	tracker.Add(204, Measurements{
+		Current: window(h(10), PM25, 10),
+		Forecast: []Measurement{{
+			FromDateTime: h(11),
+			TillDateTime: h(12),
+			Indexes:      []Index{{Name: "AIRLY_CAQI", Value: 30}},
+		}},
+	})
+	assert.Len(t, tracker.forecasts[204], 1)
+	tracker.Add(204, Measurements{Current: window(h(10).Add(72*time.Hour), PM25, 10)})
+	assert.Empty(t, tracker.forecasts[204])
+}
+
+func TestCompareForecast(t *testing.T) {
+	h := func(hour int) time.Time {
+		return time.Date(2021, 10, 1, hour, 0, 0, 0, time.UTC)
+	}
+	earlier := Measurements{
+		Current: window(h(10), PM25, 10),
+		Forecast: []Measurement{
+			window(h(12), PM25, 30),
+			{
+				FromDateTime: h(11),
+				TillDateTime: h(12),
+				Values:       []Value{{Name: PM25, Value: 20}, {Name: PM10, Value: 30}},
+				Indexes:      []Index{{Name: "AIRLY_CAQI", Value: 30, Level: "LOW"}},
+			},
+			window(h(14), PM25, 30),
+		},
+	}
+	actual11 := Measurement{
+		FromDateTime: h(11),
+		TillDateTime: h(12),
+		Values:       []Value{{Name: PM25, Value: 50}},
+		Indexes:      []Index{{Name: "AIRLY_CAQI", Value: 80, Level: "HIGH"}},
+	}
+	actual12 := window(h(12).Add(15*time.Minute), PM25, 25)
+	later := Measurements{
+		History: []Measurement{window(h(10), PM25, 10), actual11},
+		Current: actual12,
+	}
+	comparisons := CompareForecast(earlier, later)
+	assert.Equal(t, []ForecastComparison{{
+		FromDateTime: h(11),
+		TillDateTime: h(12),
+		Forecast:     earlier.Forecast[1],
+		Actual:       actual11,
+		Deltas:       map[string]float64{PM25: 30, "AIRLY_CAQI": 50},
+	}, {
+		FromDateTime: h(12),
+		TillDateTime: h(13),
+		Forecast:     earlier.Forecast[0],
+		Actual:       actual12,
+		Deltas:       map[string]float64{PM25: -5},
+	}}, comparisons)
+	assert.Equal(t, "LOW", comparisons[0].Forecast.Indexes[0].Level)
+	assert.Equal(t, "HIGH", comparisons[0].Actual.Indexes[0].Level)
+}
+
+func TestForecastTimeZones(t *testing.T) {
+	warsaw := time.FixedZone("CEST", 2*60*60)
+	h := func(hour int) time.Time {
+		return time.Date(2021, 10, 1, hour, 0, 0, 0, time.UTC)
+	}
+	// the same windows decoded with different offset
+	local := func(hour int) time.Time {
+		return h(hour).In(warsaw)
+	}
+
+	tracker := ForecastTracker{}
+	tracker.Add(204, Measurements{Current: window(h(10), PM25, 10), Forecast: []Measurement{window(h(11), PM25, 20)}})
+	tracker.Add(204, Measurements{Current: window(local(11), PM25, 16)})
+	assert.Equal(t, []ForecastAccuracy{{Horizon: time.Hour, Count: 1, MAE: 4, Bias: 4}}, tracker.Accuracy(204))
+
+	comparisons := CompareForecast(Measurements{Forecast: []Measurement{window(h(11), PM25, 20)}},
+		Measurements{Current: window(local(11), PM25, 16)})
+	assert.Len(t, comparisons, 1)
+}
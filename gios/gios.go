@@ -0,0 +1,222 @@
+// Package gios provides airly.AirQualityProvider backed by GIOŚ (Chief Inspectorate of Environmental Protection)
+// API of Polish national air quality monitoring network, see https://powietrze.gios.gov.pl/pjp/content/api
+package gios
+
+import (
+	"fmt"
+	"github.com/probakowski/go-airly"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// DefaultBaseURL of GIOŚ API
+const DefaultBaseURL = "https://api.gios.gov.pl/pjp-api/rest/"
+
+// IndexName is name of the Polish air quality index reported by GIOŚ
+const IndexName = "PIJP"
+
+// Provider of GIOŚ data, installations are GIOŚ stations. GIOŚ doesn't interpolate values, so PointMeasurements
+// returns measurements of the nearest station
+type Provider struct {
+	// BaseURL of the API, DefaultBaseURL is used if empty
+	BaseURL string
+	// MaxResponseSize in bytes, airly.DefaultMaxResponseSize is used if not set, negative value means no limit
+	MaxResponseSize int64
+	// HttpClient to use for requests, http.Client with airly.DefaultTimeout is used if nil
+	HttpClient airly.HttpClient
+}
+
+var _ airly.AirQualityProvider = Provider{}
+
+type station struct {
+	Id            int    `json:"id"`
+	StationName   string `json:"stationName"`
+	GegrLat       string `json:"gegrLat"`
+	GegrLon       string `json:"gegrLon"`
+	AddressStreet string `json:"addressStreet"`
+	City          struct {
+		Name string `json:"name"`
+	} `json:"city"`
+}
+
+type sensor struct {
+	Id    int `json:"id"`
+	Param struct {
+		ParamCode string `json:"paramCode"`
+	} `json:"param"`
+}
+
+type data struct {
+	Key    string `json:"key"`
+	Values []struct {
+		Date  string   `json:"date"`
+		Value *float64 `json:"value"`
+	} `json:"values"`
+}
+
+type index struct {
+	StIndexLevel *struct {
+		Id             int    `json:"id"`
+		IndexLevelName string `json:"indexLevelName"`
+	} `json:"stIndexLevel"`
+}
+
+// levels maps GIOŚ index level IDs to level names used by Airly
+var levels = []string{"VERY_LOW", "LOW", "MEDIUM", "HIGH", "VERY_HIGH", "EXTREME"}
+
+// params maps GIOŚ parameter codes to value names used by Airly
+var params = map[string]string{
+	"PM10":  "PM10",
+	"PM2.5": "PM25",
+	"NO2":   "NO2",
+	"O3":    "O3",
+	"SO2":   "SO2",
+	"CO":    "CO",
+	"C6H6":  "C6H6",
+}
+
+func (p Provider) get(path string, v interface{}) error {
+	base := p.BaseURL
+	if base == "" {
+		base = DefaultBaseURL
+	}
+	return airly.GetJSON(p.HttpClient, base+path, p.MaxResponseSize, v)
+}
+
+// NearestInstallations returns stations sorted by distance to loc, range can be defined with airly.MaxDistance,
+// number of results with airly.MaxResults
+func (p Provider) NearestInstallations(loc airly.Location, options ...airly.NearestInstallationsOption) ([]airly.Installation, error) {
+	o := airly.ResolveOptions(options...)
+	var stations []station
+	if err := p.get("station/findAll", &stations); err != nil {
+		return nil, err
+	}
+	installations := []airly.Installation{}
+	for _, s := range stations {
+		i := s.installation()
+		if i.Location.Distance(loc) <= o.MaxDistance {
+			installations = append(installations, i)
+		}
+	}
+	sort.SliceStable(installations, func(i, j int) bool {
+		return installations[i].Location.Distance(loc) < installations[j].Location.Distance(loc)
+	})
+	if o.MaxResults >= 0 && len(installations) > o.MaxResults {
+		installations = installations[:o.MaxResults]
+	}
+	return installations, nil
+}
+
+func (s station) installation() airly.Installation {
+	lat, _ := strconv.ParseFloat(s.GegrLat, 64)
+	lng, _ := strconv.ParseFloat(s.GegrLon, 64)
+	return airly.Installation{
+		Id:       s.Id,
+		Location: airly.Location{Latitude: lat, Longitude: lng},
+		Address: airly.Address{
+			Country:         "Poland",
+			City:            s.City.Name,
+			Street:          s.AddressStreet,
+			DisplayAddress1: s.City.Name,
+			DisplayAddress2: s.AddressStreet,
+		},
+	}
+}
+
+// NearestMeasurements returns measurements from the nearest station, range can be defined with airly.MaxDistance.
+// airly.ErrNoInstallation is returned if there is no station in range
+func (p Provider) NearestMeasurements(loc airly.Location, options ...airly.NearestMeasurementsOption) (airly.Measurements, error) {
+	o := airly.ResolveNearestMeasurementsOptions(options...)
+	installations, err := p.NearestInstallations(loc, airly.MaxDistance(o.MaxDistance), airly.MaxResults(1))
+	if err != nil {
+		return airly.Measurements{}, err
+	}
+	if len(installations) == 0 {
+		return airly.Measurements{}, airly.ErrNoInstallation
+	}
+	return p.InstallationMeasurements(installations[0].Id)
+}
+
+// PointMeasurements returns measurements from the nearest station within default range, see NearestMeasurements
+func (p Provider) PointMeasurements(loc airly.Location, _ ...airly.MeasurementsOption) (airly.Measurements, error) {
+	return p.NearestMeasurements(loc)
+}
+
+// InstallationMeasurements returns measurements of given station. The latest hour with any value is returned as
+// current measurement, earlier hours as history. GIOŚ doesn't provide forecasts
+func (p Provider) InstallationMeasurements(stationId int) (airly.Measurements, error) {
+	var sensors []sensor
+	if err := p.get(fmt.Sprintf("station/sensors/%d", stationId), &sensors); err != nil {
+		return airly.Measurements{}, err
+	}
+	series := map[time.Time][]airly.Value{}
+	for _, s := range sensors {
+		name, ok := params[s.Param.ParamCode]
+		if !ok {
+			continue
+		}
+		var d data
+		if err := p.get(fmt.Sprintf("data/getData/%d", s.Id), &d); err != nil {
+			return airly.Measurements{}, err
+		}
+		for _, v := range d.Values {
+			if v.Value == nil {
+				continue
+			}
+			t, err := time.ParseInLocation("2006-01-02 15:04:05", v.Date, warsaw)
+			if err != nil {
+				return airly.Measurements{}, err
+			}
+			series[t] = append(series[t], airly.Value{Name: name, Value: *v.Value})
+		}
+	}
+
+	var times []time.Time
+	for t := range series {
+		times = append(times, t)
+	}
+	sort.Slice(times, func(i, j int) bool {
+		return times[i].Before(times[j])
+	})
+	m := airly.Measurements{History: []airly.Measurement{}, Forecast: []airly.Measurement{}}
+	for i, t := range times {
+		values := series[t]
+		sort.Slice(values, func(i, j int) bool {
+			return values[i].Name < values[j].Name
+		})
+		measurement := airly.Measurement{
+			FromDateTime: t.Add(-time.Hour).UTC(),
+			TillDateTime: t.UTC(),
+			Values:       values,
+		}
+		if i == len(times)-1 {
+			m.Current = measurement
+		} else {
+			m.History = append(m.History, measurement)
+		}
+	}
+
+	var idx index
+	if err := p.get(fmt.Sprintf("aqindex/getIndex/%d", stationId), &idx); err != nil {
+		return airly.Measurements{}, err
+	}
+	if l := idx.StIndexLevel; l != nil && l.Id >= 0 && l.Id < len(levels) {
+		m.Current.Indexes = []airly.Index{{
+			Name:        IndexName,
+			Value:       float64(l.Id),
+			Level:       levels[l.Id],
+			Description: l.IndexLevelName,
+		}}
+	}
+	return m, nil
+}
+
+// warsaw is time zone of dates returned by GIOŚ API
+var warsaw = func() *time.Location {
+	l, err := time.LoadLocation("Europe/Warsaw")
+	if err != nil {
+		return time.FixedZone("CET", 3600)
+	}
+	return l
+}()
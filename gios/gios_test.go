@@ -0,0 +1,149 @@
+package gios
+
+import (
+	"errors"
+	"github.com/probakowski/go-airly"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+type mockClient struct {
+	DoFunc func(req *http.Request) (*http.Response, error)
+}
+
+func (m mockClient) Do(req *http.Request) (*http.Response, error) {
+	return m.DoFunc(req)
+}
+
+var responses = map[string]string{
+	"/pjp-api/rest/station/findAll": `[{
+		"id": 400,
+		"stationName": "Kraków, Aleja Krasińskiego",
+		"gegrLat": "50.057678",
+		"gegrLon": "19.926189",
+		"city": {"id": 415, "name": "Kraków"},
+		"addressStreet": "al. Krasińskiego"
+	}, {
+		"id": 10121,
+		"stationName": "Kraków, ul. Dietla",
+		"gegrLat": "50.057447",
+		"gegrLon": "19.946008",
+		"city": {"id": 415, "name": "Kraków"},
+		"addressStreet": "ul. Dietla"
+	}, {
+		"id": 530,
+		"stationName": "Warszawa-Komunikacyjna",
+		"gegrLat": "52.219298",
+		"gegrLon": "21.004724",
+		"city": {"id": 1006, "name": "Warszawa"},
+		"addressStreet": "al. Niepodległości 227/233"
+	}]`,
+	"/pjp-api/rest/station/sensors/10121": `[
+		{"id": 16784, "stationId": 10121, "param": {"paramName": "pył zawieszony PM10", "paramFormula": "PM10", "paramCode": "PM10", "idParam": 3}},
+		{"id": 16785, "stationId": 10121, "param": {"paramName": "pył zawieszony PM2.5", "paramFormula": "PM2.5", "paramCode": "PM2.5", "idParam": 69}},
+		{"id": 16786, "stationId": 10121, "param": {"paramName": "tlenek węgla", "paramFormula": "XX", "paramCode": "XX", "idParam": 8}}
+	]`,
+	"/pjp-api/rest/data/getData/16784": `{"key": "PM10", "values": [
+		{"date": "2021-10-01 12:00:00", "value": null},
+		{"date": "2021-10-01 11:00:00", "value": 30.5},
+		{"date": "2021-10-01 10:00:00", "value": 28.1}
+	]}`,
+	"/pjp-api/rest/data/getData/16785": `{"key": "PM2.5", "values": [
+		{"date": "2021-10-01 12:00:00", "value": 20.2},
+		{"date": "2021-10-01 11:00:00", "value": 19.8}
+	]}`,
+	"/pjp-api/rest/aqindex/getIndex/10121": `{"id": 10121, "stIndexLevel": {"id": 1, "indexLevelName": "Dobry"}}`,
+}
+
+func provider() Provider {
+	return Provider{HttpClient: mockClient{func(req *http.Request) (*http.Response, error) {
+		body, ok := responses[req.URL.Path]
+		if !ok {
+			return &http.Response{StatusCode: 404, Body: io.NopCloser(strings.NewReader("not found"))}, nil
+		}
+		return &http.Response{StatusCode: 200, Body: io.NopCloser(strings.NewReader(body))}, nil
+	}}}
+}
+
+func TestNearestInstallations(t *testing.T) {
+	installations, err := provider().NearestInstallations(airly.Location{Latitude: 50.062006, Longitude: 19.940984},
+		airly.MaxDistance(5), airly.MaxResults(-1))
+	assert.Nil(t, err)
+	assert.Equal(t, []airly.Installation{{
+		Id:       10121,
+		Location: airly.Location{Latitude: 50.057447, Longitude: 19.946008},
+		Address: airly.Address{
+			Country:         "Poland",
+			City:            "Kraków",
+			Street:          "ul. Dietla",
+			DisplayAddress1: "Kraków",
+			DisplayAddress2: "ul. Dietla",
+		},
+	}, {
+		Id:       400,
+		Location: airly.Location{Latitude: 50.057678, Longitude: 19.926189},
+		Address: airly.Address{
+			Country:         "Poland",
+			City:            "Kraków",
+			Street:          "al. Krasińskiego",
+			DisplayAddress1: "Kraków",
+			DisplayAddress2: "al. Krasińskiego",
+		},
+	}}, installations)
+
+	installations, err = provider().NearestInstallations(airly.Location{Latitude: 50.062006, Longitude: 19.940984})
+	assert.Nil(t, err)
+	assert.Len(t, installations, 1)
+}
+
+func TestNearestMeasurements(t *testing.T) {
+	m, err := provider().NearestMeasurements(airly.Location{Latitude: 50.062006, Longitude: 19.940984})
+	assert.Nil(t, err)
+	hour := func(h int) time.Time {
+		return time.Date(2021, 10, 1, h, 0, 0, 0, warsaw).UTC()
+	}
+	assert.Equal(t, airly.Measurements{
+		Current: airly.Measurement{
+			FromDateTime: hour(11),
+			TillDateTime: hour(12),
+			Values:       []airly.Value{{Name: "PM25", Value: 20.2}},
+			Indexes:      []airly.Index{{Name: "PIJP", Value: 1, Level: "LOW", Description: "Dobry"}},
+		},
+		History: []airly.Measurement{{
+			FromDateTime: hour(9),
+			TillDateTime: hour(10),
+			Values:       []airly.Value{{Name: "PM10", Value: 28.1}},
+		}, {
+			FromDateTime: hour(10),
+			TillDateTime: hour(11),
+			Values:       []airly.Value{{Name: "PM10", Value: 30.5}, {Name: "PM25", Value: 19.8}},
+		}},
+		Forecast: []airly.Measurement{},
+	}, m)
+}
+
+func TestNearestMeasurementsNoStation(t *testing.T) {
+	_, err := provider().PointMeasurements(airly.Location{Latitude: 54.35, Longitude: 18.64})
+	assert.Equal(t, airly.ErrNoInstallation, err)
+}
+
+func TestError(t *testing.T) {
+	err := errors.New("error")
+	p := Provider{HttpClient: mockClient{func(req *http.Request) (*http.Response, error) {
+		return nil, err
+	}}}
+	_, err2 := p.InstallationMeasurements(10121)
+	assert.Equal(t, err, err2)
+
+	_, err2 = provider().InstallationMeasurements(1)
+	assert.Equal(t, &airly.APIError{StatusCode: 404, Body: "not found"}, err2)
+
+	p = provider()
+	p.MaxResponseSize = 10
+	_, err2 = p.InstallationMeasurements(10121)
+	assert.Equal(t, &airly.ResponseTooLargeError{Limit: 10}, err2)
+}
@@ -0,0 +1,107 @@
+package airly
+
+import (
+	"sort"
+	"time"
+)
+
+// AirPreferences describe when user wants to be outside, see FindGoodAirWindows
+type AirPreferences struct {
+	// Index used to assess air quality, AIRLY_CAQI is used if empty
+	Index string
+	// MaxIndex is the highest index value accepted in every hour of window
+	MaxIndex float64
+	// PreferredHours of day (0-23) in Location, windows covering more of them are ranked higher
+	PreferredHours []int
+	// MinDuration of window, shorter windows are skipped
+	MinDuration time.Duration
+	// Location used for PreferredHours, local time zone is used if nil
+	Location *time.Location
+}
+
+// GoodAirWindow is a period with index not higher than AirPreferences.MaxIndex
+type GoodAirWindow struct {
+	Window
+	// MaxIndex and AvgIndex are the highest and average index values in window
+	MaxIndex float64 `json:"maxIndex"`
+	AvgIndex float64 `json:"avgIndex"`
+	// PreferredHours is number of hours of window within AirPreferences.PreferredHours
+	PreferredHours int `json:"preferredHours"`
+}
+
+// FindGoodAirWindows scans forecast (measurements don't have to be sorted) for continuous periods with index not
+// higher than p.MaxIndex and at least p.MinDuration long. Windows are ranked by number of preferred hours they
+// cover, then by average index and start, the best one is the first
+func FindGoodAirWindows(forecast []Measurement, p AirPreferences) []GoodAirWindow {
+	name := p.Index
+	if name == "" {
+		name = "AIRLY_CAQI"
+	}
+	loc := p.Location
+	if loc == nil {
+		loc = time.Local
+	}
+	preferred := map[int]bool{}
+	for _, h := range p.PreferredHours {
+		preferred[h] = true
+	}
+
+	var windows []GoodAirWindow
+	var current *GoodAirWindow
+	var sum float64
+	var count int
+	finish := func() {
+		if current != nil && current.Duration() >= p.MinDuration {
+			current.AvgIndex = sum / float64(count)
+			windows = append(windows, *current)
+		}
+		current = nil
+	}
+	_ = EachWindow(forecast, func(w Window, m Measurement) error {
+		v, ok := indexValue(m, name)
+		if !ok || v > p.MaxIndex {
+			finish()
+			return nil
+		}
+		if current == nil || !current.End.Equal(w.Start) {
+			finish()
+			current = &GoodAirWindow{Window: Window{Start: w.Start, End: w.Start}, MaxIndex: v}
+			sum, count = 0, 0
+		}
+		for t := w.Start; t.Before(w.End); t = t.Add(time.Hour) {
+			if preferred[t.In(loc).Hour()] {
+				current.PreferredHours++
+			}
+		}
+		current.End = w.End
+		if v > current.MaxIndex {
+			current.MaxIndex = v
+		}
+		sum += v
+		count++
+		return nil
+	})
+	finish()
+
+	sort.SliceStable(windows, func(i, j int) bool {
+		a, b := windows[i], windows[j]
+		if a.PreferredHours != b.PreferredHours {
+			return a.PreferredHours > b.PreferredHours
+		}
+		if a.AvgIndex != b.AvgIndex {
+			return a.AvgIndex < b.AvgIndex
+		}
+		return a.Start.Before(b.Start)
+	})
+	return windows
+}
+
+// indexValue returns value of index with given name
+func indexValue(m Measurement, name string) (float64, bool) {
+	for _, i := range m.Indexes {
+		if i.Name == name {
+			return i.Value, true
+		}
+	}
+	return 0, false
+}
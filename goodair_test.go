@@ -0,0 +1,39 @@
+package airly
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestFindGoodAirWindows(t *testing.T) {
+	h := func(hour int) time.Time {
+		return time.Date(2021, 3, 1, hour, 0, 0, 0, time.UTC)
+	}
+	forecast := func(caqi ...float64) []Measurement {
+		var measurements []Measurement
+		for i, v := range caqi {
+			measurements = append(measurements, Measurement{FromDateTime: h(i), TillDateTime: h(i + 1),
+				Indexes: []Index{{Name: "AIRLY_CAQI", Value: v}, {Name: "PIJP", Value: 1}}})
+		}
+		return measurements
+	}
+	f := forecast(60, 20, 30, 70, 10, 40, 50, 90, 45)
+	f[0], f[5] = f[5], f[0]
+
+	assert.Equal(t, []GoodAirWindow{
+		{Window: Window{Start: h(1), End: h(3)}, MaxIndex: 30, AvgIndex: 25},
+		{Window: Window{Start: h(4), End: h(7)}, MaxIndex: 50, AvgIndex: 100.0 / 3},
+		{Window: Window{Start: h(8), End: h(9)}, MaxIndex: 45, AvgIndex: 45},
+	}, FindGoodAirWindows(f, AirPreferences{MaxIndex: 50}))
+
+	assert.Equal(t, []GoodAirWindow{
+		{Window: Window{Start: h(4), End: h(7)}, MaxIndex: 50, AvgIndex: 100.0 / 3, PreferredHours: 2},
+		{Window: Window{Start: h(1), End: h(3)}, MaxIndex: 30, AvgIndex: 25},
+	}, FindGoodAirWindows(f, AirPreferences{MaxIndex: 50, PreferredHours: []int{5, 6, 7}, MinDuration: 2 * time.Hour,
+		Location: time.UTC}))
+
+	assert.Equal(t, []GoodAirWindow{{Window: Window{Start: h(0), End: h(9)}, MaxIndex: 1, AvgIndex: 1}},
+		FindGoodAirWindows(f, AirPreferences{Index: "PIJP", MaxIndex: 1}))
+	assert.Empty(t, FindGoodAirWindows(f, AirPreferences{MaxIndex: 5}))
+}
@@ -0,0 +1,210 @@
+// Package heatmap renders slippy map (z/x/y) PNG tiles with values interpolated from measurements
+// using inverse distance weighting (IDW)
+package heatmap
+
+import (
+	"fmt"
+	"github.com/probakowski/go-airly"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Point is a value measured at location
+type Point struct {
+	Location airly.Location
+	Value    float64
+}
+
+// Stop of color scale, values from Value (inclusive) up to the next stop get Color
+type Stop struct {
+	Value float64
+	Color color.NRGBA
+}
+
+// CAQIScale is color scale of Airly CAQI levels
+var CAQIScale = []Stop{
+	{0, color.NRGBA{0x6B, 0xC9, 0x26, 0xFF}},
+	{25, color.NRGBA{0xD1, 0xCF, 0x1E, 0xFF}},
+	{50, color.NRGBA{0xEF, 0xBB, 0x0F, 0xFF}},
+	{75, color.NRGBA{0xEF, 0x71, 0x20, 0xFF}},
+	{100, color.NRGBA{0xEF, 0x2A, 0x36, 0xFF}},
+	{125, color.NRGBA{0xB0, 0x00, 0x57, 0xFF}},
+	{150, color.NRGBA{0x77, 0x00, 0x78, 0xFF}},
+}
+
+// Renderer of heatmap tiles, it's also http.Handler serving tiles under .../{z}/{x}/{y}.png paths
+type Renderer struct {
+	Points []Point
+	// Power of IDW, 2 is used if not set
+	Power float64
+	// MaxDistance in km from the nearest point, pixels further away are transparent, 2 is used if not set
+	MaxDistance float64
+	// Scale of colors, CAQIScale is used if not set
+	Scale []Stop
+	// Alpha of colored pixels, 160 is used if not set
+	Alpha uint8
+	// TileSize in pixels, 256 is used if not set
+	TileSize int
+}
+
+// PointsFromMeasurements returns points with current value or index with given name,
+// measurements are keyed by installation ID. Installations without measurements or value are skipped
+func PointsFromMeasurements(installations []airly.Installation, measurements map[int]airly.Measurements, name string) []Point {
+	var points []Point
+	for _, i := range installations {
+		m, ok := measurements[i.Id]
+		if !ok {
+			continue
+		}
+		for _, v := range m.Current.Values {
+			if v.Name == name && !v.Null {
+				points = append(points, Point{i.Location, v.Value})
+			}
+		}
+		for _, index := range m.Current.Indexes {
+			if index.Name == name {
+				points = append(points, Point{i.Location, index.Value})
+			}
+		}
+	}
+	return points
+}
+
+// Tile renders tile with given zoom and coordinates
+func (r Renderer) Tile(z, x, y int) *image.NRGBA {
+	size := r.TileSize
+	if size == 0 {
+		size = 256
+	}
+	maxDistance := r.MaxDistance
+	if maxDistance == 0 {
+		maxDistance = 2
+	}
+	img := image.NewNRGBA(image.Rect(0, 0, size, size))
+
+	// only points close enough to the tile can color it
+	topLeft := location(z, float64(x), float64(y))
+	bottomRight := location(z, float64(x+1), float64(y+1))
+	margin := maxDistance / 111 // km to degrees of latitude
+	lngMargin := margin / math.Max(math.Cos(topLeft.Latitude*math.Pi/180), 0.01)
+	var points []Point
+	for _, p := range r.Points {
+		if p.Location.Latitude <= topLeft.Latitude+margin && p.Location.Latitude >= bottomRight.Latitude-margin &&
+			p.Location.Longitude >= topLeft.Longitude-lngMargin && p.Location.Longitude <= bottomRight.Longitude+lngMargin {
+			points = append(points, p)
+		}
+	}
+	if len(points) == 0 {
+		return img
+	}
+
+	for py := 0; py < size; py++ {
+		for px := 0; px < size; px++ {
+			loc := location(z, float64(x)+(float64(px)+0.5)/float64(size), float64(y)+(float64(py)+0.5)/float64(size))
+			if v, ok := r.interpolate(loc, points, maxDistance); ok {
+				img.SetNRGBA(px, py, r.color(v))
+			}
+		}
+	}
+	return img
+}
+
+// interpolate value at location with IDW, false is returned if there is no point within maxDistance
+func (r Renderer) interpolate(loc airly.Location, points []Point, maxDistance float64) (float64, bool) {
+	power := r.Power
+	if power == 0 {
+		power = 2
+	}
+	var sum, weights float64
+	inRange := false
+	for _, p := range points {
+		d := loc.Distance(p.Location)
+		if d < 1e-6 {
+			return p.Value, true
+		}
+		if d <= maxDistance {
+			inRange = true
+		}
+		w := 1 / math.Pow(d, power)
+		sum += w * p.Value
+		weights += w
+	}
+	return sum / weights, inRange
+}
+
+func (r Renderer) color(v float64) color.NRGBA {
+	scale := r.Scale
+	if len(scale) == 0 {
+		scale = CAQIScale
+	}
+	alpha := r.Alpha
+	if alpha == 0 {
+		alpha = 160
+	}
+	c := scale[0].Color
+	for _, s := range scale {
+		if v >= s.Value {
+			c = s.Color
+		}
+	}
+	c.A = alpha
+	return c
+}
+
+// location of point given in tile coordinates (fractional part is position within tile) at zoom z
+func location(z int, x, y float64) airly.Location {
+	n := math.Exp2(float64(z))
+	return airly.Location{
+		Latitude:  math.Atan(math.Sinh(math.Pi*(1-2*y/n))) * 180 / math.Pi,
+		Longitude: x/n*360 - 180,
+	}
+}
+
+// TileOf returns coordinates of tile containing location at zoom z
+func TileOf(loc airly.Location, z int) (x, y int) {
+	n := math.Exp2(float64(z))
+	lat := loc.Latitude * math.Pi / 180
+	x = int(math.Floor((loc.Longitude + 180) / 360 * n))
+	y = int(math.Floor((1 - math.Log(math.Tan(lat)+1/math.Cos(lat))/math.Pi) / 2 * n))
+	return x, y
+}
+
+// WritePNG renders tile and writes it as PNG
+func (r Renderer) WritePNG(w io.Writer, z, x, y int) error {
+	return png.Encode(w, r.Tile(z, x, y))
+}
+
+// ServeHTTP serves tiles for paths ending with /{z}/{x}/{y}.png
+func (r Renderer) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	z, x, y, err := parseTilePath(req.URL.Path)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "image/png")
+	_ = r.WritePNG(w, z, x, y)
+}
+
+func parseTilePath(path string) (z, x, y int, err error) {
+	parts := strings.Split(strings.TrimSuffix(path, ".png"), "/")
+	if len(parts) < 3 || !strings.HasSuffix(path, ".png") {
+		return 0, 0, 0, fmt.Errorf("invalid tile path %q", path)
+	}
+	var coords [3]int
+	for i, part := range parts[len(parts)-3:] {
+		if coords[i], err = strconv.Atoi(part); err != nil || coords[i] < 0 {
+			return 0, 0, 0, fmt.Errorf("invalid tile path %q", path)
+		}
+	}
+	z, x, y = coords[0], coords[1], coords[2]
+	if z > 30 || x >= 1<<uint(z) || y >= 1<<uint(z) {
+		return 0, 0, 0, fmt.Errorf("invalid tile path %q", path)
+	}
+	return z, x, y, nil
+}
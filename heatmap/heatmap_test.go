@@ -0,0 +1,94 @@
+package heatmap
+
+import (
+	"bytes"
+	"github.com/probakowski/go-airly"
+	"github.com/stretchr/testify/assert"
+	"image/color"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+var krakow = airly.Location{Latitude: 50.062006, Longitude: 19.940984}
+
+func TestTileOf(t *testing.T) {
+	x, y := TileOf(krakow, 12)
+	assert.Equal(t, 2274, x)
+	assert.Equal(t, 1388, y)
+	topLeft := location(12, float64(x), float64(y))
+	bottomRight := location(12, float64(x+1), float64(y+1))
+	assert.True(t, topLeft.Latitude >= krakow.Latitude && krakow.Latitude >= bottomRight.Latitude)
+	assert.True(t, topLeft.Longitude <= krakow.Longitude && krakow.Longitude <= bottomRight.Longitude)
+}
+
+func TestTile(t *testing.T) {
+	r := Renderer{Points: []Point{{krakow, 60}}, TileSize: 64}
+	x, y := TileOf(krakow, 14)
+	img := r.Tile(14, x, y)
+	colored := 0
+	for py := 0; py < 64; py++ {
+		for px := 0; px < 64; px++ {
+			c := img.NRGBAAt(px, py)
+			if c.A != 0 {
+				colored++
+				assert.Equal(t, color.NRGBA{0xEF, 0xBB, 0x0F, 160}, c)
+			}
+		}
+	}
+	assert.Equal(t, 64*64, colored)
+
+	x, y = TileOf(airly.Location{Latitude: 52.229676, Longitude: 21.012229}, 14)
+	img = r.Tile(14, x, y)
+	assert.Equal(t, uint8(0), img.NRGBAAt(32, 32).A)
+}
+
+func TestInterpolate(t *testing.T) {
+	r := Renderer{}
+	points := []Point{{krakow, 10}, {airly.Location{Latitude: 50.072006, Longitude: 19.940984}, 30}}
+	v, ok := r.interpolate(krakow, points, 2)
+	assert.True(t, ok)
+	assert.Equal(t, 10.0, v)
+	v, ok = r.interpolate(airly.Location{Latitude: 50.067006, Longitude: 19.940984}, points, 2)
+	assert.True(t, ok)
+	assert.InDelta(t, 20, v, 0.01)
+	_, ok = r.interpolate(airly.Location{Latitude: 51, Longitude: 19.940984}, points, 2)
+	assert.False(t, ok)
+}
+
+func TestColor(t *testing.T) {
+	r := Renderer{Alpha: 255}
+	assert.Equal(t, color.NRGBA{0x6B, 0xC9, 0x26, 255}, r.color(-1))
+	assert.Equal(t, color.NRGBA{0xD1, 0xCF, 0x1E, 255}, r.color(25))
+	assert.Equal(t, color.NRGBA{0x77, 0x00, 0x78, 255}, r.color(500))
+}
+
+func TestPointsFromMeasurements(t *testing.T) {
+	installations := []airly.Installation{{Id: 204, Location: krakow}, {Id: 8077}}
+	measurements := map[int]airly.Measurements{204: {Current: airly.Measurement{
+		Values:  []airly.Value{{Name: "PM25", Value: 18.7}},
+		Indexes: []airly.Index{{Name: "AIRLY_CAQI", Value: 35.53}},
+	}}}
+	assert.Equal(t, []Point{{krakow, 35.53}}, PointsFromMeasurements(installations, measurements, "AIRLY_CAQI"))
+	assert.Equal(t, []Point{{krakow, 18.7}}, PointsFromMeasurements(installations, measurements, "PM25"))
+}
+
+func TestServeHTTP(t *testing.T) {
+	r := Renderer{Points: []Point{{krakow, 60}}}
+	x, y := TileOf(krakow, 12)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest("GET", "/tiles/12/"+strconv.Itoa(x)+"/"+strconv.Itoa(y)+".png", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "image/png", rec.Header().Get("Content-Type"))
+	img, err := png.Decode(bytes.NewReader(rec.Body.Bytes()))
+	assert.Nil(t, err)
+	assert.Equal(t, 256, img.Bounds().Dx())
+
+	for _, path := range []string{"/tiles/12/1/1", "/1/1.png", "/12/x/1.png", "/1/2/0.png"} {
+		rec = httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest("GET", path, nil))
+		assert.Equal(t, http.StatusNotFound, rec.Code, path)
+	}
+}
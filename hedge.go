@@ -0,0 +1,148 @@
+package airly
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// HedgingClient is HttpClient sending second, identical request if the first one doesn't respond within delay
+// based on observed latencies, the first successful response is returned and the other request is canceled.
+// It trades some API quota for lower tail latency. Only GET requests without body are hedged.
+// HedgingClient is safe for concurrent use
+type HedgingClient struct {
+	// HttpClient used to send requests, http.Client with DefaultTimeout is used if not set
+	HttpClient HttpClient
+	// Percentile of observed latencies used as hedging delay, 0.95 is used if not set
+	Percentile float64
+	// InitialDelay used as hedging delay until MinSamples latencies are observed, 1 second is used if not set
+	InitialDelay time.Duration
+	// MinSamples needed to use observed latencies, 20 is used if not set
+	MinSamples int
+	// Samples is number of the most recent latencies kept, 100 is used if not set
+	Samples int
+
+	mu      sync.Mutex
+	samples latencyWindow
+}
+
+type hedgeResult struct {
+	res     *http.Response
+	err     error
+	attempt int
+	latency time.Duration
+}
+
+// Do sends request, hedging it if it takes longer than usual
+func (c *HedgingClient) Do(req *http.Request) (*http.Response, error) {
+	client := c.HttpClient
+	if client == nil {
+		client = defaultHttpClient
+	}
+	if req.Method != "GET" || req.Body != nil {
+		return client.Do(req)
+	}
+
+	results := make(chan hedgeResult, 2)
+	var cancels []context.CancelFunc
+	send := func() {
+		ctx, cancel := context.WithCancel(req.Context())
+		cancels = append(cancels, cancel)
+		i := len(cancels) - 1
+		go func() {
+			start := time.Now()
+			res, err := client.Do(req.Clone(ctx))
+			results <- hedgeResult{res, err, i, time.Since(start)}
+		}()
+	}
+	send()
+	timer := time.NewTimer(c.delay())
+	defer timer.Stop()
+
+	var err error
+	for received := 0; received < len(cancels); {
+		select {
+		case <-timer.C:
+			if len(cancels) == 1 {
+				send()
+			}
+		case r := <-results:
+			received++
+			if r.err != nil {
+				cancels[r.attempt]()
+				err = r.err
+				continue
+			}
+			c.observe(r.latency)
+			for i, cancel := range cancels {
+				if i != r.attempt {
+					cancel()
+				}
+			}
+			if pending := len(cancels) - received; pending > 0 {
+				go discard(results, pending)
+			}
+			r.res.Body = &cancelOnClose{r.res.Body, cancels[r.attempt]}
+			return r.res, nil
+		}
+	}
+	return nil, err
+}
+
+// discard closes responses of requests which lost the race
+func discard(results <-chan hedgeResult, pending int) {
+	for i := 0; i < pending; i++ {
+		if r := <-results; r.err == nil {
+			_ = r.res.Body.Close()
+		}
+	}
+}
+
+// cancelOnClose cancels request context when response body is closed
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (c *cancelOnClose) Close() error {
+	err := c.ReadCloser.Close()
+	c.cancel()
+	return err
+}
+
+// delay returns current hedging delay
+func (c *HedgingClient) delay() time.Duration {
+	minSamples := c.MinSamples
+	if minSamples == 0 {
+		minSamples = 20
+	}
+	c.mu.Lock()
+	if len(c.samples.latencies) < minSamples {
+		c.mu.Unlock()
+		if c.InitialDelay == 0 {
+			return time.Second
+		}
+		return c.InitialDelay
+	}
+	latencies := c.samples.sorted()
+	c.mu.Unlock()
+
+	percentile := c.Percentile
+	if percentile == 0 {
+		percentile = 0.95
+	}
+	return latencyPercentile(latencies, percentile)
+}
+
+// observe records latency of successful request
+func (c *HedgingClient) observe(latency time.Duration) {
+	samples := c.Samples
+	if samples == 0 {
+		samples = 100
+	}
+	c.mu.Lock()
+	c.samples.add(latency, samples)
+	c.mu.Unlock()
+}
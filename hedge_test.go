@@ -0,0 +1,111 @@
+package airly
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestHedgingClient(t *testing.T) {
+	var mu sync.Mutex
+	calls := 0
+	canceled := make(chan struct{})
+	c := &HedgingClient{
+		InitialDelay: 10 * time.Millisecond,
+		HttpClient: mockClient{func(req *http.Request) (*http.Response, error) {
+			mu.Lock()
+			calls++
+			call := calls
+			mu.Unlock()
+			if call == 1 {
+				// the first request hangs until canceled
+				<-req.Context().Done()
+				close(canceled)
+				return nil, req.Context().Err()
+			}
+			return &http.Response{StatusCode: 200, Body: readCloser("second")}, nil
+		}},
+	}
+	req, _ := http.NewRequest("GET", "https://airapi.airly.eu/v2/installations/204", nil)
+	res, err := c.Do(req)
+	assert.Nil(t, err)
+	body, _ := ioutil.ReadAll(res.Body)
+	assert.Equal(t, "second", string(body))
+	assert.Nil(t, res.Body.Close())
+	select {
+	case <-canceled:
+	case <-time.After(time.Second):
+		t.Fatal("slow request not canceled")
+	}
+	assert.Equal(t, 2, calls)
+}
+
+func TestHedgingClientFastResponse(t *testing.T) {
+	calls := 0
+	c := &HedgingClient{
+		InitialDelay: time.Hour,
+		HttpClient: mockClient{func(req *http.Request) (*http.Response, error) {
+			calls++
+			return &http.Response{StatusCode: 200, Body: readCloser("ok")}, nil
+		}},
+	}
+	req, _ := http.NewRequest("GET", "https://airapi.airly.eu/v2/installations/204", nil)
+	for i := 0; i < 3; i++ {
+		res, err := c.Do(req)
+		assert.Nil(t, err)
+		assert.Nil(t, res.Body.Close())
+	}
+	assert.Equal(t, 3, calls)
+	assert.Len(t, c.samples.latencies, 3)
+
+	post, _ := http.NewRequest("POST", "https://example.com", strings.NewReader("body"))
+	_, _ = c.Do(post)
+	assert.Equal(t, 4, calls)
+	assert.Len(t, c.samples.latencies, 3)
+}
+
+func TestHedgingClientErrors(t *testing.T) {
+	var mu sync.Mutex
+	calls := 0
+	c := &HedgingClient{
+		InitialDelay: time.Millisecond,
+		HttpClient: mockClient{func(req *http.Request) (*http.Response, error) {
+			mu.Lock()
+			calls++
+			mu.Unlock()
+			time.Sleep(5 * time.Millisecond)
+			return nil, errors.New("connection refused")
+		}},
+	}
+	req, _ := http.NewRequest("GET", "https://airapi.airly.eu/v2/installations/204", nil)
+	_, err := c.Do(req)
+	assert.EqualError(t, err, "connection refused")
+	assert.Equal(t, 2, calls)
+}
+
+func TestHedgingClientDelay(t *testing.T) {
+	c := &HedgingClient{MinSamples: 4, Samples: 5, Percentile: 0.5}
+	assert.Equal(t, time.Second, c.delay())
+	for _, l := range []int{40, 10, 30, 20} {
+		c.observe(time.Duration(l) * time.Millisecond)
+	}
+	assert.Equal(t, 20*time.Millisecond, c.delay())
+	for _, l := range []int{50, 60, 70} {
+		c.observe(time.Duration(l) * time.Millisecond)
+	}
+	// 40 and 10 were replaced by 60 and 70
+	assert.Equal(t, []time.Duration{60 * time.Millisecond, 70 * time.Millisecond, 30 * time.Millisecond,
+		20 * time.Millisecond, 50 * time.Millisecond}, c.samples.latencies)
+	assert.Equal(t, 50*time.Millisecond, c.delay())
+	c.Percentile = 1
+	assert.Equal(t, 70*time.Millisecond, c.delay())
+	c.Percentile = 1.5
+	assert.Equal(t, 70*time.Millisecond, c.delay())
+	c.Percentile = -1
+	assert.Equal(t, 20*time.Millisecond, c.delay())
+}
@@ -0,0 +1,144 @@
+// Package ical exports forecasted poor air periods as iCalendar (RFC 5545) events,
+// so they can be shown alongside other events in calendar applications
+package ical
+
+import (
+	"fmt"
+	"github.com/probakowski/go-airly"
+	"io"
+	"math"
+	"strings"
+	"time"
+)
+
+// Event is a period when forecasted value exceeds threshold
+type Event struct {
+	Start time.Time
+	End   time.Time
+	// Name of value or index, e.g. PM25 or AIRLY_CAQI
+	Name string
+	// Max forecasted value in the period
+	Max float64
+}
+
+// Events returns periods of consecutive forecast windows where value or index with given name exceeds threshold
+func Events(forecast []airly.Measurement, name string, threshold float64) []Event {
+	var events []Event
+	var current *Event
+	for _, m := range forecast {
+		v, ok := value(m, name)
+		if !ok || v <= threshold {
+			current = nil
+			continue
+		}
+		if current != nil && !m.FromDateTime.After(current.End) {
+			current.End = m.TillDateTime
+			current.Max = math.Max(current.Max, v)
+			continue
+		}
+		events = append(events, Event{Start: m.FromDateTime, End: m.TillDateTime, Name: name, Max: v})
+		current = &events[len(events)-1]
+	}
+	return events
+}
+
+func value(m airly.Measurement, name string) (float64, bool) {
+	if v, ok := m.Value(name); ok {
+		return v, true
+	}
+	for _, i := range m.Indexes {
+		if i.Name == name {
+			return i.Value, true
+		}
+	}
+	return 0, false
+}
+
+// labels of names that are displayed differently than returned by API
+var labels = map[string]string{
+	"PM25":       "PM2.5",
+	"AIRLY_CAQI": "CAQI",
+}
+
+// Summary of event, e.g. "Poor air expected, PM2.5 ~60"
+func (e Event) Summary() string {
+	label, ok := labels[e.Name]
+	if !ok {
+		label = e.Name
+	}
+	return fmt.Sprintf("Poor air expected, %s ~%.0f", label, e.Max)
+}
+
+// Calendar of poor air events
+type Calendar struct {
+	Events []Event
+	// Name of calendar, "Air quality forecast" is used if not set
+	Name string
+	// Stamp is used as DTSTAMP of events, current time is used if not set
+	Stamp time.Time
+}
+
+const timeFormat = "20060102T150405Z"
+
+// WriteTo writes calendar in iCalendar format
+func (c Calendar) WriteTo(w io.Writer) (int64, error) {
+	name := c.Name
+	if name == "" {
+		name = "Air quality forecast"
+	}
+	stamp := c.Stamp
+	if stamp.IsZero() {
+		stamp = time.Now()
+	}
+	lines := []string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//probakowski//go-airly//EN",
+		"CALSCALE:GREGORIAN",
+		"X-WR-CALNAME:" + escape(name),
+	}
+	for _, e := range c.Events {
+		lines = append(lines,
+			"BEGIN:VEVENT",
+			fmt.Sprintf("UID:%s-%s@go-airly", e.Start.UTC().Format(timeFormat), strings.ToLower(e.Name)),
+			"DTSTAMP:"+stamp.UTC().Format(timeFormat),
+			"DTSTART:"+e.Start.UTC().Format(timeFormat),
+			"DTEND:"+e.End.UTC().Format(timeFormat),
+			"SUMMARY:"+escape(e.Summary()),
+			"TRANSP:TRANSPARENT",
+			"END:VEVENT",
+		)
+	}
+	lines = append(lines, "END:VCALENDAR")
+
+	var written int64
+	for _, line := range lines {
+		n, err := io.WriteString(w, fold(line)+"\r\n")
+		written += int64(n)
+		if err != nil {
+			return written, err
+		}
+	}
+	return written, nil
+}
+
+// escape escapes TEXT value, see https://tools.ietf.org/html/rfc5545#section-3.3.11
+func escape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`).Replace(s)
+}
+
+// fold splits lines longer than 75 octets, continuation lines start with space
+func fold(line string) string {
+	var b strings.Builder
+	length := 0
+	for _, r := range line {
+		size := len(string(r))
+		if length+size > 75 {
+			b.WriteString("\r\n ")
+			length = 1
+		}
+		b.WriteRune(r)
+		length += size
+	}
+	return b.String()
+}
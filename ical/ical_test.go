@@ -0,0 +1,74 @@
+package ical
+
+import (
+	"bytes"
+	"github.com/probakowski/go-airly"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+	"time"
+)
+
+var start = time.Date(2020, 11, 18, 20, 0, 0, 0, time.UTC)
+
+func window(hour int, pm25 float64) airly.Measurement {
+	return airly.Measurement{
+		FromDateTime: start.Add(time.Duration(hour) * time.Hour),
+		TillDateTime: start.Add(time.Duration(hour+1) * time.Hour),
+		Values:       []airly.Value{{Name: "PM25", Value: pm25}},
+	}
+}
+
+func TestEvents(t *testing.T) {
+	forecast := []airly.Measurement{window(0, 20), window(1, 40), window(2, 61.4), window(3, 30),
+		window(4, 20), window(5, 55), {FromDateTime: start.Add(6 * time.Hour), TillDateTime: start.Add(7 * time.Hour)}}
+	assert.Equal(t, []Event{
+		{Start: start.Add(time.Hour), End: start.Add(4 * time.Hour), Name: "PM25", Max: 61.4},
+		{Start: start.Add(5 * time.Hour), End: start.Add(6 * time.Hour), Name: "PM25", Max: 55},
+	}, Events(forecast, "PM25", 25))
+	assert.Nil(t, Events(forecast, "PM25", 100))
+	assert.Nil(t, Events(forecast, "PM10", 0))
+}
+
+func TestSummary(t *testing.T) {
+	assert.Equal(t, "Poor air expected, PM2.5 ~61", Event{Name: "PM25", Max: 61.4}.Summary())
+	assert.Equal(t, "Poor air expected, NO2 ~120", Event{Name: "NO2", Max: 120}.Summary())
+}
+
+func TestWriteTo(t *testing.T) {
+	var buf bytes.Buffer
+	n, err := Calendar{
+		Events: []Event{{Start: start, End: start.Add(3 * time.Hour), Name: "PM25", Max: 60}},
+		Name:   "Kraków, Mikołajska",
+		Stamp:  time.Date(2020, 11, 18, 19, 30, 0, 0, time.UTC),
+	}.WriteTo(&buf)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(buf.Len()), n)
+	assert.Equal(t, strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//probakowski//go-airly//EN",
+		"CALSCALE:GREGORIAN",
+		`X-WR-CALNAME:Kraków\, Mikołajska`,
+		"BEGIN:VEVENT",
+		"UID:20201118T200000Z-pm25@go-airly",
+		"DTSTAMP:20201118T193000Z",
+		"DTSTART:20201118T200000Z",
+		"DTEND:20201118T230000Z",
+		`SUMMARY:Poor air expected\, PM2.5 ~60`,
+		"TRANSP:TRANSPARENT",
+		"END:VEVENT",
+		"END:VCALENDAR",
+		"",
+	}, "\r\n"), buf.String())
+}
+
+func TestFold(t *testing.T) {
+	line := "SUMMARY:" + strings.Repeat("ą", 40)
+	folded := fold(line)
+	for _, l := range strings.Split(folded, "\r\n") {
+		assert.True(t, len(l) <= 75)
+	}
+	assert.Equal(t, line, strings.Replace(folded, "\r\n ", "", -1))
+	assert.Equal(t, "VERSION:2.0", fold("VERSION:2.0"))
+}
@@ -0,0 +1,57 @@
+package airly
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Language of descriptions and advices in API responses, sent in Accept-Language header
+type Language string
+
+// Languages supported by API, constants are untyped so they can be used both as Client.Language and as Language
+const (
+	English = "en"
+	Polish  = "pl"
+)
+
+// ErrUnsupportedLanguage is returned (wrapped) by Language.Validate, ParseLanguage and by client calls with language
+// API doesn't support, instead of silently falling back to default one
+var ErrUnsupportedLanguage = errors.New("unsupported language")
+
+// SupportedLanguages returns all languages supported by API
+func SupportedLanguages() []Language {
+	return []Language{English, Polish}
+}
+
+// Validate checks that language is supported by API, empty language (API default) is valid as well. Error wrapping
+// ErrUnsupportedLanguage is returned otherwise
+func (l Language) Validate() error {
+	if l == "" {
+		return nil
+	}
+	for _, s := range SupportedLanguages() {
+		if l == s {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w %q, supported languages are %s", ErrUnsupportedLanguage, string(l), languageList())
+}
+
+// ParseLanguage parses language code, case and surrounding whitespace are ignored. Returned error wraps
+// ErrUnsupportedLanguage
+func ParseLanguage(s string) (Language, error) {
+	l := Language(strings.ToLower(strings.TrimSpace(s)))
+	if l == "" {
+		return "", fmt.Errorf("%w: empty language, supported languages are %s", ErrUnsupportedLanguage, languageList())
+	}
+	return l, l.Validate()
+}
+
+func languageList() string {
+	var codes []string
+	for _, l := range SupportedLanguages() {
+		codes = append(codes, string(l))
+	}
+	return strings.Join(codes, ", ")
+}
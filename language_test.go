@@ -0,0 +1,48 @@
+package airly
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+func TestParseLanguage(t *testing.T) {
+	for s, expected := range map[string]Language{"en": English, " PL ": Polish, "Pl": Polish} {
+		l, err := ParseLanguage(s)
+		assert.NoError(t, err, s)
+		assert.Equal(t, expected, l, s)
+	}
+	for _, s := range []string{"", "eng", "de", "pl-PL"} {
+		_, err := ParseLanguage(s)
+		assert.ErrorIs(t, err, ErrUnsupportedLanguage, s)
+	}
+	_, err := ParseLanguage("eng")
+	assert.EqualError(t, err, `unsupported language "eng", supported languages are en, pl`)
+	assert.Equal(t, []Language{English, Polish}, SupportedLanguages())
+}
+
+func TestLanguageValidate(t *testing.T) {
+	for _, l := range []Language{"", English, Polish} {
+		assert.NoError(t, l.Validate(), l)
+	}
+	assert.ErrorIs(t, Language("EN").Validate(), ErrUnsupportedLanguage)
+
+	// Client.Language is a plain string, so existing code assigning string variables keeps compiling
+	language := "eng"
+	client := Client{
+		Key:      "abc",
+		Language: language,
+		HttpClient: mockClient{func(req *http.Request) (*http.Response, error) {
+			return nil, errors.New("request sent")
+		}},
+	}
+	_, err := client.Installation(204)
+	assert.ErrorIs(t, err, ErrUnsupportedLanguage)
+	_, err = client.NearestInstallations(Location{50.062006, 19.940984})
+	assert.ErrorIs(t, err, ErrUnsupportedLanguage)
+
+	client.Language = Polish
+	_, err = client.Installation(204)
+	assert.False(t, errors.Is(err, ErrUnsupportedLanguage))
+}
@@ -0,0 +1,41 @@
+package airly
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// latencyWindow keeps the most recent latencies, it's not safe for concurrent use
+type latencyWindow struct {
+	latencies []time.Duration
+	next      int
+}
+
+// add records latency, replacing the oldest one if there are already size latencies
+func (w *latencyWindow) add(latency time.Duration, size int) {
+	if len(w.latencies) < size {
+		w.latencies = append(w.latencies, latency)
+		return
+	}
+	w.latencies[w.next%size] = latency
+	w.next++
+}
+
+// sorted returns sorted copy of latencies
+func (w *latencyWindow) sorted() []time.Duration {
+	latencies := append([]time.Duration(nil), w.latencies...)
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	return latencies
+}
+
+// latencyPercentile returns p-th percentile (nearest rank) of sorted, non-empty latencies, p is clamped to [0, 1]
+func latencyPercentile(latencies []time.Duration, p float64) time.Duration {
+	i := int(math.Ceil(p*float64(len(latencies)))) - 1
+	if i < 0 {
+		i = 0
+	} else if i >= len(latencies) {
+		i = len(latencies) - 1
+	}
+	return latencies[i]
+}
@@ -0,0 +1,26 @@
+package airly
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestLatencyWindow(t *testing.T) {
+	var w latencyWindow
+	for _, l := range []time.Duration{40, 10, 30, 20, 50} {
+		w.add(l, 3)
+	}
+	assert.Equal(t, []time.Duration{20, 50, 30}, w.latencies)
+	assert.Equal(t, []time.Duration{20, 30, 50}, w.sorted())
+	assert.Equal(t, []time.Duration{20, 50, 30}, w.latencies)
+}
+
+func TestLatencyPercentile(t *testing.T) {
+	latencies := []time.Duration{10, 20, 30, 40}
+	assert.Equal(t, time.Duration(10), latencyPercentile(latencies, -1))
+	assert.Equal(t, time.Duration(10), latencyPercentile(latencies, 0))
+	assert.Equal(t, time.Duration(20), latencyPercentile(latencies, 0.5))
+	assert.Equal(t, time.Duration(40), latencyPercentile(latencies, 0.99))
+	assert.Equal(t, time.Duration(40), latencyPercentile(latencies, 1.5))
+}
@@ -0,0 +1,42 @@
+package airly
+
+// LevelLabel is a compact representation of index level, e.g. for chat messages and status bars
+type LevelLabel struct {
+	Emoji string `json:"emoji"`
+	Short string `json:"short"`
+}
+
+// LevelLabels maps index levels (e.g. LOW, HIGH) to labels
+type LevelLabels map[string]LevelLabel
+
+// DefaultLevelLabels are labels of levels used by Airly indexes
+var DefaultLevelLabels = LevelLabels{
+	"VERY_LOW":    {"🟢", "Great"},
+	"LOW":         {"🟢", "Good"},
+	"MEDIUM":      {"🟡", "OK"},
+	"HIGH":        {"🟠", "Bad"},
+	"VERY_HIGH":   {"🔴", "Very bad"},
+	"EXTREME":     {"🟣", "Extreme"},
+	"AIRMAGEDDON": {"☠️", "Deadly"},
+}
+
+// UnknownLevelLabel is used for levels without label
+var UnknownLevelLabel = LevelLabel{"❔", "?"}
+
+// Label returns label of level, labels in l override DefaultLevelLabels. Fields left empty in override
+// are taken from default label
+func (l LevelLabels) Label(level string) LevelLabel {
+	label, ok := DefaultLevelLabels[level]
+	if !ok {
+		label = UnknownLevelLabel
+	}
+	if override, ok := l[level]; ok {
+		if override.Emoji != "" {
+			label.Emoji = override.Emoji
+		}
+		if override.Short != "" {
+			label.Short = override.Short
+		}
+	}
+	return label
+}
@@ -0,0 +1,19 @@
+package airly
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestLevelLabels(t *testing.T) {
+	var defaults LevelLabels
+	assert.Equal(t, LevelLabel{"🟠", "Bad"}, defaults.Label("HIGH"))
+	assert.Equal(t, LevelLabel{"☠️", "Deadly"}, defaults.Label("AIRMAGEDDON"))
+	assert.Equal(t, UnknownLevelLabel, defaults.Label("UNKNOWN"))
+
+	custom := LevelLabels{"HIGH": {Short: "Źle"}, "MEDIUM": {"🙂", "Średnio"}, "NEW": {Emoji: "🆕"}}
+	assert.Equal(t, LevelLabel{"🟠", "Źle"}, custom.Label("HIGH"))
+	assert.Equal(t, LevelLabel{"🙂", "Średnio"}, custom.Label("MEDIUM"))
+	assert.Equal(t, LevelLabel{"🆕", "?"}, custom.Label("NEW"))
+	assert.Equal(t, LevelLabel{"🟢", "Good"}, custom.Label("LOW"))
+}
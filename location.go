@@ -0,0 +1,153 @@
+package airly
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// earthRadius is mean Earth radius in km
+const earthRadius = 6371.0
+
+// ErrInvalidLocation is returned (wrapped) by Location.Validate and by client calls for locations that API would
+// reject anyway
+var ErrInvalidLocation = errors.New("invalid location")
+
+// Validate checks that latitude is in [-90, 90] and longitude in [-180, 180] range, error wrapping
+// ErrInvalidLocation is returned otherwise
+func (l Location) Validate() error {
+	if math.IsNaN(l.Latitude) || math.IsNaN(l.Longitude) {
+		return fmt.Errorf("%w: coordinates must be numbers", ErrInvalidLocation)
+	}
+	if l.Latitude < -90 || l.Latitude > 90 {
+		return fmt.Errorf("%w: latitude %v out of range [-90, 90]", ErrInvalidLocation, l.Latitude)
+	}
+	if l.Longitude < -180 || l.Longitude > 180 {
+		return fmt.Errorf("%w: longitude %v out of range [-180, 180]", ErrInvalidLocation, l.Longitude)
+	}
+	return nil
+}
+
+// Distance returns great-circle distance to other location in km
+func (l Location) Distance(other Location) float64 {
+	lat1 := l.Latitude * math.Pi / 180
+	lat2 := other.Latitude * math.Pi / 180
+	dLat := lat2 - lat1
+	dLng := (other.Longitude - l.Longitude) * math.Pi / 180
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * earthRadius * math.Asin(math.Sqrt(a))
+}
+
+// coordinateRegexp matches single coordinate in decimal degrees or degrees, minutes and seconds with optional
+// hemisphere before or after it, e.g. 50.062, -19.5, N 50°03.72' or 50°03'43.2"N
+var coordinateRegexp = regexp.MustCompile(`^(?i)([NSEW]?)\s*([-+]?\d+(?:\.\d+)?)\s*` +
+	`(?:[°º]\s*(?:(\d+(?:\.\d+)?)\s*['′’]\s*(?:(\d+(?:\.\d+)?)\s*(?:"|″|”|'')\s*)?)?)?([NSEW]?)$`)
+
+// ParseLocation parses location in one of formats users usually paste:
+//   - decimal degrees separated by comma or space: 50.062,19.941 or 50.062 19.941
+//   - degrees, minutes and seconds with hemispheres: 50°03'43.2"N 19°56'27.5"E, also with decimal minutes or
+//     hemisphere before coordinates: N 50°03.72', E 19°56.458'
+//   - geo URI (RFC 5870): geo:50.062,19.941 (altitude and parameters are ignored)
+//
+// Latitude is always given first. Returned error wraps ErrInvalidLocation
+func ParseLocation(s string) (Location, error) {
+	s = strings.TrimSpace(s)
+	if len(s) > 4 && strings.EqualFold(s[:4], "geo:") {
+		return parseGeoURI(s)
+	}
+	lat, lng, ok := splitCoordinates(s)
+	if !ok {
+		return Location{}, fmt.Errorf("%w: cannot parse %q, latitude and longitude expected", ErrInvalidLocation, s)
+	}
+	var loc Location
+	var err error
+	if loc.Latitude, err = parseCoordinate(lat, 'N', 'S'); err != nil {
+		return Location{}, fmt.Errorf("%w: cannot parse latitude %q", ErrInvalidLocation, lat)
+	}
+	if loc.Longitude, err = parseCoordinate(lng, 'E', 'W'); err != nil {
+		return Location{}, fmt.Errorf("%w: cannot parse longitude %q", ErrInvalidLocation, lng)
+	}
+	return loc, loc.Validate()
+}
+
+// splitCoordinates splits s into latitude and longitude, separated by comma, hemisphere letter or whitespace
+func splitCoordinates(s string) (string, string, bool) {
+	if parts := strings.Split(s, ","); len(parts) == 2 {
+		return parts[0], parts[1], true
+	} else if len(parts) > 2 {
+		return "", "", false
+	}
+	if i := strings.IndexAny(s, "EWew"); i > 0 && strings.ContainsAny(s[:1], "NSns") {
+		return s[:i], s[i:], true
+	}
+	if i := strings.IndexAny(s, "NSns"); i > 0 {
+		return s[:i+1], s[i+1:], true
+	}
+	if fields := strings.Fields(s); len(fields) == 2 {
+		return fields[0], fields[1], true
+	}
+	return "", "", false
+}
+
+// parseCoordinate parses single coordinate matched by coordinateRegexp, positive and negative are hemisphere letters
+func parseCoordinate(s string, positive, negative byte) (float64, error) {
+	m := coordinateRegexp.FindStringSubmatch(strings.TrimSpace(s))
+	if m == nil || (m[1] != "" && m[5] != "") {
+		return 0, fmt.Errorf("invalid coordinate %q", s)
+	}
+	v, err := strconv.ParseFloat(m[2], 64)
+	if err != nil {
+		return 0, err
+	}
+	for i, scale := range []float64{60, 3600} {
+		if m[i+3] == "" {
+			continue
+		}
+		part, err := strconv.ParseFloat(m[i+3], 64)
+		if err != nil || part >= 60 {
+			return 0, fmt.Errorf("invalid coordinate %q", s)
+		}
+		v += part / scale
+	}
+	switch hemisphere := strings.ToUpper(m[1] + m[5]); {
+	case hemisphere == "":
+		return v, nil
+	case strings.HasPrefix(m[2], "-"):
+		return 0, fmt.Errorf("invalid coordinate %q, both sign and hemisphere given", s)
+	case hemisphere[0] == positive:
+		return v, nil
+	case hemisphere[0] == negative:
+		return -v, nil
+	}
+	return 0, fmt.Errorf("invalid hemisphere of coordinate %q", s)
+}
+
+// parseGeoURI parses geo URI, e.g. geo:50.062,19.941,220;u=35. Only WGS-84 coordinate reference system is supported
+func parseGeoURI(s string) (Location, error) {
+	path := s[4:]
+	if i := strings.IndexAny(path, ";?"); i >= 0 {
+		for _, param := range strings.Split(path[i+1:], ";") {
+			if kv := strings.SplitN(param, "=", 2); strings.EqualFold(kv[0], "crs") && len(kv) == 2 &&
+				!strings.EqualFold(kv[1], "wgs84") {
+				return Location{}, fmt.Errorf("%w: unsupported coordinate reference system in %q", ErrInvalidLocation, s)
+			}
+		}
+		path = path[:i]
+	}
+	coords := strings.Split(path, ",")
+	if len(coords) != 2 && len(coords) != 3 {
+		return Location{}, fmt.Errorf("%w: cannot parse %q, latitude and longitude expected", ErrInvalidLocation, s)
+	}
+	var loc Location
+	var err error
+	if loc.Latitude, err = strconv.ParseFloat(coords[0], 64); err != nil {
+		return Location{}, fmt.Errorf("%w: cannot parse latitude %q", ErrInvalidLocation, coords[0])
+	}
+	if loc.Longitude, err = strconv.ParseFloat(coords[1], 64); err != nil {
+		return Location{}, fmt.Errorf("%w: cannot parse longitude %q", ErrInvalidLocation, coords[1])
+	}
+	return loc, loc.Validate()
+}
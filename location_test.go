@@ -0,0 +1,57 @@
+package airly
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math"
+	"testing"
+)
+
+func TestDistance(t *testing.T) {
+	krakow := Location{50.062006, 19.940984}
+	warsaw := Location{52.229676, 21.012229}
+	assert.InDelta(t, 252.2, krakow.Distance(warsaw), 0.5)
+	assert.InDelta(t, krakow.Distance(warsaw), warsaw.Distance(krakow), 1e-9)
+	assert.Equal(t, 0.0, krakow.Distance(krakow))
+}
+
+func TestValidate(t *testing.T) {
+	for _, loc := range []Location{{}, {90, 180}, {-90, -180}, {50.062006, 19.940984}} {
+		assert.NoError(t, loc.Validate(), loc)
+	}
+	for _, loc := range []Location{{90.1, 0}, {-91, 0}, {0, 180.5}, {0, -181}, {math.NaN(), 0}, {0, math.NaN()},
+		{math.Inf(1), 0}} {
+		assert.ErrorIs(t, loc.Validate(), ErrInvalidLocation, loc)
+	}
+	assert.EqualError(t, Location{100, 0}.Validate(), "invalid location: latitude 100 out of range [-90, 90]")
+}
+
+func TestParseLocation(t *testing.T) {
+	for s, expected := range map[string]Location{
+		"50.062,19.941":                    {50.062, 19.941},
+		" 50.062, 19.941 ":                 {50.062, 19.941},
+		"50.062 19.941":                    {50.062, 19.941},
+		"-33.8688,151.2093":                {-33.8688, 151.2093},
+		`50°03'43.2"N 19°56'27.5"E`:        {50.062, 19.940972},
+		`50° 03' 43.2" N, 19° 56' 27.5" E`: {50.062, 19.940972},
+		"50°03′43.2″N 19°56′27.5″E":        {50.062, 19.940972},
+		`33°52'7.68"S 151°12'33.48"E`:      {-33.8688, 151.2093},
+		"N 50°03.72' E 19°56.458'":         {50.062, 19.940967},
+		"50.062°N, 19.941°W":               {50.062, -19.941},
+		"50.062s 19.941e":                  {-50.062, 19.941},
+		"geo:50.062,19.941":                {50.062, 19.941},
+		"GEO:50.062,19.941,220;u=35":       {50.062, 19.941},
+		"geo:50.062,19.941;crs=wgs84;u=35": {50.062, 19.941},
+		"geo:50.062,19.941?z=11":           {50.062, 19.941},
+	} {
+		loc, err := ParseLocation(s)
+		if assert.NoError(t, err, s) {
+			assert.InDelta(t, expected.Latitude, loc.Latitude, 1e-6, s)
+			assert.InDelta(t, expected.Longitude, loc.Longitude, 1e-6, s)
+		}
+	}
+	for _, s := range []string{"", "50.062", "50.062,19.941,220", "a,b", "91,0", "0,181", "50°60'N 19°E",
+		"-50.062N 19.941E", "19.941E 50.062N", "geo:50.062", "geo:50.062,19.941;crs=EPSG:2180", "geo:x,19"} {
+		_, err := ParseLocation(s)
+		assert.ErrorIs(t, err, ErrInvalidLocation, s)
+	}
+}
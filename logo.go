@@ -0,0 +1,142 @@
+package airly
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ErrInvalidLogo is returned when sponsor logo is not an image of supported type or is too large
+var ErrInvalidLogo = errors.New("invalid logo")
+
+// logoExtensions are extensions of cached logos by supported content type
+var logoExtensions = map[string]string{
+	"image/png":  ".png",
+	"image/jpeg": ".jpg",
+	"image/gif":  ".gif",
+	"image/webp": ".webp",
+}
+
+// LogoCache downloads sponsor logos and caches them on disk, so UIs don't fetch them from CDN on every render.
+// Only PNG, JPEG, GIF and WebP images are accepted, SVG is rejected as it can contain scripts which would run when
+// logo is embedded in a page. LogoCache is safe for concurrent use
+type LogoCache struct {
+	// Dir where logos are cached, airly/logos in user cache directory is used if empty
+	Dir string
+	// MaxAge of cached logos, logos are downloaded again when it passes, they are kept forever if zero
+	MaxAge time.Duration
+	// MaxSize of logo in bytes, 1 MiB is used if zero
+	MaxSize int64
+	// HttpClient used for downloads, http.Client with DefaultTimeout is used if nil
+	HttpClient HttpClient
+}
+
+func (c LogoCache) dir() (string, error) {
+	if c.Dir != "" {
+		return c.Dir, nil
+	}
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "airly", "logos"), nil
+}
+
+// Logo returns logo image of sponsor with its content type, from cache if possible
+func (c LogoCache) Logo(s Sponsor) ([]byte, string, error) {
+	if s.Logo == "" {
+		return nil, "", fmt.Errorf("sponsor %q has no logo: %w", s.Name, ErrInvalidLogo)
+	}
+	dir, err := c.dir()
+	if err != nil {
+		return nil, "", err
+	}
+	sum := sha256.Sum256([]byte(s.Logo))
+	base := filepath.Join(dir, hex.EncodeToString(sum[:16]))
+	for contentType, ext := range logoExtensions {
+		info, err := os.Stat(base + ext)
+		if err != nil || (c.MaxAge > 0 && time.Since(info.ModTime()) > c.MaxAge) {
+			continue
+		}
+		if data, err := ioutil.ReadFile(base + ext); err == nil {
+			return data, contentType, nil
+		}
+	}
+
+	data, contentType, err := c.download(s.Logo)
+	if err != nil {
+		return nil, "", err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, "", err
+	}
+	// written to temporary file first, so concurrent readers never see partial logo
+	tmp, err := ioutil.TempFile(dir, "logo")
+	if err != nil {
+		return nil, "", err
+	}
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), base+logoExtensions[contentType])
+	}
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+		return nil, "", err
+	}
+	return data, contentType, nil
+}
+
+// download fetches logo and validates its content type
+func (c LogoCache) download(url string) ([]byte, string, error) {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	client := c.HttpClient
+	if client == nil {
+		client = defaultHttpClient
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, "", err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != 200 {
+		return nil, "", &APIError{StatusCode: res.StatusCode}
+	}
+	maxSize := c.MaxSize
+	if maxSize == 0 {
+		maxSize = 1 << 20
+	}
+	data, err := ioutil.ReadAll(io.LimitReader(res.Body, maxSize+1))
+	if err != nil {
+		return nil, "", err
+	}
+	if int64(len(data)) > maxSize {
+		return nil, "", fmt.Errorf("logo %s larger than %d bytes: %w", url, maxSize, ErrInvalidLogo)
+	}
+	contentType, _, err := mime.ParseMediaType(res.Header.Get("Content-Type"))
+	if err != nil || contentType == "" {
+		contentType = http.DetectContentType(data)
+	}
+	if _, ok := logoExtensions[contentType]; !ok {
+		return nil, "", fmt.Errorf("logo %s has content type %q: %w", url, contentType, ErrInvalidLogo)
+	}
+	// content is sniffed as well, so e.g. HTML error page served as image/png is rejected
+	if sniffed := http.DetectContentType(data); sniffed != contentType {
+		return nil, "", fmt.Errorf("logo %s has content type %q but looks like %q: %w", url, contentType, sniffed,
+			ErrInvalidLogo)
+	}
+	return data, contentType, nil
+}
@@ -0,0 +1,56 @@
+package airly
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"testing"
+)
+
+// pngLogo is 1x1 transparent PNG image
+var pngLogo = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR\x00\x00\x00\x01\x00\x00\x00\x01\x08\x06\x00\x00\x00\x1f\x15\xc4\x89" +
+	"\x00\x00\x00\rIDATx\x9cc\x00\x01\x00\x00\x05\x00\x01\r\n-\xb4\x00\x00\x00\x00IEND\xaeB`\x82")
+
+func TestLogoCache(t *testing.T) {
+	dir, err := ioutil.TempDir("", "airly")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	requests := 0
+	bodies := map[string]struct {
+		contentType string
+		body        []byte
+	}{
+		"https://cdn.airly.org/logo.png":  {"image/png", pngLogo},
+		"https://cdn.airly.org/logo.svg":  {"image/svg+xml; charset=utf-8", []byte(`<svg xmlns="http://www.w3.org/2000/svg"/>`)},
+		"https://cdn.airly.org/error.png": {"image/png", []byte("<html>Not found</html>")},
+		"https://cdn.airly.org/logo.txt":  {"text/plain", []byte("logo")},
+		"https://cdn.airly.org/large.png": {"image/png", append(pngLogo, make([]byte, 100)...)},
+	}
+	cache := LogoCache{Dir: dir, MaxSize: 100, HttpClient: mockClient{func(req *http.Request) (*http.Response, error) {
+		requests++
+		b := bodies[req.URL.String()]
+		return &http.Response{StatusCode: 200, Header: http.Header{"Content-Type": {b.contentType}},
+			Body: readCloser(string(b.body))}, nil
+	}}}
+
+	for i := 0; i < 2; i++ {
+		data, contentType, err := cache.Logo(Sponsor{Name: "Airly", Logo: "https://cdn.airly.org/logo.png"})
+		assert.NoError(t, err)
+		assert.Equal(t, pngLogo, data)
+		assert.Equal(t, "image/png", contentType)
+	}
+	assert.Equal(t, 1, requests)
+
+	// SVG can contain scripts
+	for _, logo := range []string{"https://cdn.airly.org/logo.svg", "https://cdn.airly.org/error.png",
+		"https://cdn.airly.org/logo.txt", "https://cdn.airly.org/large.png", ""} {
+		_, _, err := cache.Logo(Sponsor{Logo: logo})
+		assert.True(t, errors.Is(err, ErrInvalidLogo), logo)
+	}
+	files, err := ioutil.ReadDir(dir)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(files))
+}
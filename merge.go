@@ -0,0 +1,172 @@
+package airly
+
+import (
+	"encoding/json"
+	"sort"
+	"sync"
+	"time"
+)
+
+// HistoryMerger accumulates history from successive fetches of measurements, so series longer than 24 hours
+// returned by API can be built by polling. Windows are deduplicated by their boundaries, values fetched later
+// replace earlier ones. It can be saved and restored with encoding/json, e.g. to continue series after restart with
+// SeriesBuilder. HistoryMerger is safe for concurrent use
+type HistoryMerger struct {
+	// MaxAge of kept windows, measured from the end of the latest window of installation, zero means no limit
+	MaxAge time.Duration
+
+	mu      sync.Mutex
+	history map[int]map[Window]Measurement
+	closed  map[int]bool
+}
+
+// windowKey returns window comparable with == regardless of location and monotonic clock reading
+func windowKey(w Window) Window {
+	return Window{Start: w.Start.UTC().Round(0), End: w.End.UTC().Round(0)}
+}
+
+// Add merges history of measurements of installation, it returns number of windows which were not known before
+func (h *HistoryMerger) Add(installationId int, m Measurements) int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.history == nil {
+		h.history = map[int]map[Window]Measurement{}
+	}
+	history := h.history[installationId]
+	if history == nil {
+		history = map[Window]Measurement{}
+		h.history[installationId] = history
+	}
+	added := 0
+	for _, measurement := range m.History {
+		k := windowKey(measurement.Window())
+		if _, ok := history[k]; !ok {
+			added++
+		}
+		history[k] = measurement
+	}
+	if added > 0 {
+		delete(h.closed, installationId)
+	}
+	if h.MaxAge > 0 {
+		var latest time.Time
+		for w := range history {
+			if w.End.After(latest) {
+				latest = w.End
+			}
+		}
+		for w := range history {
+			if latest.Sub(w.End) > h.MaxAge {
+				delete(history, w)
+			}
+		}
+	}
+	return added
+}
+
+// History returns merged history of installation in chronological order
+func (h *HistoryMerger) History(installationId int) []Measurement {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	history := make([]Measurement, 0, len(h.history[installationId]))
+	for _, measurement := range h.history[installationId] {
+		history = append(history, measurement)
+	}
+	SortByWindow(history)
+	return history
+}
+
+// Gaps returns time ranges between the first and the latest window of installation not covered by merged history,
+// e.g. when collector was down for more than 24 hours
+func (h *HistoryMerger) Gaps(installationId int) []Window {
+	var gaps []Window
+	var end time.Time
+	for i, measurement := range h.History(installationId) {
+		if i > 0 && end.Before(measurement.FromDateTime) {
+			gaps = append(gaps, Window{Start: end, End: measurement.FromDateTime})
+		}
+		if measurement.TillDateTime.After(end) {
+			end = measurement.TillDateTime
+		}
+	}
+	return gaps
+}
+
+// Close marks series of installation as closed, e.g. when InstallationWatcher reports it decommissioned, so its
+// latest window is not presented as current data. Series is reopened when Add merges new windows
+func (h *HistoryMerger) Close(installationId int) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed == nil {
+		h.closed = map[int]bool{}
+	}
+	h.closed[installationId] = true
+}
+
+// Closed returns true if series of installation was closed with Close and no new windows were added since then
+func (h *HistoryMerger) Closed(installationId int) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.closed[installationId]
+}
+
+// Latest returns the latest window of installation, false is returned if there is none
+func (h *HistoryMerger) Latest(installationId int) (Window, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	var latest Window
+	for w := range h.history[installationId] {
+		if w.End.After(latest.End) {
+			latest = w
+		}
+	}
+	return latest, !latest.End.IsZero()
+}
+
+// mergerJSON is JSON representation of HistoryMerger
+type mergerJSON struct {
+	History map[int][]Measurement `json:"history"`
+	Closed  []int                 `json:"closed,omitempty"`
+}
+
+// MarshalJSON encodes history of all installations in chronological order and IDs of closed series
+func (h *HistoryMerger) MarshalJSON() ([]byte, error) {
+	h.mu.Lock()
+	v := mergerJSON{History: map[int][]Measurement{}}
+	ids := make([]int, 0, len(h.history))
+	for id := range h.history {
+		ids = append(ids, id)
+	}
+	for id := range h.closed {
+		v.Closed = append(v.Closed, id)
+	}
+	h.mu.Unlock()
+	for _, id := range ids {
+		v.History[id] = h.History(id)
+	}
+	sort.Ints(v.Closed)
+	return json.Marshal(v)
+}
+
+// UnmarshalJSON restores HistoryMerger encoded with MarshalJSON, replacing its content
+func (h *HistoryMerger) UnmarshalJSON(data []byte) error {
+	var v mergerJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.history = map[int]map[Window]Measurement{}
+	h.closed = map[int]bool{}
+	for id, measurements := range v.History {
+		history := map[Window]Measurement{}
+		for _, m := range measurements {
+			history[windowKey(m.Window())] = m
+		}
+		h.history[id] = history
+	}
+	for _, id := range v.Closed {
+		h.closed[id] = true
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package airly
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestHistoryMerger(t *testing.T) {
+	h := func(hour int) time.Time {
+		return time.Date(2021, 3, 1, hour, 0, 0, 0, time.UTC)
+	}
+	merger := HistoryMerger{}
+	assert.Equal(t, 2, merger.Add(204, Measurements{
+		History: []Measurement{window(h(9), PM25, 10), window(h(10), PM25, 12)},
+		Current: window(h(11), PM25, 15),
+	}))
+	warsaw := time.FixedZone("CET", 3600)
+	revised := window(h(10).In(warsaw), PM25, 13)
+	assert.Equal(t, 1, merger.Add(204, Measurements{
+		History: []Measurement{revised, window(h(11), PM25, 16)},
+	}))
+	assert.Equal(t, 0, merger.Add(204, Measurements{History: []Measurement{window(h(11), PM25, 16)}}))
+	assert.Equal(t, 1, merger.Add(204, Measurements{History: []Measurement{window(h(14), PM25, 20)}}))
+
+	assert.Equal(t, []Measurement{window(h(9), PM25, 10), revised, window(h(11), PM25, 16), window(h(14), PM25, 20)},
+		merger.History(204))
+	assert.Equal(t, []Window{{Start: h(12), End: h(14)}}, merger.Gaps(204))
+	assert.Empty(t, merger.History(8077))
+	assert.Empty(t, merger.Gaps(8077))
+}
+
+func TestHistoryMergerMaxAge(t *testing.T) {
+	h := func(hour int) time.Time {
+		return time.Date(2021, 3, 1, hour, 0, 0, 0, time.UTC)
+	}
+	merger := HistoryMerger{MaxAge: 2 * time.Hour}
+	merger.Add(204, Measurements{History: []Measurement{window(h(8), PM25, 1), window(h(9), PM25, 2)}})
+	merger.Add(204, Measurements{History: []Measurement{window(h(10), PM25, 3), window(h(11), PM25, 4)}})
+	assert.Equal(t, []Measurement{window(h(9), PM25, 2), window(h(10), PM25, 3), window(h(11), PM25, 4)},
+		merger.History(204))
+}
+
+func TestHistoryMergerClose(t *testing.T) {
+	h := func(hour int) time.Time {
+		return time.Date(2021, 3, 1, hour, 0, 0, 0, time.UTC)
+	}
+	merger := HistoryMerger{}
+	merger.Add(204, Measurements{History: []Measurement{window(h(8), PM25, 1)}})
+	assert.False(t, merger.Closed(204))
+	merger.Close(204)
+	assert.True(t, merger.Closed(204))
+	assert.False(t, merger.Closed(8077))
+
+	merger.Add(204, Measurements{History: []Measurement{window(h(8), PM25, 1)}})
+	assert.True(t, merger.Closed(204))
+	merger.Add(204, Measurements{History: []Measurement{window(h(9), PM25, 2)}})
+	assert.False(t, merger.Closed(204))
+}
+
+func TestHistoryMergerJSON(t *testing.T) {
+	h := func(hour int) time.Time {
+		return time.Date(2021, 3, 1, hour, 0, 0, 0, time.UTC)
+	}
+	merger := &HistoryMerger{}
+	_, ok := merger.Latest(204)
+	assert.False(t, ok)
+	merger.Add(204, Measurements{History: []Measurement{window(h(9), PM25, 2), window(h(8), PM25, 1)}})
+	merger.Add(8077, Measurements{History: []Measurement{window(h(8), PM25, 3)}})
+	merger.Close(8077)
+	latest, ok := merger.Latest(204)
+	assert.True(t, ok)
+	assert.Equal(t, Window{Start: h(9), End: h(10)}, latest)
+
+	data, err := json.Marshal(merger)
+	assert.NoError(t, err)
+	restored := &HistoryMerger{}
+	assert.NoError(t, json.Unmarshal(data, restored))
+	assert.Equal(t, []Measurement{window(h(8), PM25, 1), window(h(9), PM25, 2)}, restored.History(204))
+	assert.Equal(t, []Measurement{window(h(8), PM25, 3)}, restored.History(8077))
+	assert.True(t, restored.Closed(8077))
+	assert.False(t, restored.Closed(204))
+	assert.Equal(t, 0, restored.Add(204, Measurements{History: []Measurement{window(h(9), PM25, 2)}}))
+}
@@ -0,0 +1,87 @@
+package airly
+
+import (
+	"sync"
+)
+
+// OfficialIndexTypes are Airly index and the official ones, CAQI used in Europe and PIJP, Polish air quality index
+var OfficialIndexTypes = []string{"AIRLY_CAQI", "CAQI", "PIJP"}
+
+// MeasurementsWithIndexes fetches the same measurements with each of index types (OfficialIndexTypes if none are
+// given) concurrently and returns them merged, so indexes of all types are side by side in Indexes of every
+// measurement. Values are taken from the first index type. fetch is usually a closure calling one of measurements
+// methods, e.g.
+//
+//	m, err := airly.MeasurementsWithIndexes(func(o airly.IndexTypeOption) (airly.Measurements, error) {
+//		return client.InstallationMeasurements(204, o)
+//	})
+//
+// API returns only one index type per request, so each type costs one request, unless responses are cached by
+// HttpClient. The first error is returned
+func MeasurementsWithIndexes(fetch func(IndexTypeOption) (Measurements, error), indexTypes ...string) (Measurements, error) {
+	if len(indexTypes) == 0 {
+		indexTypes = OfficialIndexTypes
+	}
+	results := make([]Measurements, len(indexTypes))
+	errs := make([]error, len(indexTypes))
+	var wg sync.WaitGroup
+	for i, t := range indexTypes {
+		wg.Add(1)
+		go func(i int, t string) {
+			defer wg.Done()
+			results[i], errs[i] = fetch(WithIndexType(t))
+		}(i, t)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return Measurements{}, err
+		}
+	}
+
+	m := results[0]
+	m.Current = mergeIndexes(m.Current, results[1:], func(o Measurements) []Measurement { return []Measurement{o.Current} })
+	m.History = mergeAllIndexes(m.History, results[1:], func(o Measurements) []Measurement { return o.History })
+	m.Forecast = mergeAllIndexes(m.Forecast, results[1:], func(o Measurements) []Measurement { return o.Forecast })
+	return m, nil
+}
+
+func mergeAllIndexes(measurements []Measurement, others []Measurements, series func(Measurements) []Measurement) []Measurement {
+	if measurements == nil {
+		return nil
+	}
+	merged := make([]Measurement, len(measurements))
+	for i, measurement := range measurements {
+		merged[i] = mergeIndexes(measurement, others, series)
+	}
+	return merged
+}
+
+// mergeIndexes returns copy of measurement with indexes of measurements with the same window from others added
+func mergeIndexes(measurement Measurement, others []Measurements, series func(Measurements) []Measurement) Measurement {
+	indexes := append([]Index{}, measurement.Indexes...)
+	k := windowKey(measurement.Window())
+	for _, o := range others {
+		for _, other := range series(o) {
+			if windowKey(other.Window()) != k {
+				continue
+			}
+			for _, index := range other.Indexes {
+				if !hasIndex(indexes, index.Name) {
+					indexes = append(indexes, index)
+				}
+			}
+		}
+	}
+	measurement.Indexes = indexes
+	return measurement
+}
+
+func hasIndex(indexes []Index, name string) bool {
+	for _, i := range indexes {
+		if i.Name == name {
+			return true
+		}
+	}
+	return false
+}
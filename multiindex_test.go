@@ -0,0 +1,61 @@
+package airly
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestMeasurementsWithIndexes(t *testing.T) {
+	h := func(hour int) time.Time {
+		return time.Date(2021, 3, 1, hour, 0, 0, 0, time.UTC)
+	}
+	measurement := func(hour int, index Index) Measurement {
+		return Measurement{FromDateTime: h(hour), TillDateTime: h(hour + 1),
+			Values: []Value{{Name: PM25, Value: 18.7}}, Indexes: []Index{index}}
+	}
+	var mu sync.Mutex
+	var requested []string
+	api := Client{RequestID: func() string { return "1" }, HttpClient: mockClient{func(req *http.Request) (*http.Response, error) {
+		mu.Lock()
+		requested = append(requested, req.URL.Query().Get("indexType"))
+		mu.Unlock()
+		switch req.URL.Query().Get("indexType") {
+		case "AIRLY_CAQI":
+			return &http.Response{StatusCode: 200, Body: readCloser(`{"current": {"fromDateTime": "2021-03-01T10:00:00Z",
+				"tillDateTime": "2021-03-01T11:00:00Z", "values": [{"name": "PM25", "value": 18.7}],
+				"indexes": [{"name": "AIRLY_CAQI", "value": 35.53, "level": "LOW"}]},
+				"forecast": [{"fromDateTime": "2021-03-01T11:00:00Z", "tillDateTime": "2021-03-01T12:00:00Z",
+				"values": [{"name": "PM25", "value": 18.7}], "indexes": [{"name": "AIRLY_CAQI", "value": 40, "level": "LOW"}]}]}`)}, nil
+		case "PIJP":
+			return &http.Response{StatusCode: 200, Body: readCloser(`{"current": {"fromDateTime": "2021-03-01T10:00:00Z",
+				"tillDateTime": "2021-03-01T11:00:00Z", "values": [{"name": "PM25", "value": 18.7}],
+				"indexes": [{"name": "PIJP", "value": 2, "level": "MEDIUM"}]},
+				"forecast": [{"fromDateTime": "2021-03-01T12:00:00Z", "tillDateTime": "2021-03-01T13:00:00Z",
+				"values": [], "indexes": [{"name": "PIJP", "value": 3, "level": "HIGH"}]}]}`)}, nil
+		}
+		return &http.Response{StatusCode: 400, Body: readCloser(`{"errorCode": "INDEX_TYPE_NOT_FOUND"}`)}, nil
+	}}}
+	fetch := func(o IndexTypeOption) (Measurements, error) {
+		return api.InstallationMeasurements(204, o)
+	}
+
+	m, err := MeasurementsWithIndexes(fetch, "AIRLY_CAQI", "PIJP")
+	assert.NoError(t, err)
+	sort.Strings(requested)
+	assert.Equal(t, []string{"AIRLY_CAQI", "PIJP"}, requested)
+	current := measurement(10, Index{Name: "AIRLY_CAQI", Value: 35.53, Level: "LOW"})
+	current.Indexes = append(current.Indexes, Index{Name: "PIJP", Value: 2, Level: "MEDIUM"})
+	assert.Equal(t, Measurements{
+		Current:  current,
+		Forecast: []Measurement{measurement(11, Index{Name: "AIRLY_CAQI", Value: 40, Level: "LOW"})},
+	}, m)
+
+	_, err = MeasurementsWithIndexes(fetch)
+	var apiErr *APIError
+	assert.True(t, errors.As(err, &apiErr))
+}
@@ -0,0 +1,160 @@
+package airly
+
+import (
+	"fmt"
+	"github.com/probakowski/go-airly/units"
+	"strings"
+	"time"
+)
+
+// Canonical value names, the same as used by Airly
+const (
+	PM1         = "PM1"
+	PM25        = "PM25"
+	PM10        = "PM10"
+	NO2         = "NO2"
+	O3          = "O3"
+	SO2         = "SO2"
+	CO          = "CO"
+	C6H6        = "C6H6"
+	Temperature = "TEMPERATURE"
+	Humidity    = "HUMIDITY"
+	Pressure    = "PRESSURE"
+)
+
+// aliases maps lowercase names used by other sources to canonical names
+var aliases = map[string]string{
+	"pm1.0":             PM1,
+	"p0":                PM1,
+	"pm2.5":             PM25,
+	"pm2_5":             PM25,
+	"p2":                PM25,
+	"p1":                PM10,
+	"temp":              Temperature,
+	"rh":                Humidity,
+	"relative_humidity": Humidity,
+}
+
+// CanonicalName returns canonical name of value reported under given name, names are case-insensitive.
+// Unknown names are returned in upper case
+func CanonicalName(name string) string {
+	if canonical, ok := aliases[strings.ToLower(name)]; ok {
+		return canonical
+	}
+	return strings.ToUpper(name)
+}
+
+// Normalizer converts measurements from any source to canonical value names and units used by Airly:
+// µg/m³ for pollutants, °C for temperature, % for humidity and hPa for pressure
+type Normalizer struct {
+	// Units of values reported by source keyed by canonical value name, values without unit are not converted.
+	// Supported units are µg/m³, mg/m³, ppb, ppm (gases only), Pa, kPa, hPa, °F, K and °C
+	Units map[string]string
+	// AveragingPeriod is used as measurement window for instantaneous readings (with FromDateTime equal to
+	// TillDateTime), so they can be compared with averaged values
+	AveragingPeriod time.Duration
+}
+
+// Measurements returns normalized copy of measurements
+func (n Normalizer) Measurements(m Measurements) (Measurements, error) {
+	var err error
+	if m.Current, err = n.Measurement(m.Current); err != nil {
+		return Measurements{}, err
+	}
+	for _, s := range []*[]Measurement{&m.History, &m.Forecast} {
+		if *s == nil {
+			continue
+		}
+		normalized := make([]Measurement, len(*s))
+		for i, measurement := range *s {
+			if normalized[i], err = n.Measurement(measurement); err != nil {
+				return Measurements{}, err
+			}
+		}
+		*s = normalized
+	}
+	return m, nil
+}
+
+// Measurement returns normalized copy of measurement
+func (n Normalizer) Measurement(m Measurement) (Measurement, error) {
+	if m.FromDateTime.Equal(m.TillDateTime) && n.AveragingPeriod > 0 {
+		m.FromDateTime = m.TillDateTime.Add(-n.AveragingPeriod)
+	}
+	if m.Values == nil {
+		return m, nil
+	}
+	values := make([]Value, len(m.Values))
+	for i, v := range m.Values {
+		name := CanonicalName(v.Name)
+		if v.Null {
+			values[i] = Value{Name: name, Null: true}
+			continue
+		}
+		value, err := convert(name, v.Value, n.Units[name])
+		if err != nil {
+			return Measurement{}, err
+		}
+		values[i] = Value{Name: name, Value: value}
+	}
+	m.Values = values
+	return m, nil
+}
+
+// convert value of named pollutant from unit to canonical unit
+func convert(name string, value float64, unit string) (float64, error) {
+	switch strings.ToLower(unit) {
+	case "", "µg/m³", "µg/m3", "ug/m3", "hpa", "°c", "c", "%":
+		return value, nil
+	case "mg/m³", "mg/m3":
+		return value * 1000, nil
+	case "pa":
+		return value / 100, nil
+	case "kpa":
+		return value * 10, nil
+	case "°f", "f":
+		return units.FahrenheitToCelsius(value), nil
+	case "k":
+		return value - 273.15, nil
+	case "ppb", "ppm":
+		if _, ok := units.MolarMass(name); !ok {
+			return 0, fmt.Errorf("cannot convert %s from %s, molar mass unknown", name, unit)
+		}
+		if strings.ToLower(unit) == "ppm" {
+			return units.FromPPM(name, value)
+		}
+		return units.FromPPB(name, value)
+	}
+	return 0, fmt.Errorf("cannot convert %s from unknown unit %s", name, unit)
+}
+
+// NormalizedProvider normalizes measurements returned by wrapped provider, installations are returned as they are
+type NormalizedProvider struct {
+	Provider   AirQualityProvider
+	Normalizer Normalizer
+}
+
+var _ AirQualityProvider = NormalizedProvider{}
+
+// NearestInstallations returns installations of wrapped provider
+func (p NormalizedProvider) NearestInstallations(loc Location, options ...NearestInstallationsOption) ([]Installation, error) {
+	return p.Provider.NearestInstallations(loc, options...)
+}
+
+// NearestMeasurements returns normalized measurements of wrapped provider
+func (p NormalizedProvider) NearestMeasurements(loc Location, options ...NearestMeasurementsOption) (Measurements, error) {
+	m, err := p.Provider.NearestMeasurements(loc, options...)
+	if err != nil {
+		return m, err
+	}
+	return p.Normalizer.Measurements(m)
+}
+
+// PointMeasurements returns normalized measurements of wrapped provider
+func (p NormalizedProvider) PointMeasurements(loc Location, options ...MeasurementsOption) (Measurements, error) {
+	m, err := p.Provider.PointMeasurements(loc, options...)
+	if err != nil {
+		return m, err
+	}
+	return p.Normalizer.Measurements(m)
+}
@@ -0,0 +1,84 @@
+package airly
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestCanonicalName(t *testing.T) {
+	assert.Equal(t, PM25, CanonicalName("PM2.5"))
+	assert.Equal(t, PM25, CanonicalName("pm25"))
+	assert.Equal(t, PM10, CanonicalName("P1"))
+	assert.Equal(t, Humidity, CanonicalName("rh"))
+	assert.Equal(t, "WIND_SPEED", CanonicalName("wind_speed"))
+}
+
+func TestNormalizer(t *testing.T) {
+	till := time.Date(2021, 10, 1, 12, 0, 0, 0, time.UTC)
+	n := Normalizer{
+		Units:           map[string]string{NO2: "ppb", CO: "mg/m³", Pressure: "Pa", Temperature: "°F"},
+		AveragingPeriod: 5 * time.Minute,
+	}
+	history := []Measurement{{Values: []Value{{Name: "co", Value: 0.5}}}}
+	m, err := n.Measurements(Measurements{
+		Current: Measurement{
+			FromDateTime: till,
+			TillDateTime: till,
+			Values: []Value{
+				{Name: "PM2.5", Value: 10},
+				{Name: "no2", Value: 24.45},
+				{Name: "pressure", Value: 101325},
+				{Name: "temp", Value: 50},
+			},
+		},
+		History: history,
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, till.Add(-5*time.Minute), m.Current.FromDateTime)
+	assert.Equal(t, []Value{
+		{Name: PM25, Value: 10},
+		{Name: NO2, Value: 46.0055},
+		{Name: Pressure, Value: 1013.25},
+		{Name: Temperature, Value: 10},
+	}, m.Current.Values)
+	assert.Equal(t, []Value{{Name: CO, Value: 500}}, m.History[0].Values)
+	assert.Equal(t, "co", history[0].Values[0].Name)
+	assert.Nil(t, m.Forecast)
+
+	_, err = Normalizer{Units: map[string]string{PM25: "ppb"}}.Measurement(Measurement{Values: []Value{{Name: PM25}}})
+	assert.NotNil(t, err)
+	_, err = Normalizer{Units: map[string]string{PM25: "grains"}}.Measurement(Measurement{Values: []Value{{Name: PM25}}})
+	assert.NotNil(t, err)
+}
+
+func TestNormalizedProvider(t *testing.T) {
+	p := NormalizedProvider{
+		Provider: mockProvider{
+			installations: []Installation{{Id: 1}},
+			measurements:  Measurements{Current: Measurement{Values: []Value{{Name: "P2", Value: 10}}}},
+		},
+	}
+	loc := Location{50.062006, 19.940984}
+	installations, err := p.NearestInstallations(loc)
+	assert.Nil(t, err)
+	assert.Equal(t, []Installation{{Id: 1}}, installations)
+	m, err := p.NearestMeasurements(loc)
+	assert.Nil(t, err)
+	assert.Equal(t, []Value{{Name: PM25, Value: 10}}, m.Current.Values)
+	m, err = p.PointMeasurements(loc)
+	assert.Nil(t, err)
+	assert.Equal(t, []Value{{Name: PM25, Value: 10}}, m.Current.Values)
+
+	err = errors.New("error")
+	_, err2 := NormalizedProvider{Provider: mockProvider{err: err}}.NearestMeasurements(loc)
+	assert.Equal(t, err, err2)
+}
+
+func TestNormalizeNull(t *testing.T) {
+	n := Normalizer{Units: map[string]string{NO2: "ppb"}}
+	m, err := n.Measurement(Measurement{Values: []Value{{Name: "no2", Null: true}}})
+	assert.NoError(t, err)
+	assert.Equal(t, []Value{{Name: NO2, Null: true}}, m.Values)
+}
@@ -0,0 +1,111 @@
+// Package openaq converts Airly measurements to OpenAQ measurement schema,
+// see https://docs.openaq.org/reference/measurements_get_v2_measurements_get
+package openaq
+
+import (
+	"github.com/probakowski/go-airly"
+	"strings"
+	"time"
+)
+
+// Measurement in OpenAQ format
+type Measurement struct {
+	LocationId      int             `json:"locationId"`
+	Location        string          `json:"location"`
+	Parameter       string          `json:"parameter"`
+	Value           float64         `json:"value"`
+	Date            Date            `json:"date"`
+	Unit            string          `json:"unit"`
+	AveragingPeriod AveragingPeriod `json:"averagingPeriod"`
+	Coordinates     Coordinates     `json:"coordinates"`
+	Country         string          `json:"country"`
+	City            string          `json:"city"`
+	IsMobile        bool            `json:"isMobile"`
+	SourceName      string          `json:"sourceName"`
+}
+
+// Date of measurement in UTC and local time zone
+type Date struct {
+	UTC   string `json:"utc"`
+	Local string `json:"local"`
+}
+
+// AveragingPeriod of measurement
+type AveragingPeriod struct {
+	Value float64 `json:"value"`
+	Unit  string  `json:"unit"`
+}
+
+// Coordinates of location
+type Coordinates struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+// SourceName used in converted measurements
+const SourceName = "Airly"
+
+// parameters maps Airly value names to OpenAQ parameters and units
+var parameters = map[string][2]string{
+	"PM1":         {"pm1", "µg/m³"},
+	"PM25":        {"pm25", "µg/m³"},
+	"PM10":        {"pm10", "µg/m³"},
+	"NO2":         {"no2", "µg/m³"},
+	"O3":          {"o3", "µg/m³"},
+	"SO2":         {"so2", "µg/m³"},
+	"CO":          {"co", "µg/m³"},
+	"TEMPERATURE": {"temperature", "c"},
+	"HUMIDITY":    {"humidity", "%"},
+	"PRESSURE":    {"pressure", "hpa"},
+}
+
+// Convert returns OpenAQ measurements for all values of current and historical measurements of installation.
+// Date is the end of measurement period, local date uses loc time zone (time.Local if nil).
+// Values not known to OpenAQ are converted to lowercase parameters without unit
+func Convert(installation airly.Installation, measurements airly.Measurements, loc *time.Location) []Measurement {
+	if loc == nil {
+		loc = time.Local
+	}
+	var result []Measurement
+	all := append(append([]airly.Measurement{}, measurements.History...), measurements.Current)
+	for _, m := range all {
+		date := Date{
+			UTC:   m.TillDateTime.UTC().Format(time.RFC3339),
+			Local: m.TillDateTime.In(loc).Format(time.RFC3339),
+		}
+		period := AveragingPeriod{Value: m.TillDateTime.Sub(m.FromDateTime).Hours(), Unit: "hours"}
+		for _, v := range m.Values {
+			if v.Null {
+				continue
+			}
+			parameter, ok := parameters[v.Name]
+			if !ok {
+				parameter = [2]string{strings.ToLower(v.Name), ""}
+			}
+			result = append(result, Measurement{
+				LocationId:      installation.Id,
+				Location:        location(installation.Address),
+				Parameter:       parameter[0],
+				Value:           v.Value,
+				Date:            date,
+				Unit:            parameter[1],
+				AveragingPeriod: period,
+				Coordinates:     Coordinates(installation.Location),
+				Country:         installation.Address.Country,
+				City:            installation.Address.City,
+				SourceName:      SourceName,
+			})
+		}
+	}
+	return result
+}
+
+func location(a airly.Address) string {
+	if a.DisplayAddress2 == "" {
+		return a.DisplayAddress1
+	}
+	if a.DisplayAddress1 == "" {
+		return a.DisplayAddress2
+	}
+	return a.DisplayAddress1 + ", " + a.DisplayAddress2
+}
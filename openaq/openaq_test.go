@@ -0,0 +1,59 @@
+package openaq
+
+import (
+	"github.com/probakowski/go-airly"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestConvert(t *testing.T) {
+	installation := airly.Installation{
+		Id:       204,
+		Location: airly.Location{Latitude: 50.062006, Longitude: 19.940984},
+		Address: airly.Address{
+			Country:         "Poland",
+			City:            "Kraków",
+			DisplayAddress1: "Kraków",
+			DisplayAddress2: "Mikołajska",
+		},
+	}
+	from := time.Date(2021, 10, 1, 10, 0, 0, 0, time.UTC)
+	measurements := airly.Measurements{
+		Current: airly.Measurement{
+			FromDateTime: from.Add(time.Hour),
+			TillDateTime: from.Add(2 * time.Hour),
+			Values:       []airly.Value{{Name: "PM25", Value: 18.7}, {Name: "WIND_SPEED", Value: 3}},
+		},
+		History: []airly.Measurement{{
+			FromDateTime: from,
+			TillDateTime: from.Add(time.Hour),
+			Values:       []airly.Value{{Name: "TEMPERATURE", Value: 12.5}},
+		}},
+	}
+	expected := func(parameter string, value float64, unit, utc, local string) Measurement {
+		return Measurement{
+			LocationId:      204,
+			Location:        "Kraków, Mikołajska",
+			Parameter:       parameter,
+			Value:           value,
+			Date:            Date{UTC: utc, Local: local},
+			Unit:            unit,
+			AveragingPeriod: AveragingPeriod{Value: 1, Unit: "hours"},
+			Coordinates:     Coordinates{Latitude: 50.062006, Longitude: 19.940984},
+			Country:         "Poland",
+			City:            "Kraków",
+			SourceName:      "Airly",
+		}
+	}
+	assert.Equal(t, []Measurement{
+		expected("temperature", 12.5, "c", "2021-10-01T11:00:00Z", "2021-10-01T13:00:00+02:00"),
+		expected("pm25", 18.7, "µg/m³", "2021-10-01T12:00:00Z", "2021-10-01T14:00:00+02:00"),
+		expected("wind_speed", 3, "", "2021-10-01T12:00:00Z", "2021-10-01T14:00:00+02:00"),
+	}, Convert(installation, measurements, time.FixedZone("CEST", 2*3600)))
+}
+
+func TestLocation(t *testing.T) {
+	assert.Equal(t, "Kraków", location(airly.Address{DisplayAddress1: "Kraków"}))
+	assert.Equal(t, "Mikołajska", location(airly.Address{DisplayAddress2: "Mikołajska"}))
+}
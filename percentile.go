@@ -0,0 +1,96 @@
+package airly
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// within returns true if measurement starts within w, zero window contains all measurements
+func within(w Window, m Measurement) bool {
+	return w == (Window{}) || w.Contains(m.FromDateTime)
+}
+
+// Percentiles returns percentiles ps (from 0 to 100) of values with given name of measurements starting within w,
+// e.g. series from HistoryMerger. Values between closest ranks are interpolated linearly, Null values are skipped.
+// Nil is returned if there are no values
+func Percentiles(measurements []Measurement, name string, w Window, ps ...float64) []float64 {
+	var values []float64
+	for _, m := range measurements {
+		if v, ok := m.Value(name); ok && within(w, m) {
+			values = append(values, v)
+		}
+	}
+	if len(values) == 0 {
+		return nil
+	}
+	sort.Float64s(values)
+	result := make([]float64, len(ps))
+	for i, p := range ps {
+		rank := math.Max(0, math.Min(p, 100)) / 100 * float64(len(values)-1)
+		lower := int(math.Floor(rank))
+		upper := int(math.Ceil(rank))
+		result[i] = values[lower] + (values[upper]-values[lower])*(rank-float64(lower))
+	}
+	return result
+}
+
+// LevelDurations returns total duration of measurements starting within w per level of index with given name, e.g.
+// number of hours of history with HIGH AIRLY_CAQI
+func LevelDurations(measurements []Measurement, index string, w Window) map[string]time.Duration {
+	durations := map[string]time.Duration{}
+	for _, m := range measurements {
+		if !within(w, m) {
+			continue
+		}
+		for _, i := range m.Indexes {
+			if i.Name == index && i.Level != "" {
+				durations[i.Level] += m.Window().Duration()
+			}
+		}
+	}
+	return durations
+}
+
+// DailySummary of values with one name in one day
+type DailySummary struct {
+	// Day of measurements start, YYYY-MM-DD
+	Day   string  `json:"day"`
+	Name  string  `json:"name"`
+	Min   float64 `json:"min"`
+	Max   float64 `json:"max"`
+	Avg   float64 `json:"avg"`
+	Count int     `json:"count"`
+}
+
+// DailySummaries returns minimum, maximum and average of values with given name per day in time zone loc (UTC if
+// nil) in chronological order, Null values are skipped
+func DailySummaries(measurements []Measurement, name string, loc *time.Location) []DailySummary {
+	if loc == nil {
+		loc = time.UTC
+	}
+	var summaries []DailySummary
+	days := map[string]int{}
+	for _, m := range measurements {
+		v, ok := m.Value(name)
+		if !ok {
+			continue
+		}
+		day := m.FromDateTime.In(loc).Format("2006-01-02")
+		i, ok := days[day]
+		if !ok {
+			i = len(summaries)
+			days[day] = i
+			summaries = append(summaries, DailySummary{Day: day, Name: name, Min: v, Max: v})
+		}
+		s := &summaries[i]
+		s.Min = math.Min(s.Min, v)
+		s.Max = math.Max(s.Max, v)
+		s.Avg += (v - s.Avg) / float64(s.Count+1)
+		s.Count++
+	}
+	sort.SliceStable(summaries, func(i, j int) bool {
+		return summaries[i].Day < summaries[j].Day
+	})
+	return summaries
+}
@@ -0,0 +1,61 @@
+package airly
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestPercentiles(t *testing.T) {
+	h := func(hour int) time.Time {
+		return time.Date(2021, 3, 1, hour, 0, 0, 0, time.UTC)
+	}
+	var series []Measurement
+	for i := 0; i < 11; i++ {
+		series = append(series, window(h(i), PM25, float64(10-i)*10))
+	}
+	series = append(series, Measurement{FromDateTime: h(11), TillDateTime: h(12), Values: []Value{{Name: PM25, Null: true}}})
+
+	assert.Equal(t, []float64{50, 90, 99, 0, 100}, Percentiles(series, PM25, Window{}, 50, 90, 99, 0, 100))
+	assert.Equal(t, []float64{95}, Percentiles(series, PM25, Window{Start: h(0), End: h(2)}, 50))
+	assert.Nil(t, Percentiles(series, PM10, Window{}, 50))
+	assert.Nil(t, Percentiles(series, PM25, Window{Start: h(11), End: h(12)}, 50))
+}
+
+func TestLevelDurations(t *testing.T) {
+	h := func(hour int) time.Time {
+		return time.Date(2021, 3, 1, hour, 0, 0, 0, time.UTC)
+	}
+	measurement := func(hour int, level string) Measurement {
+		return Measurement{FromDateTime: h(hour), TillDateTime: h(hour + 1),
+			Indexes: []Index{{Name: "AIRLY_CAQI", Level: level}, {Name: "PIJP", Level: "LOW"}}}
+	}
+	series := []Measurement{measurement(0, "LOW"), measurement(1, "HIGH"), measurement(2, "HIGH"), measurement(3, "")}
+	assert.Equal(t, map[string]time.Duration{"LOW": time.Hour, "HIGH": 2 * time.Hour},
+		LevelDurations(series, "AIRLY_CAQI", Window{}))
+	assert.Equal(t, map[string]time.Duration{"HIGH": time.Hour},
+		LevelDurations(series, "AIRLY_CAQI", Window{Start: h(2), End: h(4)}))
+}
+
+func TestDailySummaries(t *testing.T) {
+	warsaw := time.FixedZone("CET", 3600)
+	at := func(day, hour int) time.Time {
+		return time.Date(2021, 3, day, hour, 0, 0, 0, time.UTC)
+	}
+	series := []Measurement{
+		window(at(2, 1), PM25, 30),
+		window(at(1, 22), PM25, 10),
+		window(at(1, 23), PM25, 20),
+		{FromDateTime: at(2, 2), TillDateTime: at(2, 3), Values: []Value{{Name: PM25, Null: true}}},
+		window(at(2, 3), PM10, 50),
+	}
+	assert.Equal(t, []DailySummary{
+		{Day: "2021-03-01", Name: PM25, Min: 10, Max: 20, Avg: 15, Count: 2},
+		{Day: "2021-03-02", Name: PM25, Min: 30, Max: 30, Avg: 30, Count: 1},
+	}, DailySummaries(series, PM25, nil))
+	assert.Equal(t, []DailySummary{
+		{Day: "2021-03-01", Name: PM25, Min: 10, Max: 10, Avg: 10, Count: 1},
+		{Day: "2021-03-02", Name: PM25, Min: 20, Max: 30, Avg: 25, Count: 2},
+	}, DailySummaries(series, PM25, warsaw))
+	assert.Empty(t, DailySummaries(series, NO2, nil))
+}
@@ -0,0 +1,93 @@
+package airly
+
+import (
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+)
+
+// AirQualityProvider is a source of installations and measurements. It's implemented by Client, so applications
+// can code against it and use other data sources as well
+type AirQualityProvider interface {
+	// NearestInstallations returns installations near specified point, see Client.NearestInstallations
+	NearestInstallations(loc Location, options ...NearestInstallationsOption) ([]Installation, error)
+	// NearestMeasurements returns measurements from installation closest to location, see Client.NearestMeasurements
+	NearestMeasurements(loc Location, options ...NearestMeasurementsOption) (Measurements, error)
+	// PointMeasurements returns measurements interpolated for any location, see Client.PointMeasurements
+	PointMeasurements(loc Location, options ...MeasurementsOption) (Measurements, error)
+}
+
+var _ AirQualityProvider = Client{}
+
+// Options are values set by options with defaults applied, to be used by AirQualityProvider implementations
+type Options struct {
+	MaxDistance    float64
+	MaxResults     int
+	IndexType      string
+	IndexPollutant string
+}
+
+// ResolveOptions applies options of NearestInstallations to defaults
+func ResolveOptions(options ...NearestInstallationsOption) Options {
+	return newNearestInstallationsConfig(options).options()
+}
+
+// ResolveNearestMeasurementsOptions applies options of NearestMeasurements to defaults
+func ResolveNearestMeasurementsOptions(options ...NearestMeasurementsOption) Options {
+	return newNearestMeasurementsConfig(options).options()
+}
+
+// ResolveMeasurementsOptions applies options of PointMeasurements and InstallationMeasurements to defaults
+func ResolveMeasurementsOptions(options ...MeasurementsOption) Options {
+	return newMeasurementsConfig(options).options()
+}
+
+func (c requestConfig) options() Options {
+	return Options{
+		MaxDistance:    c.maxDistance,
+		MaxResults:     c.maxResults,
+		IndexType:      c.indexType,
+		IndexPollutant: c.indexPollutant,
+	}
+}
+
+// GetJSON sends GET request for url with client and decodes JSON response into v, it's meant for AirQualityProvider
+// implementations calling other APIs. http.Client with DefaultTimeout is used if client is nil. Responses larger than
+// maxSize bytes (DefaultMaxResponseSize if 0, no limit if negative) fail with *ResponseTooLargeError, responses with
+// status other than 200 with *APIError
+func GetJSON(client HttpClient, url string, maxSize int64, v interface{}) error {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if client == nil {
+		client = defaultHttpClient
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if maxSize == 0 {
+		maxSize = DefaultMaxResponseSize
+	}
+	body := io.Reader(res.Body)
+	if maxSize > 0 {
+		body = io.LimitReader(res.Body, maxSize+1)
+	}
+	data, err := ioutil.ReadAll(body)
+	if err != nil {
+		return err
+	}
+	if maxSize > 0 && int64(len(data)) > maxSize {
+		return &ResponseTooLargeError{Limit: maxSize}
+	}
+
+	if res.StatusCode != 200 {
+		return &APIError{StatusCode: res.StatusCode, Body: string(data), RetryAfter: retryAfter(res)}
+	}
+	return json.Unmarshal(data, v)
+}
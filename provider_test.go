@@ -0,0 +1,58 @@
+package airly
+
+import (
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+func TestResolveOptions(t *testing.T) {
+	assert.Equal(t, Options{MaxDistance: 3, MaxResults: 1}, ResolveOptions())
+	assert.Equal(t, Options{MaxDistance: 5, MaxResults: -1},
+		ResolveOptions(MaxDistance(5), MaxResults(-1)))
+	assert.Equal(t, Options{MaxDistance: 5, MaxResults: 1, IndexType: "PIJP"},
+		ResolveNearestMeasurementsOptions(MaxDistance(5), WithIndexType("PIJP")))
+	assert.Equal(t, Options{MaxDistance: 3, MaxResults: 1, IndexType: "CAQI"},
+		ResolveMeasurementsOptions(WithIndexType("CAQI")))
+}
+
+func TestOptionsPerEndpoint(t *testing.T) {
+	for _, o := range []interface{}{MaxDistance(1), MaxResults(1), AllResults()} {
+		assert.Implements(t, (*NearestInstallationsOption)(nil), o)
+	}
+	for _, o := range []interface{}{MaxDistance(1), WithIndexType("CAQI")} {
+		assert.Implements(t, (*NearestMeasurementsOption)(nil), o)
+	}
+	assert.Implements(t, (*MeasurementsOption)(nil), WithIndexType("CAQI"))
+
+	_, ok := interface{}(MaxResults(1)).(NearestMeasurementsOption)
+	assert.False(t, ok, "MaxResults is ignored by NearestMeasurements")
+	_, ok = interface{}(WithIndexType("CAQI")).(NearestInstallationsOption)
+	assert.False(t, ok, "index type is ignored by NearestInstallations")
+	_, ok = interface{}(MaxDistance(1)).(MeasurementsOption)
+	assert.False(t, ok, "distance is ignored by PointMeasurements")
+}
+
+func TestGetJSON(t *testing.T) {
+	var requested string
+	client := mockClient{func(req *http.Request) (*http.Response, error) {
+		requested = req.URL.String()
+		assert.Equal(t, "application/json", req.Header.Get("Accept"))
+		if req.URL.Path == "/missing" {
+			return &http.Response{StatusCode: 404, Body: readCloser("not found")}, nil
+		}
+		return &http.Response{StatusCode: 200, Body: readCloser(`{"id": 204}`)}, nil
+	}}
+
+	var i Installation
+	assert.NoError(t, GetJSON(client, "https://example.com/installation", 0, &i))
+	assert.Equal(t, "https://example.com/installation", requested)
+	assert.Equal(t, 204, i.Id)
+	assert.NoError(t, GetJSON(client, "https://example.com/installation", 11, &i))
+	assert.NoError(t, GetJSON(client, "https://example.com/installation", -1, &i))
+
+	err := GetJSON(client, "https://example.com/installation", 10, &i)
+	assert.Equal(t, &ResponseTooLargeError{Limit: 10}, err)
+	err = GetJSON(client, "https://example.com/missing", 0, &i)
+	assert.Equal(t, &APIError{StatusCode: 404, Body: "not found"}, err)
+}
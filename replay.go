@@ -0,0 +1,96 @@
+package airly
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ErrNotRecorded is returned (wrapped) by ReplayClient for requests without recorded response
+var ErrNotRecorded = errors.New("no recorded response")
+
+// ReplayClient is HttpClient serving responses recorded by AuditingClient with RecordBodies instead of sending
+// requests, so code using Client can be tested deterministically against real traffic. Requests are matched by
+// endpoint and parameters, the same request gets recorded responses in order and the last one is repeated
+// afterwards. ReplayClient is safe for concurrent use
+type ReplayClient struct {
+	// Entries to serve, usually read with ReadAuditLog
+	Entries []AuditEntry
+
+	mu   sync.Mutex
+	next map[string]int
+}
+
+// NewReplayClient returns ReplayClient serving audit log read from r
+func NewReplayClient(r io.Reader) (*ReplayClient, error) {
+	entries, err := ReadAuditLog(r)
+	if err != nil {
+		return nil, err
+	}
+	return &ReplayClient{Entries: entries}, nil
+}
+
+// Do returns recorded response for request
+func (c *ReplayClient) Do(req *http.Request) (*http.Response, error) {
+	key := replayKey(auditEndpoint(req.URL.Path), req.URL.Query())
+	var matching []AuditEntry
+	for _, e := range c.Entries {
+		params := url.Values{}
+		for k, v := range e.Params {
+			params.Set(k, v)
+		}
+		if replayKey(e.Endpoint, params) == key {
+			matching = append(matching, e)
+		}
+	}
+	if len(matching) == 0 {
+		return nil, fmt.Errorf("%w for %s", ErrNotRecorded, key)
+	}
+
+	c.mu.Lock()
+	if c.next == nil {
+		c.next = map[string]int{}
+	}
+	i := c.next[key]
+	if i < len(matching)-1 {
+		c.next[key] = i + 1
+	}
+	c.mu.Unlock()
+
+	e := matching[i]
+	if e.Error != "" {
+		return nil, errors.New(e.Error)
+	}
+	header := http.Header{"Content-Type": {"application/json"}}
+	if e.RequestID != "" {
+		header.Set(RequestIDHeader, e.RequestID)
+	}
+	if r := e.RateLimit; r != nil {
+		header.Set("X-RateLimit-Limit-day", strconv.Itoa(r.DayLimit))
+		header.Set("X-RateLimit-Remaining-day", strconv.Itoa(r.DayRemaining))
+		header.Set("X-RateLimit-Limit-minute", strconv.Itoa(r.MinuteLimit))
+		header.Set("X-RateLimit-Remaining-minute", strconv.Itoa(r.MinuteRemaining))
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", e.Status, http.StatusText(e.Status)),
+		StatusCode:    e.Status,
+		Header:        header,
+		Body:          ioutil.NopCloser(strings.NewReader(e.Body)),
+		ContentLength: int64(len(e.Body)),
+		Request:       req,
+	}, nil
+}
+
+// replayKey identifies request by endpoint and canonically encoded parameters
+func replayKey(endpoint string, params url.Values) string {
+	if len(params) == 0 {
+		return endpoint
+	}
+	return endpoint + "?" + params.Encode()
+}
@@ -0,0 +1,76 @@
+package airly
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+func TestReplayClient(t *testing.T) {
+	var log bytes.Buffer
+	calls := 0
+	recording := Client{HttpClient: &AuditingClient{
+		Log:          &log,
+		RecordBodies: true,
+		HttpClient: mockClient{func(req *http.Request) (*http.Response, error) {
+			calls++
+			switch req.URL.Path {
+			case "/v2/installations/204":
+				return &http.Response{StatusCode: 200, Body: readCloser(`{"id": 204, "elevation": 220.38}`)}, nil
+			case "/v2/installations/911":
+				return nil, errors.New("connection refused")
+			}
+			header := http.Header{}
+			header.Set("X-RateLimit-Limit-day", "100")
+			header.Set("X-RateLimit-Remaining-day", "42")
+			return &http.Response{StatusCode: 200, Header: header,
+				Body: readCloser(fmt.Sprintf(`{"current": {"values": [{"name": "PM25", "value": %d}]}}`, calls))}, nil
+		}},
+	}}
+	installation, err := recording.Installation(204)
+	assert.NoError(t, err)
+	m1, err := recording.InstallationMeasurements(204, WithIndexType("CAQI"))
+	assert.NoError(t, err)
+	m2, err := recording.InstallationMeasurements(204, WithIndexType("CAQI"))
+	assert.NoError(t, err)
+	assert.NotEqual(t, m1, m2)
+	_, err = recording.Installation(911)
+	assert.Error(t, err)
+
+	replay, err := NewReplayClient(&log)
+	assert.NoError(t, err)
+	api := Client{HttpClient: replay}
+	i, err := api.Installation(204)
+	assert.NoError(t, err)
+	assert.Equal(t, installation, i)
+	m, err := api.InstallationMeasurements(204, WithIndexType("CAQI"))
+	assert.NoError(t, err)
+	assert.Equal(t, m1, m)
+	m, err = api.InstallationMeasurements(204, WithIndexType("CAQI"))
+	assert.NoError(t, err)
+	assert.Equal(t, m2, m)
+	m, err = api.InstallationMeasurements(204, WithIndexType("CAQI"))
+	assert.NoError(t, err)
+	assert.Equal(t, m2, m, "the last response should be repeated")
+
+	_, err = api.Installation(911)
+	assert.EqualError(t, errors.Unwrap(err), "connection refused")
+	_, err = api.InstallationMeasurements(204)
+	assert.ErrorIs(t, err, ErrNotRecorded)
+	assert.Equal(t, 4, calls, "replay should not send requests")
+}
+
+func TestReplayRateLimit(t *testing.T) {
+	replay := &ReplayClient{Entries: []AuditEntry{{
+		Endpoint:  "meta/indexes",
+		Status:    200,
+		Body:      `[]`,
+		RateLimit: &RateLimit{DayLimit: 100, DayRemaining: 42, MinuteLimit: 50, MinuteRemaining: 49},
+	}}}
+	rateLimit, err := Client{HttpClient: replay}.RateLimit()
+	assert.NoError(t, err)
+	assert.Equal(t, RateLimit{DayLimit: 100, DayRemaining: 42, MinuteLimit: 50, MinuteRemaining: 49}, rateLimit)
+}
@@ -0,0 +1,129 @@
+package report
+
+import (
+	"bytes"
+	"encoding/base64"
+	"github.com/probakowski/go-airly"
+	"html/template"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Chart draws bar chart of the first index of history, current and forecast measurements. Bars are colored
+// with index color, forecast bars are semi-transparent. Nil is returned if there is no index to draw
+func (r Report) Chart(width, height int) *image.NRGBA {
+	var windows []airly.Measurement
+	windows = append(windows, r.Measurements.History...)
+	windows = append(windows, r.Measurements.Current)
+	forecastFrom := len(windows)
+	windows = append(windows, r.Measurements.Forecast...)
+
+	max := 0.0
+	for _, m := range windows {
+		if len(m.Indexes) > 0 && m.Indexes[0].Value > max {
+			max = m.Indexes[0].Value
+		}
+	}
+	if max == 0 {
+		return nil
+	}
+
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	barWidth := width / len(windows)
+	for i, m := range windows {
+		if len(m.Indexes) == 0 || barWidth == 0 {
+			continue
+		}
+		c := parseColor(m.Indexes[0].Color)
+		if i >= forecastFrom {
+			c.A = 0x80
+		}
+		top := height - int(m.Indexes[0].Value/max*float64(height))
+		bar := image.Rect(i*barWidth+1, top, (i+1)*barWidth-1, height)
+		draw.Draw(img, bar, image.NewUniform(c), image.Point{}, draw.Over)
+	}
+	return img
+}
+
+// parseColor parses #RRGGBB color, gray is returned for invalid colors
+func parseColor(s string) color.NRGBA {
+	v, err := strconv.ParseUint(strings.TrimPrefix(s, "#"), 16, 32)
+	if err != nil || len(s) != 7 {
+		return color.NRGBA{0x99, 0x99, 0x99, 0xFF}
+	}
+	return color.NRGBA{uint8(v >> 16), uint8(v >> 8), uint8(v), 0xFF}
+}
+
+// ChartDataURL returns chart encoded as base64 PNG data URL, empty URL is returned if there is nothing to draw
+func (r Report) ChartDataURL(width, height int) (template.URL, error) {
+	chart := r.Chart(width, height)
+	if chart == nil {
+		return "", nil
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, chart); err != nil {
+		return "", err
+	}
+	return template.URL("data:image/png;base64," + base64.StdEncoding.EncodeToString(buf.Bytes())), nil
+}
+
+var htmlTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: sans-serif; color: #333; max-width: 720px; margin: 2em auto; }
+.period { color: #777; }
+.index { padding: 0.5em 1em; border-left: 8px solid #999; background: #f7f7f7; margin-bottom: 1em; }
+table { border-collapse: collapse; width: 100%; margin-bottom: 1em; }
+th, td { border-bottom: 1px solid #ddd; padding: 4px 8px; text-align: left; }
+img { width: 100%; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+{{with .Period}}<p class="period">{{.}}</p>
+{{end}}{{range .Indexes}}<div class="index" style="border-color: {{.Index.Color}}">
+<p><strong>{{.Badge}} {{.Index.Name}} {{.Index.Value}}</strong>{{with .Index.Description}} – {{.}}{{end}}</p>
+{{with .Index.Advice}}<p>{{.}}</p>
+{{end}}</div>
+{{end}}{{if gt (len .Values) 1}}{{template "table" .Values}}{{end}}{{with .Chart}}<img src="{{.}}" alt="Index chart">
+{{end}}{{if gt (len .Forecast) 1}}<h2>Forecast</h2>
+{{template "table" .Forecast}}{{end}}</body>
+</html>
+{{define "table"}}<table>
+{{range $i, $row := .}}<tr>{{range $row}}{{if eq $i 0}}<th>{{.}}</th>{{else}}<td>{{.}}</td>{{end}}{{end}}</tr>
+{{end}}</table>
+{{end}}`))
+
+type htmlIndex struct {
+	Index airly.Index
+	Badge string
+}
+
+// HTML writes report as standalone HTML page with inline CSS and chart embedded as base64 PNG, see Chart
+func (r Report) HTML(w io.Writer) error {
+	chart, err := r.ChartDataURL(720, 240)
+	if err != nil {
+		return err
+	}
+	var indexes []htmlIndex
+	for _, index := range r.Measurements.Current.Indexes {
+		indexes = append(indexes, htmlIndex{index, r.Badge(index)})
+	}
+	return htmlTemplate.Execute(w, struct {
+		Title    string
+		Period   string
+		Indexes  []htmlIndex
+		Values   [][]string
+		Forecast [][]string
+		Chart    template.URL
+	}{r.Title(), r.period(), indexes, r.valueRows(), r.forecastRows(), chart})
+}
@@ -0,0 +1,47 @@
+package report
+
+import (
+	"bytes"
+	"github.com/probakowski/go-airly"
+	"github.com/stretchr/testify/assert"
+	"image/color"
+	"testing"
+)
+
+func TestHTML(t *testing.T) {
+	var buf bytes.Buffer
+	assert.Nil(t, report.HTML(&buf))
+	html := buf.String()
+	assert.Contains(t, html, "<title>Kraków, Mikołajska 4B</title>")
+	assert.Contains(t, html, `<p class="period">2020-11-18 19:00 – 2020-11-18 20:00</p>`)
+	assert.Contains(t, html, "<strong>🟢 Good AIRLY_CAQI 35.53</strong> – Air is quite good.")
+	assert.Contains(t, html, "<tr><td>PM25</td><td>18.7</td><td>WHO</td><td>25</td><td>74.8%</td></tr>")
+	assert.Contains(t, html, "<h2>Forecast</h2>")
+	assert.Contains(t, html, `<img src="data:image/png;base64,`)
+
+	buf.Reset()
+	assert.Nil(t, Report{Installation: airly.Installation{Address: airly.Address{City: "<b>"}}}.HTML(&buf))
+	html = buf.String()
+	assert.Contains(t, html, "<h1>&lt;b&gt;</h1>")
+	assert.NotContains(t, html, "<table>")
+	assert.NotContains(t, html, "<img")
+}
+
+func TestChart(t *testing.T) {
+	r := report
+	r.Measurements.Current.Indexes[0].Color = "#D1CF1E"
+	img := r.Chart(100, 50)
+	// current bar is about 44% of the highest (forecast) bar
+	assert.Equal(t, color.NRGBA{0xD1, 0xCF, 0x1E, 0xFF}, img.NRGBAAt(25, 49))
+	assert.Equal(t, color.NRGBA{0xFF, 0xFF, 0xFF, 0xFF}, img.NRGBAAt(25, 20))
+	// forecast without color is semi-transparent gray over white
+	assert.Equal(t, color.NRGBA{0xCC, 0xCC, 0xCC, 0xFF}, img.NRGBAAt(75, 1))
+
+	assert.Nil(t, Report{}.Chart(100, 50))
+}
+
+func TestParseColor(t *testing.T) {
+	assert.Equal(t, color.NRGBA{0x6B, 0xC9, 0x26, 0xFF}, parseColor("#6BC926"))
+	assert.Equal(t, color.NRGBA{0x99, 0x99, 0x99, 0xFF}, parseColor("red"))
+	assert.Equal(t, color.NRGBA{0x99, 0x99, 0x99, 0xFF}, parseColor(""))
+}
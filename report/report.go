@@ -0,0 +1,166 @@
+// Package report renders measurements as human readable reports
+package report
+
+import (
+	"fmt"
+	"github.com/probakowski/go-airly"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Report of measurements of single installation
+type Report struct {
+	Installation airly.Installation
+	Measurements airly.Measurements
+	// Labels of index levels used in badges, see airly.LevelLabels
+	Labels airly.LevelLabels
+	// Location used to format times, time.Local is used if not set
+	Location *time.Location
+}
+
+// Title of report, based on installation address
+func (r Report) Title() string {
+	a := r.Installation.Address
+	street := strings.TrimSpace(a.Street + " " + a.Number)
+	switch {
+	case a.City != "" && street != "":
+		return a.City + ", " + street
+	case a.City != "":
+		return a.City
+	case r.Installation.Id != 0:
+		return fmt.Sprintf("Installation %d", r.Installation.Id)
+	}
+	return "Air quality"
+}
+
+// Badge returns index level with its emoji, e.g. "🟢 Good"
+func (r Report) Badge(index airly.Index) string {
+	label := r.Labels.Label(index.Level)
+	return label.Emoji + " " + label.Short
+}
+
+func (r Report) time(t time.Time) string {
+	loc := r.Location
+	if loc == nil {
+		loc = time.Local
+	}
+	return t.In(loc).Format("2006-01-02 15:04")
+}
+
+// Markdown writes report as Markdown with current index, table of values with standards and forecast of index
+func (r Report) Markdown(w io.Writer) error {
+	current := r.Measurements.Current
+	var b strings.Builder
+	fmt.Fprintf(&b, "## %s\n\n", escapeMarkdown(r.Title()))
+	if period := r.period(); period != "" {
+		fmt.Fprintf(&b, "_%s_\n\n", period)
+	}
+	for _, index := range current.Indexes {
+		fmt.Fprintf(&b, "%s **%s %s**", r.Badge(index), escapeMarkdown(index.Name), formatFloat(index.Value))
+		if index.Description != "" {
+			fmt.Fprintf(&b, " – %s", escapeMarkdown(index.Description))
+		}
+		b.WriteString("\n\n")
+		if index.Advice != "" {
+			fmt.Fprintf(&b, "> %s\n\n", escapeMarkdown(index.Advice))
+		}
+	}
+
+	if values := r.valueRows(); len(values) > 1 {
+		writeTable(&b, values)
+	}
+
+	if forecast := r.forecastRows(); len(forecast) > 1 {
+		b.WriteString("\n### Forecast\n\n")
+		writeTable(&b, forecast)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// period of current measurement, empty if not known
+func (r Report) period() string {
+	current := r.Measurements.Current
+	if current.TillDateTime.IsZero() {
+		return ""
+	}
+	return r.time(current.FromDateTime) + " – " + r.time(current.TillDateTime)
+}
+
+// valueRows returns current values with matching standards, first row is header
+func (r Report) valueRows() [][]string {
+	current := r.Measurements.Current
+	rows := [][]string{{"Name", "Value", "Standard", "Limit", "% of limit"}}
+	for _, v := range current.Values {
+		row := []string{v.Name, formatFloat(v.Value), "", "", ""}
+		if v.Null {
+			row[1] = "-"
+		}
+		for _, s := range current.Standards {
+			if s.Pollutant == v.Name {
+				row[2], row[3], row[4] = s.Name, formatFloat(s.Limit), formatFloat(s.Percent)+"%"
+				break
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// forecastRows returns first index of forecast measurements, first row is header
+func (r Report) forecastRows() [][]string {
+	rows := [][]string{{"From", "Till", "Index", "Level"}}
+	for _, f := range r.Measurements.Forecast {
+		if len(f.Indexes) == 0 {
+			continue
+		}
+		index := f.Indexes[0]
+		rows = append(rows, []string{r.time(f.FromDateTime), r.time(f.TillDateTime),
+			index.Name + " " + formatFloat(index.Value), r.Badge(index)})
+	}
+	return rows
+}
+
+// MarkdownTable writes rows as Markdown table, first row is used as header
+func MarkdownTable(w io.Writer, rows [][]string) error {
+	var b strings.Builder
+	writeTable(&b, rows)
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writeTable(b *strings.Builder, rows [][]string) {
+	if len(rows) == 0 {
+		return
+	}
+	writeRow(b, rows[0])
+	separator := make([]string, len(rows[0]))
+	for i := range separator {
+		separator[i] = "---"
+	}
+	writeRow(b, separator)
+	for _, row := range rows[1:] {
+		writeRow(b, row)
+	}
+}
+
+func writeRow(b *strings.Builder, row []string) {
+	b.WriteString("|")
+	for _, cell := range row {
+		b.WriteString(" ")
+		b.WriteString(strings.Replace(strings.Replace(cell, "|", `\|`, -1), "\n", " ", -1))
+		b.WriteString(" |")
+	}
+	b.WriteString("\n")
+}
+
+// escapeMarkdown escapes characters that would be interpreted as inline formatting
+func escapeMarkdown(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "*", `\*`, "_", `\_`, "`", "\\`", "[", `\[`, "]", `\]`, "<", "&lt;").Replace(s)
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
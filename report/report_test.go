@@ -0,0 +1,76 @@
+package report
+
+import (
+	"bytes"
+	"github.com/probakowski/go-airly"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+var from = time.Date(2020, 11, 18, 19, 0, 0, 0, time.UTC)
+
+var report = Report{
+	Installation: airly.Installation{Id: 204, Address: airly.Address{City: "Kraków", Street: "Mikołajska", Number: "4B"}},
+	Measurements: airly.Measurements{
+		Current: airly.Measurement{
+			FromDateTime: from,
+			TillDateTime: from.Add(time.Hour),
+			Values:       []airly.Value{{Name: "PM25", Value: 18.7}, {Name: "TEMPERATURE", Value: 4.1}},
+			Indexes: []airly.Index{{Name: "AIRLY_CAQI", Value: 35.53, Level: "LOW",
+				Description: "Air is quite good.", Advice: "Take a breath!"}},
+			Standards: []airly.Standard{{Name: "WHO", Pollutant: "PM25", Limit: 25, Percent: 74.8}},
+		},
+		Forecast: []airly.Measurement{{
+			FromDateTime: from.Add(time.Hour),
+			TillDateTime: from.Add(2 * time.Hour),
+			Indexes:      []airly.Index{{Name: "AIRLY_CAQI", Value: 80, Level: "HIGH"}},
+		}},
+	},
+	Location: time.UTC,
+}
+
+func TestTitle(t *testing.T) {
+	assert.Equal(t, "Kraków, Mikołajska 4B", report.Title())
+	assert.Equal(t, "Kraków", Report{Installation: airly.Installation{Address: airly.Address{City: "Kraków"}}}.Title())
+	assert.Equal(t, "Installation 204", Report{Installation: airly.Installation{Id: 204}}.Title())
+	assert.Equal(t, "Air quality", Report{}.Title())
+}
+
+func TestMarkdown(t *testing.T) {
+	var buf bytes.Buffer
+	assert.Nil(t, report.Markdown(&buf))
+	assert.Equal(t, `## Kraków, Mikołajska 4B
+
+_2020-11-18 19:00 – 2020-11-18 20:00_
+
+🟢 Good **AIRLY\_CAQI 35.53** – Air is quite good.
+
+> Take a breath!
+
+| Name | Value | Standard | Limit | % of limit |
+| --- | --- | --- | --- | --- |
+| PM25 | 18.7 | WHO | 25 | 74.8% |
+| TEMPERATURE | 4.1 |  |  |  |
+
+### Forecast
+
+| From | Till | Index | Level |
+| --- | --- | --- | --- |
+| 2020-11-18 20:00 | 2020-11-18 21:00 | AIRLY_CAQI 80 | 🟠 Bad |
+`, buf.String())
+
+	buf.Reset()
+	assert.Nil(t, Report{}.Markdown(&buf))
+	assert.Equal(t, "## Air quality\n\n", buf.String())
+}
+
+func TestMarkdownTable(t *testing.T) {
+	var buf bytes.Buffer
+	assert.Nil(t, MarkdownTable(&buf, [][]string{{"ID", "CITY"}, {"204", "a|b\nc"}}))
+	assert.Equal(t, "| ID | CITY |\n| --- | --- |\n| 204 | a\\|b c |\n", buf.String())
+
+	buf.Reset()
+	assert.Nil(t, MarkdownTable(&buf, nil))
+	assert.Equal(t, "", buf.String())
+}
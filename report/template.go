@@ -0,0 +1,76 @@
+package report
+
+import (
+	"github.com/probakowski/go-airly"
+	"math"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// Funcs returns functions available in templates created by NewTemplate, in addition to text/template built-ins
+// like index:
+//
+//	value MEASUREMENT NAME   value or index value with given name (case-insensitive), 0 if missing
+//	has MEASUREMENT NAME     true if measurement has value or index with given name
+//	firstIndex MEASUREMENT   the first index of measurement, empty index if there is none
+//	label LEVEL              emoji and short label of index level, see airly.DefaultLevelLabels
+//	round VALUE PLACES       value rounded to given number of decimal places
+//	time TIME LAYOUT         time in local time zone formatted with layout, e.g. 15:04
+//	daily MEASUREMENTS NAME  airly.DailySummary of value per local day of history and current measurement
+//	upper, lower STRING      string in upper or lower case
+func Funcs() template.FuncMap {
+	return template.FuncMap{
+		"value": func(m airly.Measurement, name string) float64 {
+			v, _ := lookup(m, name)
+			return v
+		},
+		"has": func(m airly.Measurement, name string) bool {
+			_, ok := lookup(m, name)
+			return ok
+		},
+		"firstIndex": func(m airly.Measurement) airly.Index {
+			if len(m.Indexes) == 0 {
+				return airly.Index{}
+			}
+			return m.Indexes[0]
+		},
+		"label": func(level string) airly.LevelLabel {
+			return airly.LevelLabels(nil).Label(level)
+		},
+		"round": func(v float64, places int) float64 {
+			p := math.Pow10(places)
+			return math.Round(v*p) / p
+		},
+		"time": func(t time.Time, layout string) string {
+			return t.Local().Format(layout)
+		},
+		"daily": func(m airly.Measurements, name string) []airly.DailySummary {
+			series := append(append([]airly.Measurement{}, m.History...), m.Current)
+			return airly.DailySummaries(series, name, time.Local)
+		},
+		"upper": strings.ToUpper,
+		"lower": strings.ToLower,
+	}
+}
+
+// NewTemplate returns text/template with Funcs, templates are meant to be executed with airly.Measurements, e.g.
+//
+//	{{with .Current}}{{(label (firstIndex .).Level).Emoji}} PM2.5 {{round (value . "PM25") 0}}{{end}}
+func NewTemplate(name string) *template.Template {
+	return template.New(name).Funcs(Funcs())
+}
+
+func lookup(m airly.Measurement, name string) (float64, bool) {
+	for _, v := range m.Values {
+		if strings.EqualFold(v.Name, name) {
+			return v.Value, !v.Null
+		}
+	}
+	for _, i := range m.Indexes {
+		if strings.EqualFold(i.Name, name) {
+			return i.Value, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,51 @@
+package report
+
+import (
+	"bytes"
+	"github.com/probakowski/go-airly"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestNewTemplate(t *testing.T) {
+	m := airly.Measurements{Current: airly.Measurement{
+		TillDateTime: time.Date(2020, 11, 18, 20, 0, 0, 0, time.Local),
+		Values:       []airly.Value{{Name: "PM25", Value: 18.73}},
+		Indexes:      []airly.Index{{Name: "AIRLY_CAQI", Value: 35.53, Level: "LOW"}},
+	}}
+	for _, tc := range []struct {
+		template, expected string
+	}{
+		{`{{with .Current}}{{(label (firstIndex .).Level).Emoji}} PM2.5 {{round (value . "PM25") 0}}{{end}}`, "🟢 PM2.5 19"},
+		{`{{value .Current "pm25"}} {{value .Current "airly_caqi"}} {{value .Current "NO2"}}`, "18.73 35.53 0"},
+		{`{{has .Current "PM25"}} {{has .Current "PM10"}}`, "true false"},
+		{`{{round 18.73 1}} {{(firstIndex .Current).Name | lower}} {{upper "low"}}`, "18.7 airly_caqi LOW"},
+		{`{{time .Current.TillDateTime "15:04"}}`, "20:00"},
+		{`{{(index .Current.Values 0).Name}} {{(index .Current.Indexes 0).Level}}`, "PM25 LOW"},
+	} {
+		tmpl, err := NewTemplate("test").Parse(tc.template)
+		assert.Nil(t, err)
+		var buf bytes.Buffer
+		assert.Nil(t, tmpl.Execute(&buf, m))
+		assert.Equal(t, tc.expected, buf.String())
+	}
+
+	day := func(d, hour int, v float64) airly.Measurement {
+		from := time.Date(2020, 11, d, hour, 0, 0, 0, time.Local)
+		return airly.Measurement{FromDateTime: from, TillDateTime: from.Add(time.Hour),
+			Values: []airly.Value{{Name: "PM25", Value: v}}}
+	}
+	history := airly.Measurements{History: []airly.Measurement{day(17, 22, 10), day(17, 23, 20)}, Current: day(18, 0, 5)}
+	tmpl, err := NewTemplate("test").Parse(`{{range daily . "PM25"}}{{.Day}} {{.Min}}-{{.Max}} {{.Avg}}|{{end}}`)
+	assert.Nil(t, err)
+	var buf bytes.Buffer
+	assert.Nil(t, tmpl.Execute(&buf, history))
+	assert.Equal(t, "2020-11-17 10-20 15|2020-11-18 5-5 5|", buf.String())
+
+	tmpl, err = NewTemplate("test").Parse(`{{(firstIndex .Current).Level}}|{{(label "UNKNOWN").Short}}`)
+	assert.Nil(t, err)
+	buf.Reset()
+	assert.Nil(t, tmpl.Execute(&buf, airly.Measurements{}))
+	assert.Equal(t, "|?", buf.String())
+}
@@ -0,0 +1,69 @@
+package airly
+
+import (
+	"errors"
+	"sync"
+)
+
+// ErrNoInstallation is returned when there is no installation within requested distance
+var ErrNoInstallation = errors.New("no installation within range")
+
+// NearestResolver provides measurements from installation nearest to location like Client.NearestMeasurements,
+// but installation is resolved only once per location (and maximum distance) and then the cheaper
+// Client.InstallationMeasurements is used. Resolution is forgotten when getting measurements fails, so
+// relocated or removed installations are resolved again on next call. NearestResolver is safe for concurrent use
+type NearestResolver struct {
+	Client Client
+
+	mu            sync.Mutex
+	installations map[resolverKey]int
+}
+
+type resolverKey struct {
+	location    Location
+	maxDistance float64
+}
+
+// Resolve returns ID of installation nearest to location, range can be defined with MaxDistance
+func (r *NearestResolver) Resolve(loc Location, options ...NearestInstallationsOption) (int, error) {
+	key := resolverKey{loc, newNearestInstallationsConfig(options).maxDistance}
+	r.mu.Lock()
+	id, ok := r.installations[key]
+	r.mu.Unlock()
+	if ok {
+		return id, nil
+	}
+
+	installations, err := r.Client.NearestInstallations(loc, MaxDistance(key.maxDistance), MaxResults(1))
+	if err != nil {
+		return 0, err
+	}
+	if len(installations) == 0 {
+		return 0, ErrNoInstallation
+	}
+	r.mu.Lock()
+	if r.installations == nil {
+		r.installations = map[resolverKey]int{}
+	}
+	r.installations[key] = installations[0].Id
+	r.mu.Unlock()
+	return installations[0].Id, nil
+}
+
+// NearestMeasurements returns measurements for an installation closest to a given location, range can be defined
+// with MaxDistance, index type with WithIndexType and pollutant of indexes with WithIndexPollutant
+func (r *NearestResolver) NearestMeasurements(loc Location, options ...NearestMeasurementsOption) (Measurements, error) {
+	config := newNearestMeasurementsConfig(options)
+	id, err := r.Resolve(loc, MaxDistance(config.maxDistance))
+	if err != nil {
+		return Measurements{}, err
+	}
+	m, err := r.Client.InstallationMeasurements(id, WithIndexType(config.indexType),
+		WithIndexPollutant(config.indexPollutant))
+	if err != nil {
+		r.mu.Lock()
+		delete(r.installations, resolverKey{loc, config.maxDistance})
+		r.mu.Unlock()
+	}
+	return m, err
+}
@@ -0,0 +1,63 @@
+package airly
+
+import (
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+func TestNearestResolver(t *testing.T) {
+	var urls []string
+	status := 200
+	r := NearestResolver{
+		Client: Client{
+			Key: "x1234x",
+			HttpClient: mockClient{func(req *http.Request) (*http.Response, error) {
+				urls = append(urls, req.URL.String())
+				if req.URL.Path == "/v2/installations/nearest" {
+					return &http.Response{
+						StatusCode: 200,
+						Body:       readCloser(`[{"id": 204}]`),
+					}, nil
+				}
+				return &http.Response{
+					StatusCode: status,
+					Body:       readCloser(`{"current": {"values": [{"name": "PM25", "value": 18.7}]}}`),
+				}, nil
+			}},
+		},
+	}
+
+	loc := Location{50.062006, 19.940984}
+	for i := 0; i < 2; i++ {
+		m, err := r.NearestMeasurements(loc, MaxDistance(5), WithIndexType("CAQI"))
+		assert.Nil(t, err)
+		assert.Equal(t, []Value{{Name: "PM25", Value: 18.7}}, m.Current.Values)
+	}
+	assert.Equal(t, []string{
+		"https://airapi.airly.eu/v2/installations/nearest?lat=50.062006&lng=19.940984&maxDistanceKM=5&maxResults=1",
+		"https://airapi.airly.eu/v2/measurements/installation?indexType=CAQI&installationId=204",
+		"https://airapi.airly.eu/v2/measurements/installation?indexType=CAQI&installationId=204",
+	}, urls)
+
+	status = 404
+	_, err := r.NearestMeasurements(loc, MaxDistance(5))
+	assert.NotNil(t, err)
+	assert.Empty(t, r.installations)
+}
+
+func TestNearestResolverNoInstallation(t *testing.T) {
+	r := NearestResolver{
+		Client: Client{
+			Key: "x1234x",
+			HttpClient: mockClient{func(req *http.Request) (*http.Response, error) {
+				return &http.Response{
+					StatusCode: 200,
+					Body:       readCloser(`[]`),
+				}, nil
+			}},
+		},
+	}
+	_, err := r.NearestMeasurements(Location{50.062006, 19.940984})
+	assert.Equal(t, ErrNoInstallation, err)
+}
@@ -0,0 +1,175 @@
+package airly
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RetryingClient is HttpClient retrying requests failed with network errors, 429 Too Many Requests or 5xx statuses.
+// Delays between attempts are defined by Backoff, longer delay requested with Retry-After header is respected. Requests are not retried past
+// MaxElapsed from the first attempt or when Budget is exhausted, so retrying during an outage can't use up API quota
+type RetryingClient struct {
+	// HttpClient used to send requests, http.Client with DefaultTimeout is used if not set
+	HttpClient HttpClient
+	// MaxAttempts including the first one, 3 is used if not set
+	MaxAttempts int
+	// Backoff policy, ExponentialBackoff starting with 1 second is used if not set
+	Backoff BackoffPolicy
+	// MaxElapsed time since the first attempt after which requests are not retried, 30 seconds is used if not set
+	MaxElapsed time.Duration
+	// Budget limiting number of retries, it can be shared by many clients. Retries are not limited if not set
+	Budget *RetryBudget
+
+	now   func() time.Time
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+// Do sends request, retrying it if needed. Requests with body can be retried only if GetBody is set
+func (c RetryingClient) Do(req *http.Request) (*http.Response, error) {
+	client := c.HttpClient
+	if client == nil {
+		client = defaultHttpClient
+	}
+	maxAttempts := c.MaxAttempts
+	if maxAttempts == 0 {
+		maxAttempts = 3
+	}
+	backoff := c.Backoff
+	if backoff == nil {
+		backoff = ExponentialBackoff{}
+	}
+	maxElapsed := c.MaxElapsed
+	if maxElapsed == 0 {
+		maxElapsed = 30 * time.Second
+	}
+	now := c.now
+	if now == nil {
+		now = time.Now
+	}
+	sleep := c.sleep
+	if sleep == nil {
+		sleep = sleepContext
+	}
+
+	start := now()
+	if c.Budget != nil {
+		c.Budget.request()
+	}
+	for attempt := 1; ; attempt++ {
+		res, err := client.Do(req)
+		if !retryable(res, err) || attempt >= maxAttempts || (req.Body != nil && req.GetBody == nil) {
+			return res, err
+		}
+		wait := backoff.NextDelay(attempt, err, res)
+		if after := retryAfter(res); after > wait {
+			wait = after
+		}
+		if now().Add(wait).Sub(start) > maxElapsed || (c.Budget != nil && !c.Budget.withdraw()) {
+			return res, err
+		}
+		if res != nil {
+			_, _ = io.Copy(ioutil.Discard, io.LimitReader(res.Body, 4096))
+			_ = res.Body.Close()
+		}
+		if err := sleep(req.Context(), wait); err != nil {
+			return nil, err
+		}
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+	}
+}
+
+// retryable returns true for temporary errors and statuses, see IsTemporary and APIError.Temporary
+func retryable(res *http.Response, err error) bool {
+	if err != nil {
+		return IsTemporary(err)
+	}
+	return (&APIError{StatusCode: res.StatusCode}).Temporary()
+}
+
+// retryAfter returns delay requested by server in Retry-After header given in seconds, 0 if not set
+func retryAfter(res *http.Response) time.Duration {
+	if res == nil {
+		return 0
+	}
+	seconds, err := strconv.Atoi(res.Header.Get("Retry-After"))
+	if err != nil || seconds < 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// RetryBudget limits retries to a fraction of requests. Each request adds Ratio to the budget and each retry
+// takes 1 from it, the budget starts with and never exceeds Reserve, so at most Ratio*requests+Reserve retries
+// are made. RetryBudget is safe for concurrent use
+type RetryBudget struct {
+	// Ratio of retries to requests, 0.2 is used if not set
+	Ratio float64
+	// Reserve of retries available regardless of number of requests, 10 is used if not set
+	Reserve int
+
+	mu          sync.Mutex
+	tokens      float64
+	initialized bool
+}
+
+func (b *RetryBudget) init() {
+	if !b.initialized {
+		b.tokens = float64(b.reserve())
+		b.initialized = true
+	}
+}
+
+func (b *RetryBudget) reserve() int {
+	if b.Reserve == 0 {
+		return 10
+	}
+	return b.Reserve
+}
+
+func (b *RetryBudget) request() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.init()
+	ratio := b.Ratio
+	if ratio == 0 {
+		ratio = 0.2
+	}
+	b.tokens += ratio
+	if max := float64(b.reserve()); b.tokens > max {
+		b.tokens = max
+	}
+}
+
+func (b *RetryBudget) withdraw() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.init()
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
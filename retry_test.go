@@ -0,0 +1,180 @@
+package airly
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// retryTest returns RetryingClient responding with given statuses (0 means network error) and recording delays
+func retryTest(statuses ...int) (*RetryingClient, *[]time.Duration, *int) {
+	var delays []time.Duration
+	calls := 0
+	now := time.Date(2020, 11, 18, 20, 0, 0, 0, time.UTC)
+	c := &RetryingClient{
+		HttpClient: mockClient{func(req *http.Request) (*http.Response, error) {
+			status := statuses[calls]
+			calls++
+			if status == 0 {
+				return nil, syscall.ECONNRESET
+			}
+			return &http.Response{StatusCode: status, Header: http.Header{}, Body: readCloser("")}, nil
+		}},
+		now: func() time.Time { return now },
+		sleep: func(ctx context.Context, d time.Duration) error {
+			delays = append(delays, d)
+			now = now.Add(d)
+			return nil
+		},
+	}
+	return c, &delays, &calls
+}
+
+func TestRetryingClient(t *testing.T) {
+	req, _ := http.NewRequest("GET", "https://airapi.airly.eu/v2/installations/204", nil)
+
+	c, delays, calls := retryTest(503, 0, 200)
+	res, err := c.Do(req)
+	assert.Nil(t, err)
+	assert.Equal(t, 200, res.StatusCode)
+	assert.Equal(t, 3, *calls)
+	assert.Equal(t, []time.Duration{time.Second, 2 * time.Second}, *delays)
+
+	c, _, calls = retryTest(503, 503, 503, 200)
+	res, err = c.Do(req)
+	assert.Nil(t, err)
+	assert.Equal(t, 503, res.StatusCode)
+	assert.Equal(t, 3, *calls)
+
+	c, _, calls = retryTest(404, 200)
+	res, _ = c.Do(req)
+	assert.Equal(t, 404, res.StatusCode)
+	assert.Equal(t, 1, *calls)
+
+	c, _, calls = retryTest(0, 0)
+	c.MaxAttempts = 2
+	_, err = c.Do(req)
+	assert.EqualError(t, err, "connection reset by peer")
+	assert.Equal(t, 2, *calls)
+}
+
+func TestRetryingClientMaxElapsed(t *testing.T) {
+	req, _ := http.NewRequest("GET", "https://airapi.airly.eu/v2/installations/204", nil)
+	c, delays, calls := retryTest(503, 503, 503, 503, 200)
+	c.MaxAttempts = 10
+	c.Backoff = ExponentialBackoff{Initial: 5 * time.Second}
+	c.MaxElapsed = 20 * time.Second
+	res, _ := c.Do(req)
+	assert.Equal(t, 503, res.StatusCode)
+	// 5s and 10s delays fit in 20s, the next 20s one would not
+	assert.Equal(t, []time.Duration{5 * time.Second, 10 * time.Second}, *delays)
+	assert.Equal(t, 3, *calls)
+}
+
+func TestRetryingClientRetryAfter(t *testing.T) {
+	req, _ := http.NewRequest("GET", "https://airapi.airly.eu/v2/installations/204", nil)
+	c, delays, _ := retryTest(429, 200)
+	c.HttpClient = mockClient{func(req *http.Request) (*http.Response, error) {
+		if len(*delays) == 0 {
+			return &http.Response{StatusCode: 429, Header: http.Header{"Retry-After": {"7"}}, Body: readCloser("")}, nil
+		}
+		return &http.Response{StatusCode: 200, Body: readCloser("")}, nil
+	}}
+	res, _ := c.Do(req)
+	assert.Equal(t, 200, res.StatusCode)
+	assert.Equal(t, []time.Duration{7 * time.Second}, *delays)
+
+	c, delays, _ = retryTest()
+	c.HttpClient = mockClient{func(req *http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: 429, Header: http.Header{"Retry-After": {"3600"}}, Body: readCloser("")}, nil
+	}}
+	res, _ = c.Do(req)
+	assert.Equal(t, 429, res.StatusCode)
+	assert.Empty(t, *delays)
+}
+
+func TestRetryingClientBody(t *testing.T) {
+	req, _ := http.NewRequest("POST", "https://example.com", strings.NewReader("body"))
+	c, _, calls := retryTest(503, 200)
+	res, _ := c.Do(req)
+	assert.Equal(t, 200, res.StatusCode)
+	assert.Equal(t, 2, *calls)
+
+	req.GetBody = nil
+	c, _, calls = retryTest(503, 200)
+	res, _ = c.Do(req)
+	assert.Equal(t, 503, res.StatusCode)
+	assert.Equal(t, 1, *calls)
+}
+
+func TestRetryingClientContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	req, _ := http.NewRequestWithContext(ctx, "GET", "https://airapi.airly.eu/v2/installations/204", nil)
+	c, _, _ := retryTest(503, 200)
+	c.sleep = nil
+	c.Backoff = ConstantBackoff{Delay: time.Hour}
+	c.MaxElapsed = 2 * time.Hour
+	_, err := c.Do(req)
+	assert.Equal(t, context.Canceled, err)
+}
+
+func TestRetryBudget(t *testing.T) {
+	budget := &RetryBudget{Ratio: 0.5, Reserve: 2}
+	req, _ := http.NewRequest("GET", "https://airapi.airly.eu/v2/installations/204", nil)
+	c, _, calls := retryTest(503, 503, 503, 503, 503, 503, 503, 503, 503, 503)
+	c.MaxAttempts = 10
+	c.MaxElapsed = time.Hour
+	c.Budget = budget
+	_, _ = c.Do(req)
+	// reserve of 2 retries
+	assert.Equal(t, 3, *calls)
+	_, _ = c.Do(req)
+	// the second request adds 0.5, not enough to retry
+	assert.Equal(t, 4, *calls)
+	_, _ = c.Do(req)
+	// the third request adds another 0.5, enough for one retry
+	assert.Equal(t, 6, *calls)
+
+	budget = &RetryBudget{}
+	for i := 0; i < 100; i++ {
+		budget.request()
+	}
+	for i := 0; i < 10; i++ {
+		assert.True(t, budget.withdraw())
+	}
+	assert.False(t, budget.withdraw())
+}
+
+func TestRetryingClientBackoff(t *testing.T) {
+	req, _ := http.NewRequest("GET", "https://airapi.airly.eu/v2/installations/204", nil)
+	c, delays, _ := retryTest(503, 0, 200)
+	var attempts []int
+	var errs []error
+	var statuses []int
+	c.Backoff = backoffFunc(func(attempt int, err error, res *http.Response) time.Duration {
+		attempts = append(attempts, attempt)
+		errs = append(errs, err)
+		if res != nil {
+			statuses = append(statuses, res.StatusCode)
+		}
+		return time.Duration(attempt) * time.Millisecond
+	})
+	res, _ := c.Do(req)
+	assert.Equal(t, 200, res.StatusCode)
+	assert.Equal(t, []int{1, 2}, attempts)
+	assert.Nil(t, errs[0])
+	assert.EqualError(t, errs[1], "connection reset by peer")
+	assert.Equal(t, []int{503}, statuses)
+	assert.Equal(t, []time.Duration{time.Millisecond, 2 * time.Millisecond}, *delays)
+}
+
+type backoffFunc func(attempt int, err error, res *http.Response) time.Duration
+
+func (f backoffFunc) NextDelay(attempt int, err error, res *http.Response) time.Duration {
+	return f(attempt, err, res)
+}
@@ -0,0 +1,193 @@
+// Package sensorcommunity provides airly.AirQualityProvider backed by Sensor.Community (formerly Luftdaten) API,
+// see https://github.com/opendata-stuttgart/meta/wiki/EN-APIs
+package sensorcommunity
+
+import (
+	"fmt"
+	"github.com/probakowski/go-airly"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// DefaultBaseURL of Sensor.Community API
+const DefaultBaseURL = "https://data.sensor.community/airrohr/v1/"
+
+// Provider of Sensor.Community data. Installations are sensor locations, as particulate matter and weather sensors
+// placed together report separately. Values are averages of readings from the last 5 minutes.
+// Sensor.Community doesn't interpolate values, so PointMeasurements returns measurements of the nearest location.
+// Indoor sensors are skipped
+type Provider struct {
+	// BaseURL of the API, DefaultBaseURL is used if empty
+	BaseURL string
+	// MaxResponseSize in bytes, airly.DefaultMaxResponseSize is used if not set, negative value means no limit
+	MaxResponseSize int64
+	// HttpClient to use for requests, http.Client with airly.DefaultTimeout is used if nil
+	HttpClient airly.HttpClient
+}
+
+var _ airly.AirQualityProvider = Provider{}
+
+type reading struct {
+	Timestamp string `json:"timestamp"`
+	Location  struct {
+		Id        int    `json:"id"`
+		Latitude  string `json:"latitude"`
+		Longitude string `json:"longitude"`
+		Altitude  string `json:"altitude"`
+		Country   string `json:"country"`
+		Indoor    int    `json:"indoor"`
+	} `json:"location"`
+	SensorDataValues []struct {
+		Value     string `json:"value"`
+		ValueType string `json:"value_type"`
+	} `json:"sensordatavalues"`
+}
+
+// valueTypes maps Sensor.Community value types to value names used by Airly with factor converting units
+var valueTypes = map[string]struct {
+	name   string
+	factor float64
+}{
+	"P0":          {"PM1", 1},
+	"P1":          {"PM10", 1},
+	"P2":          {"PM25", 1},
+	"temperature": {"TEMPERATURE", 1},
+	"humidity":    {"HUMIDITY", 1},
+	"pressure":    {"PRESSURE", 0.01}, // Pa to hPa
+}
+
+func (p Provider) get(path string, v interface{}) error {
+	base := p.BaseURL
+	if base == "" {
+		base = DefaultBaseURL
+	}
+	return airly.GetJSON(p.HttpClient, base+path, p.MaxResponseSize, v)
+}
+
+// location groups readings of all sensors at one place
+type location struct {
+	installation airly.Installation
+	distance     float64
+	readings     []reading
+}
+
+// locations returns outdoor locations within distance from loc, sorted by distance
+func (p Provider) locations(loc airly.Location, distance float64) ([]*location, error) {
+	var readings []reading
+	err := p.get(fmt.Sprintf("filter/area=%s,%s,%s", formatFloat(loc.Latitude), formatFloat(loc.Longitude),
+		formatFloat(distance)), &readings)
+	if err != nil {
+		return nil, err
+	}
+	byId := map[int]*location{}
+	var locations []*location
+	for _, r := range readings {
+		if r.Location.Indoor != 0 {
+			continue
+		}
+		l, ok := byId[r.Location.Id]
+		if !ok {
+			lat, _ := strconv.ParseFloat(r.Location.Latitude, 64)
+			lng, _ := strconv.ParseFloat(r.Location.Longitude, 64)
+			elevation, _ := strconv.ParseFloat(r.Location.Altitude, 64)
+			installation := airly.Installation{
+				Id:        r.Location.Id,
+				Location:  airly.Location{Latitude: lat, Longitude: lng},
+				Address:   airly.Address{Country: r.Location.Country},
+				Elevation: elevation,
+			}
+			l = &location{installation: installation, distance: installation.Location.Distance(loc)}
+			if l.distance > distance {
+				continue
+			}
+			byId[r.Location.Id] = l
+			locations = append(locations, l)
+		}
+		l.readings = append(l.readings, r)
+	}
+	sort.SliceStable(locations, func(i, j int) bool {
+		return locations[i].distance < locations[j].distance
+	})
+	return locations, nil
+}
+
+// NearestInstallations returns sensor locations sorted by distance to loc, range can be defined with
+// airly.MaxDistance, number of results with airly.MaxResults
+func (p Provider) NearestInstallations(loc airly.Location, options ...airly.NearestInstallationsOption) ([]airly.Installation, error) {
+	o := airly.ResolveOptions(options...)
+	locations, err := p.locations(loc, o.MaxDistance)
+	if err != nil {
+		return nil, err
+	}
+	installations := []airly.Installation{}
+	for _, l := range locations {
+		if o.MaxResults >= 0 && len(installations) == o.MaxResults {
+			break
+		}
+		installations = append(installations, l.installation)
+	}
+	return installations, nil
+}
+
+// NearestMeasurements returns measurements from the nearest location, range can be defined with airly.MaxDistance.
+// airly.ErrNoInstallation is returned if there is no location in range. Only current measurement is available
+func (p Provider) NearestMeasurements(loc airly.Location, options ...airly.NearestMeasurementsOption) (airly.Measurements, error) {
+	o := airly.ResolveNearestMeasurementsOptions(options...)
+	locations, err := p.locations(loc, o.MaxDistance)
+	if err != nil {
+		return airly.Measurements{}, err
+	}
+	if len(locations) == 0 {
+		return airly.Measurements{}, airly.ErrNoInstallation
+	}
+	current, err := locations[0].measurement()
+	return airly.Measurements{Current: current, History: []airly.Measurement{}, Forecast: []airly.Measurement{}}, err
+}
+
+// PointMeasurements returns measurements from the nearest location within default range, see NearestMeasurements
+func (p Provider) PointMeasurements(loc airly.Location, _ ...airly.MeasurementsOption) (airly.Measurements, error) {
+	return p.NearestMeasurements(loc)
+}
+
+// measurement averages readings of location, time range covers all readings
+func (l *location) measurement() (airly.Measurement, error) {
+	var m airly.Measurement
+	sums := map[string]float64{}
+	counts := map[string]int{}
+	for _, r := range l.readings {
+		t, err := time.Parse("2006-01-02 15:04:05", r.Timestamp)
+		if err != nil {
+			return airly.Measurement{}, err
+		}
+		if m.FromDateTime.IsZero() || t.Before(m.FromDateTime) {
+			m.FromDateTime = t
+		}
+		if t.After(m.TillDateTime) {
+			m.TillDateTime = t
+		}
+		for _, v := range r.SensorDataValues {
+			valueType, ok := valueTypes[v.ValueType]
+			if !ok {
+				continue
+			}
+			value, err := strconv.ParseFloat(v.Value, 64)
+			if err != nil {
+				continue
+			}
+			sums[valueType.name] += value * valueType.factor
+			counts[valueType.name]++
+		}
+	}
+	for name, sum := range sums {
+		m.Values = append(m.Values, airly.Value{Name: name, Value: sum / float64(counts[name])})
+	}
+	sort.Slice(m.Values, func(i, j int) bool {
+		return m.Values[i].Name < m.Values[j].Name
+	})
+	return m, nil
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
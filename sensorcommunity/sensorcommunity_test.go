@@ -0,0 +1,123 @@
+package sensorcommunity
+
+import (
+	"errors"
+	"github.com/probakowski/go-airly"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+type mockClient struct {
+	DoFunc func(req *http.Request) (*http.Response, error)
+}
+
+func (m mockClient) Do(req *http.Request) (*http.Response, error) {
+	return m.DoFunc(req)
+}
+
+const readings = `[{
+	"timestamp": "2021-10-01 12:00:03",
+	"location": {"id": 1, "latitude": "50.0625", "longitude": "19.9410", "altitude": "220.0", "country": "PL", "indoor": 0},
+	"sensor": {"id": 11, "sensor_type": {"name": "SDS011"}},
+	"sensordatavalues": [{"value": "20.00", "value_type": "P1"}, {"value": "10.00", "value_type": "P2"}]
+}, {
+	"timestamp": "2021-10-01 12:02:30",
+	"location": {"id": 1, "latitude": "50.0625", "longitude": "19.9410", "altitude": "220.0", "country": "PL", "indoor": 0},
+	"sensor": {"id": 11, "sensor_type": {"name": "SDS011"}},
+	"sensordatavalues": [{"value": "24.00", "value_type": "P1"}, {"value": "12.00", "value_type": "P2"}]
+}, {
+	"timestamp": "2021-10-01 12:01:00",
+	"location": {"id": 1, "latitude": "50.0625", "longitude": "19.9410", "altitude": "220.0", "country": "PL", "indoor": 0},
+	"sensor": {"id": 12, "sensor_type": {"name": "BME280"}},
+	"sensordatavalues": [{"value": "12.5", "value_type": "temperature"}, {"value": "101325", "value_type": "pressure"}, {"value": "x", "value_type": "humidity"}]
+}, {
+	"timestamp": "2021-10-01 12:01:00",
+	"location": {"id": 2, "latitude": "50.0700", "longitude": "19.9500", "altitude": "210.0", "country": "PL", "indoor": 0},
+	"sensor": {"id": 21, "sensor_type": {"name": "SDS011"}},
+	"sensordatavalues": [{"value": "30.00", "value_type": "P1"}]
+}, {
+	"timestamp": "2021-10-01 12:01:00",
+	"location": {"id": 3, "latitude": "50.0621", "longitude": "19.9410", "altitude": "210.0", "country": "PL", "indoor": 1},
+	"sensor": {"id": 31, "sensor_type": {"name": "SDS011"}},
+	"sensordatavalues": [{"value": "5.00", "value_type": "P1"}]
+}]`
+
+func provider(t *testing.T, expectedPath string) Provider {
+	return Provider{HttpClient: mockClient{func(req *http.Request) (*http.Response, error) {
+		assert.Equal(t, expectedPath, req.URL.Path)
+		return &http.Response{StatusCode: 200, Body: io.NopCloser(strings.NewReader(readings))}, nil
+	}}}
+}
+
+func TestNearestInstallations(t *testing.T) {
+	p := provider(t, "/airrohr/v1/filter/area=50.062006,19.940984,3")
+	installations, err := p.NearestInstallations(airly.Location{Latitude: 50.062006, Longitude: 19.940984},
+		airly.MaxResults(-1))
+	assert.Nil(t, err)
+	assert.Equal(t, []airly.Installation{{
+		Id:        1,
+		Location:  airly.Location{Latitude: 50.0625, Longitude: 19.941},
+		Address:   airly.Address{Country: "PL"},
+		Elevation: 220,
+	}, {
+		Id:        2,
+		Location:  airly.Location{Latitude: 50.07, Longitude: 19.95},
+		Address:   airly.Address{Country: "PL"},
+		Elevation: 210,
+	}}, installations)
+
+	installations, err = p.NearestInstallations(airly.Location{Latitude: 50.062006, Longitude: 19.940984})
+	assert.Nil(t, err)
+	assert.Len(t, installations, 1)
+}
+
+func TestNearestMeasurements(t *testing.T) {
+	p := provider(t, "/airrohr/v1/filter/area=50.062006,19.940984,3")
+	m, err := p.PointMeasurements(airly.Location{Latitude: 50.062006, Longitude: 19.940984})
+	assert.Nil(t, err)
+	assert.Equal(t, airly.Measurements{
+		Current: airly.Measurement{
+			FromDateTime: time.Date(2021, 10, 1, 12, 0, 3, 0, time.UTC),
+			TillDateTime: time.Date(2021, 10, 1, 12, 2, 30, 0, time.UTC),
+			Values: []airly.Value{
+				{Name: "PM10", Value: 22},
+				{Name: "PM25", Value: 11},
+				{Name: "PRESSURE", Value: 1013.25},
+				{Name: "TEMPERATURE", Value: 12.5},
+			},
+		},
+		History:  []airly.Measurement{},
+		Forecast: []airly.Measurement{},
+	}, m)
+}
+
+func TestNearestMeasurementsNoLocation(t *testing.T) {
+	p := Provider{HttpClient: mockClient{func(req *http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: 200, Body: io.NopCloser(strings.NewReader("[]"))}, nil
+	}}}
+	_, err := p.NearestMeasurements(airly.Location{Latitude: 50.062006, Longitude: 19.940984})
+	assert.Equal(t, airly.ErrNoInstallation, err)
+}
+
+func TestError(t *testing.T) {
+	err := errors.New("error")
+	p := Provider{HttpClient: mockClient{func(req *http.Request) (*http.Response, error) {
+		return nil, err
+	}}}
+	_, err2 := p.NearestMeasurements(airly.Location{Latitude: 50.062006, Longitude: 19.940984})
+	assert.Equal(t, err, err2)
+
+	p = Provider{HttpClient: mockClient{func(req *http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: 500, Body: io.NopCloser(strings.NewReader("error"))}, nil
+	}}}
+	_, err2 = p.NearestInstallations(airly.Location{Latitude: 50.062006, Longitude: 19.940984})
+	assert.Equal(t, &airly.APIError{StatusCode: 500, Body: "error"}, err2)
+
+	p.MaxResponseSize = 2
+	_, err2 = p.NearestInstallations(airly.Location{Latitude: 50.062006, Longitude: 19.940984})
+	assert.Equal(t, &airly.ResponseTooLargeError{Limit: 2}, err2)
+}
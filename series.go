@@ -0,0 +1,96 @@
+package airly
+
+import (
+	"time"
+)
+
+// SeriesBuilder builds continuous series of installations in HistoryMerger by polling API. Each update checks the
+// latest stored window and fetches measurements only if it's older than the last completed hour, so after downtime
+// (e.g. on restart with HistoryMerger restored from JSON) missed hours are backfilled from 24 hours of history
+// returned by API. Older windows can't be recovered, they are reported in SeriesUpdate.Lost.
+// SeriesBuilder is not safe for concurrent use
+type SeriesBuilder struct {
+	Client        Client
+	Merger        *HistoryMerger
+	Installations []int
+	// Interval between updates in Run, 1 hour is used if not set
+	Interval time.Duration
+	// Options used to fetch measurements
+	Options []MeasurementsOption
+
+	now func() time.Time
+}
+
+// SeriesUpdate is result of SeriesBuilder update of a single installation
+type SeriesUpdate struct {
+	InstallationId int
+	// Added is number of windows which were not stored before
+	Added int
+	// Lost is time range between the latest window stored before update and the oldest window returned by API,
+	// which can't be backfilled anymore. It's zero if there is no such range
+	Lost Window
+	// Err of fetching measurements, nil if update succeeded or wasn't needed
+	Err error
+}
+
+// Update backfills series of all installations, installations with the last completed hour already stored are not
+// fetched
+func (b *SeriesBuilder) Update() []SeriesUpdate {
+	now := time.Now()
+	if b.now != nil {
+		now = b.now()
+	}
+	updates := make([]SeriesUpdate, 0, len(b.Installations))
+	for _, id := range b.Installations {
+		update := SeriesUpdate{InstallationId: id}
+		latest, stored := b.Merger.Latest(id)
+		if stored && !latest.End.Before(now.Truncate(time.Hour)) {
+			updates = append(updates, update)
+			continue
+		}
+		m, err := b.Client.InstallationMeasurements(id, b.Options...)
+		if err != nil {
+			update.Err = err
+			updates = append(updates, update)
+			continue
+		}
+		if stored {
+			if i := chronological(m.History); len(i) > 0 && m.History[i[0]].FromDateTime.After(latest.End) {
+				update.Lost = Window{Start: latest.End, End: m.History[i[0]].FromDateTime}
+			}
+		}
+		update.Added = b.Merger.Add(id, m)
+		updates = append(updates, update)
+	}
+	return updates
+}
+
+// Run calls Update every Interval (starting immediately) and sends results to returned channel until stop is
+// closed, then the channel is closed
+func (b *SeriesBuilder) Run(stop <-chan struct{}) <-chan SeriesUpdate {
+	interval := b.Interval
+	if interval == 0 {
+		interval = time.Hour
+	}
+	ch := make(chan SeriesUpdate)
+	go func() {
+		defer close(ch)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			for _, update := range b.Update() {
+				select {
+				case ch <- update:
+				case <-stop:
+					return
+				}
+			}
+			select {
+			case <-ticker.C:
+			case <-stop:
+				return
+			}
+		}
+	}()
+	return ch
+}
@@ -0,0 +1,81 @@
+package airly
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestSeriesBuilder(t *testing.T) {
+	h := func(hour int) time.Time {
+		return time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC).Add(time.Duration(hour) * time.Hour)
+	}
+	// API returns 24 hours of history ending at the current hour
+	history := func(now time.Time) Measurements {
+		var m Measurements
+		for t := now.Truncate(time.Hour).Add(-24 * time.Hour); t.Before(now.Truncate(time.Hour)); t = t.Add(time.Hour) {
+			m.History = append(m.History, window(t, PM25, 10))
+		}
+		return m
+	}
+	now := h(30).Add(10 * time.Minute)
+	requests := 0
+	var fail error
+	client := Client{HttpClient: mockClient{func(req *http.Request) (*http.Response, error) {
+		requests++
+		if fail != nil {
+			return nil, fail
+		}
+		body, err := json.Marshal(history(now))
+		assert.NoError(t, err)
+		return &http.Response{StatusCode: 200, Body: readCloser(string(body))}, nil
+	}}}
+
+	merger := &HistoryMerger{}
+	b := SeriesBuilder{Client: client, Merger: merger, Installations: []int{204}, now: func() time.Time { return now }}
+	assert.Equal(t, []SeriesUpdate{{InstallationId: 204, Added: 24}}, b.Update())
+	assert.Equal(t, []SeriesUpdate{{InstallationId: 204}}, b.Update())
+	assert.Equal(t, 1, requests, "up to date series should not be fetched")
+
+	// restart after 30 hours of downtime, the oldest 6 hours are lost
+	data, err := json.Marshal(merger)
+	assert.NoError(t, err)
+	restored := &HistoryMerger{}
+	assert.NoError(t, json.Unmarshal(data, restored))
+	now = h(60).Add(5 * time.Minute)
+	b = SeriesBuilder{Client: client, Merger: restored, Installations: []int{204}, now: func() time.Time { return now }}
+	assert.Equal(t, []SeriesUpdate{{InstallationId: 204, Added: 24, Lost: Window{Start: h(30), End: h(36)}}}, b.Update())
+	assert.Equal(t, []Window{{Start: h(30), End: h(36)}}, restored.Gaps(204))
+
+	// short downtime is backfilled completely
+	now = h(65)
+	assert.Equal(t, []SeriesUpdate{{InstallationId: 204, Added: 5}}, b.Update())
+	assert.Len(t, restored.History(204), 53)
+
+	fail = errors.New("connection refused")
+	now = h(66)
+	updates := b.Update()
+	assert.Len(t, updates, 1)
+	assert.ErrorIs(t, updates[0].Err, fail)
+}
+
+func TestSeriesBuilderRun(t *testing.T) {
+	b := SeriesBuilder{
+		Client: Client{HttpClient: mockClient{func(req *http.Request) (*http.Response, error) {
+			return nil, errors.New("connection refused")
+		}}},
+		Merger:        &HistoryMerger{},
+		Installations: []int{204},
+		Interval:      time.Millisecond,
+	}
+	stop := make(chan struct{})
+	ch := b.Run(stop)
+	assert.Error(t, (<-ch).Err)
+	assert.Error(t, (<-ch).Err)
+	close(stop)
+	for range ch {
+	}
+}
@@ -0,0 +1,109 @@
+package airly
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// StatsRecorder collects latencies and errors of API calls per endpoint in-process, e.g. to report health of
+// the application without Prometheus. Set it as Client.StatsRecorder (it can be shared by many clients) and read
+// statistics with Client.Stats. StatsRecorder is safe for concurrent use
+type StatsRecorder struct {
+	// Samples is number of the most recent latencies kept per endpoint for percentiles, 100 is used if not set
+	Samples int
+
+	mu        sync.Mutex
+	endpoints map[string]*endpointStats
+	now       func() time.Time
+}
+
+type endpointStats struct {
+	requests, errors int
+	lastError        time.Time
+	latencies        latencyWindow
+}
+
+// EndpointStats are statistics of API calls to a single endpoint. Requests and errors are counted since
+// StatsRecorder was created, latency percentiles are computed from the most recent calls
+type EndpointStats struct {
+	// Endpoint with installation IDs replaced by {id}, e.g. installations/{id}
+	Endpoint string `json:"endpoint"`
+	Requests int    `json:"requests"`
+	// Errors is number of failed calls, including API errors and responses that couldn't be decoded
+	Errors int `json:"errors"`
+	// ErrorRate is Errors divided by Requests
+	ErrorRate float64 `json:"errorRate"`
+	// LastError is time of the most recent failed call, zero if there were none
+	LastError time.Time     `json:"lastError"`
+	P50       time.Duration `json:"p50"`
+	P90       time.Duration `json:"p90"`
+	P99       time.Duration `json:"p99"`
+	Max       time.Duration `json:"max"`
+}
+
+// record adds API call to path which took latency and failed with err, if not nil
+func (r *StatsRecorder) record(path string, latency time.Duration, err error) {
+	samples := r.Samples
+	if samples == 0 {
+		samples = 100
+	}
+	name := summaryEndpoint(endpoint(path))
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.endpoints == nil {
+		r.endpoints = make(map[string]*endpointStats)
+	}
+	s := r.endpoints[name]
+	if s == nil {
+		s = &endpointStats{}
+		r.endpoints[name] = s
+	}
+	s.requests++
+	if err != nil {
+		s.errors++
+		s.lastError = time.Now()
+		if r.now != nil {
+			s.lastError = r.now()
+		}
+	}
+	s.latencies.add(latency, samples)
+}
+
+// Stats returns statistics of all endpoints called so far, sorted by endpoint
+func (r *StatsRecorder) Stats() []EndpointStats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stats := make([]EndpointStats, 0, len(r.endpoints))
+	for name, s := range r.endpoints {
+		latencies := s.latencies.sorted()
+		stats = append(stats, EndpointStats{
+			Endpoint:  name,
+			Requests:  s.requests,
+			Errors:    s.errors,
+			ErrorRate: float64(s.errors) / float64(s.requests),
+			LastError: s.lastError,
+			P50:       latencyPercentile(latencies, 0.5),
+			P90:       latencyPercentile(latencies, 0.9),
+			P99:       latencyPercentile(latencies, 0.99),
+			Max:       latencies[len(latencies)-1],
+		})
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Endpoint < stats[j].Endpoint })
+	return stats
+}
+
+// recordStats records API call to path started at start in StatsRecorder, if set
+func (c Client) recordStats(path string, start time.Time, err error) {
+	if c.StatsRecorder != nil {
+		c.StatsRecorder.record(path, time.Since(start), err)
+	}
+}
+
+// Stats returns statistics of API calls made with StatsRecorder, nil is returned if it's not set
+func (c Client) Stats() []EndpointStats {
+	if c.StatsRecorder == nil {
+		return nil
+	}
+	return c.StatsRecorder.Stats()
+}
@@ -0,0 +1,54 @@
+package airly
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestStatsRecorder(t *testing.T) {
+	failed := time.Date(2021, 3, 1, 12, 0, 0, 0, time.UTC)
+	r := &StatsRecorder{Samples: 10, now: func() time.Time { return failed }}
+	for i := 1; i <= 20; i++ {
+		r.record("measurements/installation?installationId=204", time.Duration(i)*time.Millisecond, nil)
+	}
+	r.record("installations/204", 30*time.Millisecond, errors.New("failed"))
+	r.record("installations/8077", 10*time.Millisecond, nil)
+
+	assert.Equal(t, []EndpointStats{
+		{Endpoint: "installations/{id}", Requests: 2, Errors: 1, ErrorRate: 0.5, LastError: failed,
+			P50: 10 * time.Millisecond, P90: 30 * time.Millisecond, P99: 30 * time.Millisecond, Max: 30 * time.Millisecond},
+		{Endpoint: "measurements/installation", Requests: 20,
+			P50: 15 * time.Millisecond, P90: 19 * time.Millisecond, P99: 20 * time.Millisecond, Max: 20 * time.Millisecond},
+	}, r.Stats())
+}
+
+func TestClientStats(t *testing.T) {
+	assert.Nil(t, Client{}.Stats())
+
+	client := Client{
+		StatsRecorder: &StatsRecorder{},
+		HttpClient: mockClient{func(req *http.Request) (*http.Response, error) {
+			if req.URL.Path == "/v2/installations/1" {
+				return &http.Response{StatusCode: 404, Body: readCloser("")}, nil
+			}
+			return &http.Response{StatusCode: 200, Body: readCloser(`{"id": 204}`)}, nil
+		}},
+	}
+	_, err := client.Installation(204)
+	assert.NoError(t, err)
+	_, err = client.Installation(1)
+	assert.Error(t, err)
+	_, err = client.IndexTypes()
+	assert.Error(t, err)
+
+	stats := client.Stats()
+	assert.Len(t, stats, 2)
+	assert.Equal(t, "installations/{id}", stats[0].Endpoint)
+	assert.Equal(t, 2, stats[0].Requests)
+	assert.Equal(t, 1, stats[0].Errors)
+	assert.Equal(t, "meta/indexes", stats[1].Endpoint)
+	assert.Equal(t, 1, stats[1].Errors)
+}
@@ -0,0 +1,60 @@
+package airly
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+)
+
+// maxRetryAfter is the longest delay requested with Retry-After for which IsRetryable returns true, longer delays
+// mean e.g. that daily limit is exceeded
+const maxRetryAfter = time.Minute
+
+// Temporary returns true for 429 Too Many Requests and 5xx statuses other than 501 Not Implemented, the same
+// request may succeed later. 4xx statuses like 401, 403 and 404 are permanent
+func (e *APIError) Temporary() bool {
+	return e.StatusCode == http.StatusTooManyRequests ||
+		(e.StatusCode >= 500 && e.StatusCode != http.StatusNotImplemented)
+}
+
+// IsTemporary returns true if err is caused by condition that may go away: temporary APIError (see
+// APIError.Temporary), timeout, refused, reset or unreachable connection, temporary DNS failure or connection closed
+// before the whole response was read. Cancelled requests, invalid arguments, permanent API errors, responses that
+// can't be decoded and other network errors like invalid certificates or unknown hosts are not temporary
+func IsTemporary(err error) bool {
+	var apiErr *APIError
+	var netErr net.Error
+	var dnsErr *net.DNSError
+	switch {
+	case err == nil:
+		return false
+	case errors.As(err, &apiErr):
+		return apiErr.Temporary()
+	case errors.Is(err, context.Canceled):
+		return false
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, io.ErrUnexpectedEOF):
+		return true
+	case errors.As(err, &netErr) && netErr.Timeout():
+		return true
+	case errors.As(err, &dnsErr):
+		return dnsErr.IsTemporary
+	case errors.Is(err, syscall.ECONNREFUSED), errors.Is(err, syscall.ECONNRESET), errors.Is(err, syscall.ECONNABORTED),
+		errors.Is(err, syscall.ENETUNREACH), errors.Is(err, syscall.EHOSTUNREACH):
+		return true
+	}
+	return false
+}
+
+// IsRetryable returns true if request failed with err should be retried: err is temporary (see IsTemporary) and
+// API didn't ask to wait longer than a minute, which happens e.g. when daily limit is exceeded
+func IsRetryable(err error) bool {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.RetryAfter > maxRetryAfter {
+		return false
+	}
+	return IsTemporary(err)
+}
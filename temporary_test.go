@@ -0,0 +1,55 @@
+package airly
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestIsTemporary(t *testing.T) {
+	dialErr := &url.Error{Op: "Get", URL: "https://airapi.airly.eu/v2/meta/indexes",
+		Err: &net.OpError{Op: "dial", Net: "tcp", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)}}
+	resetErr := &url.Error{Op: "Get", URL: "https://airapi.airly.eu/v2/meta/indexes",
+		Err: &net.OpError{Op: "read", Net: "tcp", Err: os.NewSyscallError("read", syscall.ECONNRESET)}}
+	certErr := &url.Error{Op: "Get", URL: "https://airapi.airly.eu/v2/meta/indexes",
+		Err: x509.CertificateInvalidError{Reason: x509.Expired}}
+	_, schemeErr := http.Get("ftp://airapi.airly.eu/v2/meta/indexes")
+	for _, tc := range []struct {
+		err                  error
+		temporary, retryable bool
+	}{
+		{&RequestError{Err: &APIError{StatusCode: 503}}, true, true},
+		{&APIError{StatusCode: 500}, true, true},
+		{&APIError{StatusCode: 429, RetryAfter: 30 * time.Second}, true, true},
+		{&APIError{StatusCode: 429, RetryAfter: 12 * time.Hour}, true, false},
+		{&APIError{StatusCode: 501}, false, false},
+		{&RequestError{Err: &APIError{StatusCode: 401}}, false, false},
+		{&APIError{StatusCode: 403}, false, false},
+		{&APIError{StatusCode: 404}, false, false},
+		{&RequestError{Err: dialErr}, true, true},
+		{resetErr, true, true},
+		{&url.Error{Op: "Get", Err: &net.DNSError{Err: "server misbehaving", IsTemporary: true}}, true, true},
+		{&url.Error{Op: "Get", Err: &net.DNSError{Err: "no such host", IsNotFound: true}}, false, false},
+		{&RequestError{Err: certErr}, false, false},
+		{schemeErr, false, false},
+		{&url.Error{Op: "Get", Err: context.DeadlineExceeded}, true, true},
+		{&url.Error{Op: "Get", Err: context.Canceled}, false, false},
+		{fmt.Errorf("read: %w", io.ErrUnexpectedEOF), true, true},
+		{&DecodeError{Err: errors.New("invalid character")}, false, false},
+		{fmt.Errorf("lat 91: %w", ErrInvalidLocation), false, false},
+		{nil, false, false},
+	} {
+		assert.Equal(t, tc.temporary, IsTemporary(tc.err), fmt.Sprint(tc.err))
+		assert.Equal(t, tc.retryable, IsRetryable(tc.err), fmt.Sprint(tc.err))
+	}
+}
@@ -0,0 +1,89 @@
+// Package units converts values between units commonly used for air quality data
+package units
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// MolarVolume in liters of ideal gas at 25°C and 1013.25 hPa, used for µg/m³ to ppb conversion
+const MolarVolume = 24.45
+
+// mmHgPerHPa is number of millimeters of mercury in one hectopascal
+const mmHgPerHPa = 0.750061683
+
+// molarMasses of gases in g/mol keyed by Airly value names
+var molarMasses = map[string]float64{
+	"NO2":  46.0055,
+	"O3":   47.9982,
+	"SO2":  64.066,
+	"CO":   28.010,
+	"C6H6": 78.11,
+}
+
+// ErrUnknownPollutant is returned when converting pollutant with unknown molar mass
+var ErrUnknownPollutant = errors.New("molar mass unknown")
+
+// MolarMass returns molar mass in g/mol of gaseous pollutant with given Airly value name, e.g. NO2
+func MolarMass(pollutant string) (float64, bool) {
+	m, ok := molarMasses[strings.ToUpper(pollutant)]
+	return m, ok
+}
+
+func molarMass(pollutant string) (float64, error) {
+	m, ok := MolarMass(pollutant)
+	if !ok {
+		return 0, fmt.Errorf("%s: %w", pollutant, ErrUnknownPollutant)
+	}
+	return m, nil
+}
+
+// ToPPB converts concentration of pollutant from µg/m³ to ppb
+func ToPPB(pollutant string, value float64) (float64, error) {
+	m, err := molarMass(pollutant)
+	if err != nil {
+		return 0, err
+	}
+	return value * MolarVolume / m, nil
+}
+
+// ToPPM converts concentration of pollutant from µg/m³ to ppm
+func ToPPM(pollutant string, value float64) (float64, error) {
+	ppb, err := ToPPB(pollutant, value)
+	return ppb / 1000, err
+}
+
+// FromPPB converts concentration of pollutant from ppb to µg/m³
+func FromPPB(pollutant string, value float64) (float64, error) {
+	m, err := molarMass(pollutant)
+	if err != nil {
+		return 0, err
+	}
+	return value * m / MolarVolume, nil
+}
+
+// FromPPM converts concentration of pollutant from ppm to µg/m³
+func FromPPM(pollutant string, value float64) (float64, error) {
+	return FromPPB(pollutant, value*1000)
+}
+
+// HPaToMmHg converts pressure from hPa to mmHg
+func HPaToMmHg(hPa float64) float64 {
+	return hPa * mmHgPerHPa
+}
+
+// MmHgToHPa converts pressure from mmHg to hPa
+func MmHgToHPa(mmHg float64) float64 {
+	return mmHg / mmHgPerHPa
+}
+
+// CelsiusToFahrenheit converts temperature from °C to °F
+func CelsiusToFahrenheit(c float64) float64 {
+	return c*9/5 + 32
+}
+
+// FahrenheitToCelsius converts temperature from °F to °C
+func FahrenheitToCelsius(f float64) float64 {
+	return (f - 32) * 5 / 9
+}
@@ -0,0 +1,48 @@
+package units
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestPPB(t *testing.T) {
+	ppb, err := ToPPB("NO2", 40)
+	assert.Nil(t, err)
+	assert.InDelta(t, 21.26, ppb, 0.01)
+	ugm3, err := FromPPB("no2", ppb)
+	assert.Nil(t, err)
+	assert.InDelta(t, 40, ugm3, 1e-9)
+
+	ppm, err := ToPPM("CO", 1145.5)
+	assert.Nil(t, err)
+	assert.InDelta(t, 1, ppm, 0.001)
+	ugm3, err = FromPPM("CO", 1)
+	assert.Nil(t, err)
+	assert.InDelta(t, 1145.6, ugm3, 0.1)
+
+	_, err = ToPPB("PM25", 10)
+	assert.ErrorIs(t, err, ErrUnknownPollutant)
+	assert.EqualError(t, err, "PM25: molar mass unknown")
+	_, err = FromPPM("PM10", 10)
+	assert.ErrorIs(t, err, ErrUnknownPollutant)
+}
+
+func TestMolarMass(t *testing.T) {
+	m, ok := MolarMass("o3")
+	assert.True(t, ok)
+	assert.Equal(t, 47.9982, m)
+	_, ok = MolarMass("PM1")
+	assert.False(t, ok)
+}
+
+func TestPressure(t *testing.T) {
+	assert.InDelta(t, 760, HPaToMmHg(1013.25), 0.001)
+	assert.InDelta(t, 1013.25, MmHgToHPa(760), 0.001)
+}
+
+func TestTemperature(t *testing.T) {
+	assert.Equal(t, 32.0, CelsiusToFahrenheit(0))
+	assert.Equal(t, 212.0, CelsiusToFahrenheit(100))
+	assert.Equal(t, -40.0, FahrenheitToCelsius(-40))
+	assert.InDelta(t, 21.5, FahrenheitToCelsius(CelsiusToFahrenheit(21.5)), 1e-9)
+}
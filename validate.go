@@ -0,0 +1,118 @@
+package airly
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ValidationError lists all invariants violated by measurements, see Measurements.Validate
+type ValidationError struct {
+	Errors []error
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		messages[i] = err.Error()
+	}
+	return "invalid measurements: " + strings.Join(messages, "; ")
+}
+
+// concentrations are names of values which can't be negative
+var concentrations = map[string]bool{
+	PM1: true, PM25: true, PM10: true, NO2: true, O3: true, SO2: true, CO: true, C6H6: true, Humidity: true,
+}
+
+// DefaultIndexTypes are level ranges of AIRLY_CAQI index used by Validate if no index types are given
+var DefaultIndexTypes = []IndexType{{Name: "AIRLY_CAQI", Levels: []Level{
+	{Values: "0-25", Level: "VERY_LOW"},
+	{Values: "25-50", Level: "LOW"},
+	{Values: "50-75", Level: "MEDIUM"},
+	{Values: "75-87.5", Level: "HIGH"},
+	{Values: "87.5-100", Level: "VERY_HIGH"},
+	{Values: "100-125", Level: "EXTREME"},
+	{Values: "125+", Level: "AIRMAGEDDON"},
+}}}
+
+// Validate checks that every measurement window ends after it starts, concentrations are not negative, index values
+// are within ranges of their levels and history and forecast are sorted without overlaps. Index ranges are taken from
+// indexTypes (e.g. returned by Client.IndexTypes), DefaultIndexTypes are used if none are given, indexes of unknown
+// types are not checked. All violations are returned as *ValidationError, nil is returned for valid measurements
+func (m Measurements) Validate(indexTypes ...IndexType) error {
+	if len(indexTypes) == 0 {
+		indexTypes = DefaultIndexTypes
+	}
+	levels := map[string]Level{}
+	for _, t := range indexTypes {
+		for _, l := range t.Levels {
+			levels[t.Name+"/"+l.Level] = l
+		}
+	}
+	var errs []error
+	add := func(path string, format string, args ...interface{}) {
+		errs = append(errs, fmt.Errorf("%s: %s", path, fmt.Sprintf(format, args...)))
+	}
+	check := func(path string, measurement Measurement) {
+		if !measurement.FromDateTime.Before(measurement.TillDateTime) {
+			add(path, "fromDateTime %s not before tillDateTime %s", measurement.FromDateTime, measurement.TillDateTime)
+		}
+		for _, v := range measurement.Values {
+			switch {
+			case v.Null:
+			case math.IsNaN(v.Value) || math.IsInf(v.Value, 0):
+				add(path, "%s is %v", v.Name, v.Value)
+			case v.Value < 0 && concentrations[CanonicalName(v.Name)]:
+				add(path, "negative %s %v", v.Name, v.Value)
+			}
+		}
+		for _, i := range measurement.Indexes {
+			l, ok := levels[i.Name+"/"+i.Level]
+			if !ok {
+				continue
+			}
+			if min, max, ok := levelRange(l.Values); ok && (i.Value < min || i.Value > max) {
+				add(path, "%s %v outside of %s range %s", i.Name, i.Value, i.Level, l.Values)
+			}
+		}
+	}
+	series := func(name string, measurements []Measurement) {
+		for i, measurement := range measurements {
+			path := name + "[" + strconv.Itoa(i) + "]"
+			check(path, measurement)
+			if i > 0 && measurement.FromDateTime.Before(measurements[i-1].TillDateTime) {
+				add(path, "starts at %s before end of previous window %s", measurement.FromDateTime,
+					measurements[i-1].TillDateTime)
+			}
+		}
+	}
+	series("history", m.History)
+	if !m.Current.FromDateTime.IsZero() || !m.Current.TillDateTime.IsZero() || len(m.Current.Values) > 0 {
+		check("current", m.Current)
+	}
+	series("forecast", m.Forecast)
+	if len(errs) > 0 {
+		return &ValidationError{Errors: errs}
+	}
+	return nil
+}
+
+// levelRange parses range of level values in format used by Airly: 0-25, 125+ (without upper bound) or single value
+func levelRange(values string) (min, max float64, ok bool) {
+	var err error
+	values = strings.TrimSpace(values)
+	if strings.HasSuffix(values, "+") {
+		min, err = strconv.ParseFloat(strings.TrimSuffix(values, "+"), 64)
+		return min, math.Inf(1), err == nil
+	}
+	parts := strings.SplitN(values, "-", 2)
+	if min, err = strconv.ParseFloat(strings.TrimSpace(parts[0]), 64); err != nil {
+		return 0, 0, false
+	}
+	if len(parts) == 1 {
+		return min, min, true
+	}
+	max, err = strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+	return min, max, err == nil
+}
@@ -0,0 +1,61 @@
+package airly
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestMeasurementsValidate(t *testing.T) {
+	h := func(hour int) time.Time {
+		return time.Date(2021, 3, 1, hour, 0, 0, 0, time.UTC)
+	}
+	m := Measurements{
+		History: []Measurement{window(h(9), PM25, 10), window(h(10), PM25, 12)},
+		Current: Measurement{
+			FromDateTime: h(11),
+			TillDateTime: h(12),
+			Values:       []Value{{Name: PM25, Value: 18.7}, {Name: PM10, Null: true}, {Name: "TEMPERATURE", Value: -5}},
+			Indexes:      []Index{{Name: "AIRLY_CAQI", Value: 35.53, Level: "LOW"}, {Name: "PIJP", Value: 7, Level: "LOW"}},
+		},
+		Forecast: []Measurement{window(h(12), PM25, 20)},
+	}
+	assert.NoError(t, m.Validate())
+	assert.NoError(t, Measurements{}.Validate())
+
+	m.History = []Measurement{window(h(10), PM25, 12), window(h(9), PM25, -1)}
+	m.Current.TillDateTime = h(11)
+	m.Current.Indexes[0].Value = 60
+	m.Forecast[0].Values[0].Value = math.NaN()
+	err := m.Validate()
+	var validationErr *ValidationError
+	if assert.True(t, errors.As(err, &validationErr)) {
+		var messages []string
+		for _, e := range validationErr.Errors {
+			messages = append(messages, e.Error())
+		}
+		assert.Equal(t, []string{
+			"history[1]: negative PM25 -1",
+			"history[1]: starts at 2021-03-01 09:00:00 +0000 UTC before end of previous window 2021-03-01 11:00:00 +0000 UTC",
+			"current: fromDateTime 2021-03-01 11:00:00 +0000 UTC not before tillDateTime 2021-03-01 11:00:00 +0000 UTC",
+			"current: AIRLY_CAQI 60 outside of LOW range 25-50",
+			"forecast[0]: PM25 is NaN",
+		}, messages)
+	}
+
+	pijp := IndexType{Name: "PIJP", Levels: []Level{{Values: "0-1", Level: "LOW"}}}
+	err = Measurements{Current: Measurement{FromDateTime: h(11), TillDateTime: h(12),
+		Indexes: []Index{{Name: "PIJP", Value: 7, Level: "LOW"}}}}.Validate(pijp)
+	assert.EqualError(t, err, "invalid measurements: current: PIJP 7 outside of LOW range 0-1")
+}
+
+func TestLevelRange(t *testing.T) {
+	min, max, ok := levelRange("87.5-100")
+	assert.Equal(t, []interface{}{87.5, 100.0, true}, []interface{}{min, max, ok})
+	min, max, ok = levelRange("125+")
+	assert.Equal(t, []interface{}{125.0, math.Inf(1), true}, []interface{}{min, max, ok})
+	_, _, ok = levelRange("low")
+	assert.False(t, ok)
+}
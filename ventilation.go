@@ -0,0 +1,103 @@
+package airly
+
+import (
+	"time"
+)
+
+// VentilationAction recommended by Ventilation
+type VentilationAction int
+
+const (
+	// KeepClosed means air is bad now and it's not expected to get better within forecast
+	KeepClosed VentilationAction = iota
+	// OpenWindowsNow means air is good now
+	OpenWindowsNow
+	// WaitUntil means air is bad now, but it's expected to get better at VentilationAdvice.Time
+	WaitUntil
+	// NoData means current measurement has neither values nor indexes, e.g. sensor is offline, so air can't be
+	// assessed
+	NoData
+)
+
+func (a VentilationAction) String() string {
+	switch a {
+	case OpenWindowsNow:
+		return "OpenWindowsNow"
+	case WaitUntil:
+		return "WaitUntil"
+	case NoData:
+		return "NoData"
+	default:
+		return "KeepClosed"
+	}
+}
+
+// VentilationThresholds define acceptable outdoor air
+type VentilationThresholds struct {
+	// Index used to assess air, AIRLY_CAQI is used if empty
+	Index string
+	// MaxIndex is the highest acceptable index value, it's not checked if zero. Measurements without the index are
+	// not acceptable if it's set
+	MaxIndex float64
+	// MaxValues are the highest acceptable values by name, e.g. PM25: 25. Missing or null values are accepted
+	MaxValues map[string]float64
+	// MaxWait limits how far in the future forecast is checked, the whole forecast is checked if zero
+	MaxWait time.Duration
+}
+
+// VentilationAdvice returned by Ventilation
+type VentilationAdvice struct {
+	Action VentilationAction
+	// Time until which windows can stay open (OpenWindowsNow) or when they can be opened (WaitUntil), zero if unknown
+	Time time.Time
+}
+
+// accepts returns true if measurement doesn't exceed thresholds
+func (t VentilationThresholds) accepts(m Measurement) bool {
+	name := t.Index
+	if name == "" {
+		name = "AIRLY_CAQI"
+	}
+	if v, ok := indexValue(m, name); t.MaxIndex > 0 && (!ok || v > t.MaxIndex) {
+		return false
+	}
+	for name, limit := range t.MaxValues {
+		if v, ok := m.Value(name); ok && v > limit {
+			return false
+		}
+	}
+	return true
+}
+
+// Ventilation recommends whether to open windows based on current and forecast outdoor air. If current air is
+// acceptable advice is OpenWindowsNow with time when forecast exceeds thresholds, otherwise it's WaitUntil start of
+// the first acceptable forecast window or KeepClosed if there is none. NoData is returned if current measurement
+// has neither values nor indexes
+func Ventilation(m Measurements, t VentilationThresholds) VentilationAdvice {
+	if len(m.Current.Values) == 0 && len(m.Current.Indexes) == 0 {
+		return VentilationAdvice{Action: NoData}
+	}
+	now := t.accepts(m.Current)
+	advice := VentilationAdvice{Action: KeepClosed}
+	if now {
+		advice.Action = OpenWindowsNow
+	}
+	limit := m.Current.TillDateTime.Add(t.MaxWait)
+	for _, i := range chronological(m.Forecast) {
+		f := m.Forecast[i]
+		if !f.TillDateTime.After(m.Current.TillDateTime) {
+			continue
+		}
+		if t.MaxWait > 0 && f.FromDateTime.After(limit) {
+			break
+		}
+		if t.accepts(f) != now {
+			advice.Time = f.FromDateTime
+			if !now {
+				advice.Action = WaitUntil
+			}
+			break
+		}
+	}
+	return advice
+}
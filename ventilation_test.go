@@ -0,0 +1,50 @@
+package airly
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestVentilation(t *testing.T) {
+	h := func(hour int) time.Time {
+		return time.Date(2021, 3, 1, hour, 0, 0, 0, time.UTC)
+	}
+	measurement := func(hour int, caqi, pm25 float64) Measurement {
+		return Measurement{FromDateTime: h(hour), TillDateTime: h(hour + 1),
+			Values:  []Value{{Name: PM25, Value: pm25}},
+			Indexes: []Index{{Name: "AIRLY_CAQI", Value: caqi}}}
+	}
+	thresholds := VentilationThresholds{MaxIndex: 50, MaxValues: map[string]float64{PM25: 25}}
+	m := Measurements{
+		Current:  measurement(10, 30, 10),
+		Forecast: []Measurement{measurement(13, 30, 30), measurement(11, 30, 10), measurement(12, 40, 20)},
+	}
+	assert.Equal(t, VentilationAdvice{Action: OpenWindowsNow, Time: h(13)}, Ventilation(m, thresholds))
+
+	m.Current = measurement(10, 60, 10)
+	m.Forecast = []Measurement{measurement(11, 70, 40), measurement(12, 55, 20), measurement(13, 45, 20)}
+	assert.Equal(t, VentilationAdvice{Action: WaitUntil, Time: h(13)}, Ventilation(m, thresholds))
+	thresholds.MaxWait = time.Hour
+	assert.Equal(t, VentilationAdvice{Action: KeepClosed}, Ventilation(m, thresholds))
+
+	m.Current.Values[0].Null = true
+	m.Current.Values[0].Value = 0
+	assert.Equal(t, KeepClosed, Ventilation(m, thresholds).Action)
+	assert.Equal(t, OpenWindowsNow, Ventilation(m, VentilationThresholds{MaxValues: map[string]float64{PM25: 5}}).Action)
+	assert.Equal(t, "WaitUntil", WaitUntil.String())
+}
+
+func TestVentilationMissingData(t *testing.T) {
+	h := func(hour int) time.Time {
+		return time.Date(2021, 3, 1, hour, 0, 0, 0, time.UTC)
+	}
+	m := Measurements{Current: Measurement{FromDateTime: h(10), TillDateTime: h(11)}}
+	assert.Equal(t, VentilationAdvice{Action: NoData}, Ventilation(m, VentilationThresholds{MaxIndex: 50}))
+	assert.Equal(t, NoData, Ventilation(m, VentilationThresholds{}).Action)
+	assert.Equal(t, "NoData", NoData.String())
+
+	m.Current.Values = []Value{{Name: PM25, Value: 10}}
+	assert.Equal(t, KeepClosed, Ventilation(m, VentilationThresholds{MaxIndex: 50}).Action)
+	assert.Equal(t, OpenWindowsNow, Ventilation(m, VentilationThresholds{MaxValues: map[string]float64{PM25: 25}}).Action)
+}
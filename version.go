@@ -0,0 +1,56 @@
+package airly
+
+import (
+	"encoding/json"
+	"strings"
+	"sync"
+)
+
+// APIVersion of Airly API, used as path prefix of requests
+type APIVersion string
+
+// APIv2 is the current version of Airly API, used by default
+const APIv2 APIVersion = "v2"
+
+// path returns version normalized to be used as path element, APIv2 is used for empty version
+func (v APIVersion) path() string {
+	p := strings.Trim(string(v), "/")
+	if p == "" {
+		return string(APIv2)
+	}
+	return p
+}
+
+// Decoder decodes response body of API endpoint (path without version and query, e.g. measurements/nearest)
+// into v, which is one of types returned by Client methods. It allows adapting responses of other API versions
+// to types of this package
+type Decoder func(endpoint string, body []byte, v interface{}) error
+
+var (
+	decodersMu sync.RWMutex
+	decoders   = map[string]Decoder{}
+)
+
+// RegisterDecoder registers decoder of responses of given API version, responses of versions without registered
+// decoder are decoded as JSON into types of this package, which matches APIv2
+func RegisterDecoder(version APIVersion, decoder Decoder) {
+	decodersMu.Lock()
+	defer decodersMu.Unlock()
+	decoders[version.path()] = decoder
+}
+
+// decode decodes response body of path with decoder registered for version, errors are returned as *DecodeError.
+// If strict is set, JSON decoding fails on unknown fields
+func decode(version APIVersion, path string, body []byte, v interface{}, strict bool) error {
+	path = endpoint(path)
+	decodersMu.RLock()
+	decoder, ok := decoders[version.path()]
+	decodersMu.RUnlock()
+	var err error
+	if ok {
+		err = decoder(path, body, v)
+	} else if err = json.Unmarshal(body, v); err == nil && strict {
+		err = checkUnknownFields(body, v)
+	}
+	return newDecodeError(path, body, err)
+}
@@ -0,0 +1,51 @@
+package airly
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+func TestAPIVersion(t *testing.T) {
+	assert.Equal(t, "v2", APIVersion("").path())
+	assert.Equal(t, "v3", APIVersion("/v3/").path())
+
+	var url string
+	api := Client{
+		APIVersion: "v3",
+		HttpClient: mockClient{func(req *http.Request) (*http.Response, error) {
+			url = req.URL.String()
+			return &http.Response{StatusCode: 200, Body: readCloser(`{"installation": {"id": 204}}`)}, nil
+		}}}
+
+	// without decoder v3 response is decoded as v2 one
+	i, err := api.Installation(204)
+	assert.Nil(t, err)
+	assert.Equal(t, "https://airapi.airly.eu/v3/installations/204", url)
+	assert.Equal(t, 0, i.Id)
+
+	var endpoints []string
+	RegisterDecoder("v3", func(endpoint string, body []byte, v interface{}) error {
+		endpoints = append(endpoints, endpoint)
+		if i, ok := v.(*Installation); ok {
+			var wrapper struct {
+				Installation Installation `json:"installation"`
+			}
+			err := json.Unmarshal(body, &wrapper)
+			*i = wrapper.Installation
+			return err
+		}
+		return json.Unmarshal(body, v)
+	})
+	defer func() {
+		decodersMu.Lock()
+		delete(decoders, "v3")
+		decodersMu.Unlock()
+	}()
+	i, err = api.Installation(204)
+	assert.Nil(t, err)
+	assert.Equal(t, 204, i.Id)
+	_, _ = api.InstallationMeasurements(204)
+	assert.Equal(t, []string{"installations/204", "measurements/installation"}, endpoints)
+}
@@ -0,0 +1,223 @@
+package airly
+
+import (
+	"errors"
+	"net/http"
+	"time"
+)
+
+// ChangeKind describes what changed in installation metadata
+type ChangeKind string
+
+const (
+	// AddressChanged is reported when any part of installation address changes
+	AddressChanged ChangeKind = "address"
+	// SponsorChanged is reported when installation sponsor changes
+	SponsorChanged ChangeKind = "sponsor"
+	// InstallationMoved is reported when installation location changes more than InstallationWatcher.MoveThreshold
+	InstallationMoved ChangeKind = "moved"
+	// InstallationDecommissioned is reported when API responds with 404 for installation or, if
+	// InstallationWatcher.StaleAfter is set, when it stops updating measurements. Decommissioned installations are
+	// still checked, so reactivation can be reported
+	InstallationDecommissioned ChangeKind = "decommissioned"
+	// InstallationReactivated is reported when decommissioned installation is available and, if
+	// InstallationWatcher.StaleAfter is set, updating measurements again
+	InstallationReactivated ChangeKind = "reactivated"
+	// InstallationAdded is reported by AreaWatcher when installation appears in watched area
+	InstallationAdded ChangeKind = "added"
+)
+
+// InstallationChange is a single change detected by InstallationWatcher or AreaWatcher, Current is empty for
+// InstallationDecommissioned and Previous is empty for InstallationAdded. Previous of InstallationReactivated is
+// the last version seen before decommissioning
+type InstallationChange struct {
+	Kind           ChangeKind
+	InstallationId int
+	Previous       Installation
+	Current        Installation
+	// LastUpdate is end of the newest measurement with values (or time of the first check if there was none), it's
+	// set for InstallationDecommissioned reported because installation stopped updating and, if
+	// InstallationWatcher.StaleAfter is set, for InstallationReactivated
+	LastUpdate time.Time
+}
+
+// InstallationWatcher periodically fetches metadata of monitored installations and reports changes, as silent
+// relocations of sensors corrupt long-term series. It's not safe for concurrent use
+type InstallationWatcher struct {
+	Client        Client
+	Installations []int
+	// Interval between checks in Watch, 24 hours is used if not set
+	Interval time.Duration
+	// MoveThreshold in km, location changes below it are not reported, 0.05 is used if not set
+	MoveThreshold float64
+	// OnError is called with errors other than 404, if set
+	OnError func(installationId int, err error)
+	// StaleAfter makes watcher fetch measurements as well and report installation as decommissioned when it has
+	// no new measurements for that long, e.g. so its series can be closed with HistoryMerger.Close, and as
+	// reactivated when new measurements appear. Measurements are not checked if not set
+	StaleAfter time.Duration
+	// Archive records every fetched version of installations, if set
+	Archive *InstallationArchive
+
+	known          map[int]Installation
+	decommissioned map[int]bool
+	updated        map[int]time.Time
+	now            func() time.Time
+}
+
+// Check fetches monitored installations once and returns changes since previous check,
+// the first check only records installations' state (and reports installations that don't exist).
+// Errors don't stop checking other installations, they are passed to OnError and the first one is returned
+func (w *InstallationWatcher) Check() ([]InstallationChange, error) {
+	if w.known == nil {
+		w.known = map[int]Installation{}
+		w.decommissioned = map[int]bool{}
+		w.updated = map[int]time.Time{}
+	}
+	var changes []InstallationChange
+	var firstErr error
+	for _, id := range w.Installations {
+		c, err := w.check(id)
+		changes = append(changes, c...)
+		if err != nil {
+			if w.OnError != nil {
+				w.OnError(id, err)
+			}
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+	return changes, firstErr
+}
+
+func (w *InstallationWatcher) check(id int) ([]InstallationChange, error) {
+	current, err := w.Client.Installation(id)
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+		if w.decommissioned[id] {
+			return nil, nil
+		}
+		w.decommissioned[id] = true
+		return []InstallationChange{{Kind: InstallationDecommissioned, InstallationId: id, Previous: w.known[id]}}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	stale := false
+	if w.StaleAfter > 0 {
+		// known is updated only after measurements are fetched, so changes are reported in the next check
+		// if it fails
+		if stale, err = w.stale(id); err != nil {
+			return nil, err
+		}
+	}
+	previous, ok := w.known[id]
+	w.known[id] = current
+	if w.Archive != nil {
+		w.Archive.Observe(current, w.currentTime())
+	}
+	if stale {
+		if w.decommissioned[id] {
+			return nil, nil
+		}
+		w.decommissioned[id] = true
+		return []InstallationChange{{Kind: InstallationDecommissioned, InstallationId: id, Previous: current,
+			LastUpdate: w.updated[id]}}, nil
+	}
+	if w.decommissioned[id] {
+		delete(w.decommissioned, id)
+		return []InstallationChange{{Kind: InstallationReactivated, InstallationId: id, Previous: previous,
+			Current: current, LastUpdate: w.updated[id]}}, nil
+	}
+	if !ok {
+		return nil, nil
+	}
+	return w.diff(previous, current), nil
+}
+
+// stale fetches measurements of installation and returns true if it has no measurements with values newer than
+// StaleAfter
+func (w *InstallationWatcher) stale(id int) (bool, error) {
+	m, err := w.Client.InstallationMeasurements(id)
+	if err != nil {
+		return false, err
+	}
+	now := w.currentTime()
+	for _, measurement := range append(m.History, m.Current) {
+		if len(measurement.Values) > 0 && measurement.TillDateTime.After(w.updated[id]) {
+			w.updated[id] = measurement.TillDateTime
+		}
+	}
+	if w.updated[id].IsZero() {
+		w.updated[id] = now
+	}
+	return now.Sub(w.updated[id]) > w.StaleAfter, nil
+}
+
+func (w *InstallationWatcher) diff(previous, current Installation) []InstallationChange {
+	threshold := w.MoveThreshold
+	if threshold == 0 {
+		threshold = 0.05
+	}
+	var changes []InstallationChange
+	change := func(kind ChangeKind) {
+		changes = append(changes, InstallationChange{Kind: kind, InstallationId: current.Id, Previous: previous,
+			Current: current})
+	}
+	if previous.Address != current.Address {
+		change(AddressChanged)
+	}
+	if previous.Sponsor != current.Sponsor {
+		change(SponsorChanged)
+	}
+	if previous.Location.Distance(current.Location) > threshold {
+		change(InstallationMoved)
+	}
+	return changes
+}
+
+// Watch runs Check every Interval (starting immediately) and sends detected changes to returned channel
+// until stop is closed, then the channel is closed. Errors are passed to OnError and installations are
+// checked again in the next round
+func (w *InstallationWatcher) Watch(stop <-chan struct{}) <-chan InstallationChange {
+	return watch(w.Interval, w.Check, stop)
+}
+
+// currentTime returns current time, it can be overridden in tests
+func (w *InstallationWatcher) currentTime() time.Time {
+	if w.now != nil {
+		return w.now()
+	}
+	return time.Now()
+}
+
+// watch runs check every interval (24 hours if 0, starting immediately) and sends changes to returned channel
+// until stop is closed
+func watch(interval time.Duration, check func() ([]InstallationChange, error), stop <-chan struct{}) <-chan InstallationChange {
+	if interval == 0 {
+		interval = 24 * time.Hour
+	}
+	ch := make(chan InstallationChange)
+	go func() {
+		defer close(ch)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			changes, _ := check()
+			for _, change := range changes {
+				select {
+				case ch <- change:
+				case <-stop:
+					return
+				}
+			}
+			select {
+			case <-ticker.C:
+			case <-stop:
+				return
+			}
+		}
+	}()
+	return ch
+}
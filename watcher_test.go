@@ -0,0 +1,234 @@
+package airly
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestInstallationWatcher(t *testing.T) {
+	responses := map[string]string{
+		"/v2/installations/204":  `{"id": 204, "location": {"latitude": 50.062006, "longitude": 19.940984}, "address": {"street": "Mikołajska"}, "sponsor": {"name": "A"}}`,
+		"/v2/installations/8077": `{"id": 8077, "location": {"latitude": 50.0, "longitude": 19.9}}`,
+	}
+	w := InstallationWatcher{
+		Client: Client{
+			Key: "x1234x",
+			HttpClient: mockClient{func(req *http.Request) (*http.Response, error) {
+				body, ok := responses[req.URL.Path]
+				if !ok {
+					return &http.Response{StatusCode: 404, Body: readCloser("not found")}, nil
+				}
+				return &http.Response{StatusCode: 200, Body: readCloser(body)}, nil
+			}},
+		},
+		Installations: []int{204, 8077, 1},
+	}
+
+	changes, err := w.Check()
+	assert.Nil(t, err)
+	assert.Equal(t, []InstallationChange{{Kind: InstallationDecommissioned, InstallationId: 1}}, changes)
+
+	// moved by ~10m, below threshold
+	responses["/v2/installations/204"] = `{"id": 204, "location": {"latitude": 50.062096, "longitude": 19.940984}, "address": {"street": "Mikołajska"}, "sponsor": {"name": "B"}}`
+	changes, err = w.Check()
+	assert.Nil(t, err)
+	assert.Len(t, changes, 1)
+	assert.Equal(t, SponsorChanged, changes[0].Kind)
+	assert.Equal(t, "A", changes[0].Previous.Sponsor.Name)
+	assert.Equal(t, "B", changes[0].Current.Sponsor.Name)
+
+	responses["/v2/installations/204"] = `{"id": 204, "location": {"latitude": 50.072006, "longitude": 19.940984}, "address": {"street": "Floriańska"}, "sponsor": {"name": "B"}}`
+	delete(responses, "/v2/installations/8077")
+	changes, err = w.Check()
+	assert.Nil(t, err)
+	kinds := map[ChangeKind]int{}
+	for _, c := range changes {
+		kinds[c.Kind] = c.InstallationId
+	}
+	assert.Equal(t, map[ChangeKind]int{AddressChanged: 204, InstallationMoved: 204, InstallationDecommissioned: 8077}, kinds)
+
+	changes, err = w.Check()
+	assert.Nil(t, err)
+	assert.Empty(t, changes)
+}
+
+func TestInstallationWatcherErrors(t *testing.T) {
+	err := errors.New("error")
+	var reported []int
+	w := InstallationWatcher{
+		Client: Client{
+			Key: "x1234x",
+			HttpClient: mockClient{func(req *http.Request) (*http.Response, error) {
+				return nil, err
+			}},
+		},
+		Installations: []int{204, 8077},
+		OnError: func(installationId int, err error) {
+			reported = append(reported, installationId)
+		},
+	}
+	_, err2 := w.Check()
+	assert.ErrorIs(t, err2, err)
+	assert.Equal(t, []int{204, 8077}, reported)
+}
+
+func TestInstallationWatcherWatch(t *testing.T) {
+	var mu sync.Mutex
+	street := "Mikołajska"
+	w := InstallationWatcher{
+		Client: Client{
+			Key: "x1234x",
+			HttpClient: mockClient{func(req *http.Request) (*http.Response, error) {
+				mu.Lock()
+				defer mu.Unlock()
+				return &http.Response{
+					StatusCode: 200,
+					Body:       readCloser(`{"id": 204, "address": {"street": "` + street + `"}}`),
+				}, nil
+			}},
+		},
+		Installations: []int{204},
+		Interval:      time.Millisecond,
+	}
+	stop := make(chan struct{})
+	changes := w.Watch(stop)
+	time.Sleep(5 * time.Millisecond)
+	mu.Lock()
+	street = "Floriańska"
+	mu.Unlock()
+	change := <-changes
+	assert.Equal(t, AddressChanged, change.Kind)
+	close(stop)
+	for range changes {
+	}
+}
+
+func TestInstallationWatcherStale(t *testing.T) {
+	now := time.Date(2021, 3, 1, 12, 0, 0, 0, time.UTC)
+	measurements := `{"current": {"fromDateTime": "2021-03-01T11:00:00Z", "tillDateTime": "2021-03-01T12:00:00Z", "values": [{"name": "PM25", "value": 10}]}}`
+	w := InstallationWatcher{
+		Client: Client{
+			HttpClient: mockClient{func(req *http.Request) (*http.Response, error) {
+				body := `{"id": 204}`
+				if req.URL.Path == "/v2/measurements/installation" {
+					body = measurements
+				}
+				return &http.Response{StatusCode: 200, Body: readCloser(body)}, nil
+			}},
+		},
+		Installations: []int{204},
+		StaleAfter:    6 * time.Hour,
+		now:           func() time.Time { return now },
+	}
+
+	changes, err := w.Check()
+	assert.NoError(t, err)
+	assert.Empty(t, changes)
+
+	// current window without values, as returned for installations that stopped sending data
+	measurements = `{"current": {"fromDateTime": "2021-03-01T17:00:00Z", "tillDateTime": "2021-03-01T18:00:00Z", "values": []},
+		"history": [{"fromDateTime": "2021-03-01T11:00:00Z", "tillDateTime": "2021-03-01T12:00:00Z", "values": [{"name": "PM25", "value": 10}]}]}`
+	now = now.Add(6 * time.Hour)
+	changes, err = w.Check()
+	assert.NoError(t, err)
+	assert.Empty(t, changes)
+
+	now = now.Add(time.Minute)
+	changes, err = w.Check()
+	assert.NoError(t, err)
+	assert.Equal(t, []InstallationChange{{Kind: InstallationDecommissioned, InstallationId: 204,
+		Previous: Installation{Id: 204}, LastUpdate: time.Date(2021, 3, 1, 12, 0, 0, 0, time.UTC)}}, changes)
+
+	changes, err = w.Check()
+	assert.NoError(t, err)
+	assert.Empty(t, changes)
+
+	measurements = `{"current": {"fromDateTime": "2021-03-02T11:00:00Z", "tillDateTime": "2021-03-02T12:00:00Z", "values": [{"name": "PM25", "value": 10}]}}`
+	now = time.Date(2021, 3, 2, 12, 0, 0, 0, time.UTC)
+	changes, err = w.Check()
+	assert.NoError(t, err)
+	assert.Equal(t, []InstallationChange{{Kind: InstallationReactivated, InstallationId: 204,
+		Previous: Installation{Id: 204}, Current: Installation{Id: 204}, LastUpdate: now}}, changes)
+
+	changes, err = w.Check()
+	assert.NoError(t, err)
+	assert.Empty(t, changes)
+}
+
+func TestInstallationWatcherMeasurementsError(t *testing.T) {
+	installation := `{"id": 204, "location": {"latitude": 50.062006, "longitude": 19.940984}}`
+	failMeasurements := false
+	w := InstallationWatcher{
+		Client: Client{
+			HttpClient: mockClient{func(req *http.Request) (*http.Response, error) {
+				if req.URL.Path == "/v2/measurements/installation" {
+					if failMeasurements {
+						return nil, errors.New("connection reset")
+					}
+					return &http.Response{StatusCode: 200, Body: readCloser(`{"current": {"fromDateTime": "2021-03-01T11:00:00Z", "tillDateTime": "2021-03-01T12:00:00Z", "values": [{"name": "PM25", "value": 10}]}}`)}, nil
+				}
+				return &http.Response{StatusCode: 200, Body: readCloser(installation)}, nil
+			}},
+		},
+		Installations: []int{204},
+		StaleAfter:    6 * time.Hour,
+		now:           func() time.Time { return time.Date(2021, 3, 1, 12, 0, 0, 0, time.UTC) },
+	}
+	changes, err := w.Check()
+	assert.NoError(t, err)
+	assert.Empty(t, changes)
+
+	installation = `{"id": 204, "location": {"latitude": 50.072006, "longitude": 19.940984}}`
+	failMeasurements = true
+	changes, err = w.Check()
+	assert.Error(t, err)
+	assert.Empty(t, changes)
+
+	failMeasurements = false
+	changes, err = w.Check()
+	assert.NoError(t, err)
+	assert.Len(t, changes, 1)
+	assert.Equal(t, InstallationMoved, changes[0].Kind)
+	assert.Equal(t, 50.062006, changes[0].Previous.Location.Latitude)
+}
+
+func TestInstallationWatcherReactivated(t *testing.T) {
+	found := true
+	w := InstallationWatcher{
+		Client: Client{
+			HttpClient: mockClient{func(req *http.Request) (*http.Response, error) {
+				if !found {
+					return &http.Response{StatusCode: 404, Body: readCloser("not found")}, nil
+				}
+				return &http.Response{StatusCode: 200, Body: readCloser(`{"id": 204, "address": {"street": "Mikołajska"}}`)}, nil
+			}},
+		},
+		Installations: []int{204},
+		Archive:       &InstallationArchive{},
+	}
+	changes, err := w.Check()
+	assert.NoError(t, err)
+	assert.Empty(t, changes)
+
+	found = false
+	changes, err = w.Check()
+	assert.NoError(t, err)
+	assert.Len(t, changes, 1)
+	assert.Equal(t, InstallationDecommissioned, changes[0].Kind)
+	assert.Equal(t, "Mikołajska", changes[0].Previous.Address.Street)
+	changes, err = w.Check()
+	assert.NoError(t, err)
+	assert.Empty(t, changes)
+
+	found = true
+	changes, err = w.Check()
+	assert.NoError(t, err)
+	assert.Len(t, changes, 1)
+	assert.Equal(t, InstallationReactivated, changes[0].Kind)
+	assert.Equal(t, "Mikołajska", changes[0].Current.Address.Street)
+	assert.Len(t, w.Archive.Versions(204), 1)
+}
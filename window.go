@@ -0,0 +1,88 @@
+package airly
+
+import (
+	"sort"
+	"time"
+)
+
+// Window is time range of measurement, Start is inclusive and End exclusive
+type Window struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// Window returns time range of measurement
+func (m Measurement) Window() Window {
+	return Window{Start: m.FromDateTime, End: m.TillDateTime}
+}
+
+// Duration of window
+func (w Window) Duration() time.Duration {
+	return w.End.Sub(w.Start)
+}
+
+// Contains returns true if t is within window
+func (w Window) Contains(t time.Time) bool {
+	return !t.Before(w.Start) && t.Before(w.End)
+}
+
+// Overlaps returns true if windows have common part, windows ending when other starts don't overlap
+func (w Window) Overlaps(o Window) bool {
+	return w.Start.Before(o.End) && o.Start.Before(w.End)
+}
+
+// Before orders windows by start, then by end
+func (w Window) Before(o Window) bool {
+	if w.Start.Equal(o.Start) {
+		return w.End.Before(o.End)
+	}
+	return w.Start.Before(o.Start)
+}
+
+// SortByWindow sorts measurements chronologically in place, see Window.Before
+func SortByWindow(measurements []Measurement) {
+	sort.SliceStable(measurements, func(i, j int) bool {
+		return measurements[i].Window().Before(measurements[j].Window())
+	})
+}
+
+// chronological returns indexes of measurements in chronological order
+func chronological(measurements []Measurement) []int {
+	order := make([]int, len(measurements))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return measurements[order[i]].Window().Before(measurements[order[j]].Window())
+	})
+	return order
+}
+
+// Overlaps returns pairs of indexes of overlapping measurements, the first index of each pair is the one starting
+// earlier. Measurements don't have to be sorted
+func Overlaps(measurements []Measurement) [][2]int {
+	var overlaps [][2]int
+	order := chronological(measurements)
+	for i, a := range order {
+		for _, b := range order[i+1:] {
+			if !measurements[b].FromDateTime.Before(measurements[a].TillDateTime) {
+				break
+			}
+			if measurements[a].Window().Overlaps(measurements[b].Window()) {
+				overlaps = append(overlaps, [2]int{a, b})
+			}
+		}
+	}
+	return overlaps
+}
+
+// EachWindow calls fn for measurements in chronological order without modifying the slice, iteration stops at the
+// first error, which is returned
+func EachWindow(measurements []Measurement, fn func(w Window, m Measurement) error) error {
+	for _, i := range chronological(measurements) {
+		if err := fn(measurements[i].Window(), measurements[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
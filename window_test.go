@@ -0,0 +1,53 @@
+package airly
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestWindow(t *testing.T) {
+	h := func(hour int) time.Time {
+		return time.Date(2021, 3, 1, hour, 0, 0, 0, time.UTC)
+	}
+	w := window(h(10), PM25, 1).Window()
+	assert.Equal(t, Window{Start: h(10), End: h(11)}, w)
+	assert.Equal(t, time.Hour, w.Duration())
+	assert.True(t, w.Contains(h(10)))
+	assert.False(t, w.Contains(h(11)))
+	assert.True(t, w.Overlaps(Window{Start: h(10).Add(30 * time.Minute), End: h(12)}))
+	assert.False(t, w.Overlaps(Window{Start: h(11), End: h(12)}))
+	assert.True(t, w.Before(Window{Start: h(10), End: h(12)}))
+	assert.False(t, w.Before(w))
+}
+
+func TestSortByWindow(t *testing.T) {
+	h := func(hour int) time.Time {
+		return time.Date(2021, 3, 1, hour, 0, 0, 0, time.UTC)
+	}
+	day := Measurement{FromDateTime: h(0), TillDateTime: h(24)}
+	history := []Measurement{window(h(12), PM25, 3), window(h(10), PM25, 1), day, window(h(11), PM25, 2)}
+
+	assert.Equal(t, [][2]int{{2, 1}, {2, 3}, {2, 0}}, Overlaps(history))
+
+	var values []float64
+	err := EachWindow(history, func(w Window, m Measurement) error {
+		if w.Duration() > time.Hour {
+			return nil
+		}
+		v, _ := m.Value(PM25)
+		values = append(values, v)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{1, 2, 3}, values)
+	assert.Equal(t, day, history[2], "EachWindow must not modify slice")
+
+	stop := errors.New("stop")
+	assert.Equal(t, stop, EachWindow(history, func(Window, Measurement) error { return stop }))
+
+	SortByWindow(history)
+	assert.Equal(t, []Measurement{day, window(h(10), PM25, 1), window(h(11), PM25, 2), window(h(12), PM25, 3)}, history)
+	assert.Empty(t, Overlaps(history[1:]))
+}